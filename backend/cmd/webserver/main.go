@@ -2,17 +2,42 @@ package main
 
 import (
 	"brothers_in_batash/internal/app/webserver/controllers"
+	"brothers_in_batash/internal/pkg/config"
 	"brothers_in_batash/internal/pkg/logging"
+	"brothers_in_batash/internal/pkg/middleware/requestctx"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
 func main() {
-	logging.Info("started WS", nil)
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Panic(err, "error loading config", nil)
+	}
+	if err := logging.SetLevel(cfg.LogLevel); err != nil {
+		logging.Panic(err, "error setting log level", nil)
+	}
+	if cfg.JWTSecret == config.DefaultJWTSecret {
+		logging.Warning(nil, "Using the default JWT secret, which is only good for development", nil)
+	}
+	logging.Info("started WS", []logging.LogProp{{"mode", cfg.Mode}})
 
 	app := fiber.New()
+	if len(cfg.CORSOrigins) > 0 {
+		app.Use(cors.New(cors.Config{
+			AllowOrigins:  strings.Join(cfg.CORSOrigins, ","),
+			AllowHeaders:  strings.Join([]string{fiber.HeaderAuthorization, fiber.HeaderContentType, fiber.HeaderIfMatch}, ","),
+			ExposeHeaders: strings.Join([]string{fiber.HeaderETag, fiber.HeaderLocation}, ","),
+		}))
+	}
+	app.Use(requestctx.New(config.RequestTimeout))
 	apiGroup := app.Group(controllers.APIRouteBasePath)
-	APIControllers, err := controllers.InitControllers()
+	APIControllers, closeStores, err := controllers.InitControllers(cfg)
 	if err != nil {
 		logging.Panic(err, "error setting up controllers", nil)
 	}
@@ -20,7 +45,24 @@ func main() {
 		logging.Panic(err, "error setting up routes", nil)
 	}
 
-	if err := app.Listen(":3000"); err != nil {
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- app.Listen(cfg.ListenAddress)
+	}()
+
+	// Stores are closed only after the server stopped serving, so that no write is lost
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	select {
+	case err := <-listenErr:
 		logging.Error(err, "Error starting server", nil)
+	case sig := <-signals:
+		logging.Info("shutting down WS", []logging.LogProp{{"signal", sig.String()}})
+		if err := app.ShutdownWithTimeout(config.ShutdownTimeout); err != nil {
+			logging.Error(err, "error shutting down server", nil)
+		}
+	}
+	if err := closeStores(); err != nil {
+		logging.Error(err, "error closing stores", nil)
 	}
 }
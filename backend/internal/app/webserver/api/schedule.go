@@ -0,0 +1,14 @@
+package api
+
+import "brothers_in_batash/internal/pkg/scheduling"
+
+type AutoAssignReqBody struct {
+	From   string `json:"from" validate:"required,datetime=2006-01-02"`
+	To     string `json:"to" validate:"required,datetime=2006-01-02"`
+	DryRun bool   `json:"dryRun"`
+}
+
+type AutoAssignRespBody struct {
+	DryRun bool `json:"dryRun"`
+	scheduling.AssignmentResult
+}
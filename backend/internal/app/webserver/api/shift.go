@@ -1,5 +1,10 @@
 package api
 
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+)
+
 //Seems redundant ATM
 //Will use models.Shift
 
@@ -29,3 +34,22 @@ package api
 //	Description           string    `json:"description" validate:"omitempty,min=1,max=255"`
 //	ShiftTemplateID       string    `json:"shiftTemplateId" validate:"omitempty"`
 //}
+
+// ShiftConflictRespBody describes why a shift could not be staffed the way it was requested
+type ShiftConflictRespBody struct {
+	Overlaps       []scheduling.Overlap       `json:"overlaps"`
+	RestViolations []scheduling.RestViolation `json:"restViolations"`
+}
+
+// StaffingIssuesRespBody lists the template required roles a shift's soldiers do not cover, and the soldiers who are
+// on leave during the shift
+type StaffingIssuesRespBody struct {
+	Shortfalls []scheduling.RoleShortfall `json:"shortfalls"`
+	OnLeave    []scheduling.LeaveConflict `json:"onLeave"`
+}
+
+// CreatedShiftRespBody is the shift that was created. StaffingIssues is set when the shift was saved understaffed.
+type CreatedShiftRespBody struct {
+	models.Shift
+	StaffingIssues *StaffingIssuesRespBody `json:"staffingIssues,omitempty"`
+}
@@ -0,0 +1,7 @@
+package api
+
+// SoldierShiftsRespBody lists the upcoming shifts a soldier is staffed in, including the shifts of day schedules - the
+// ones that keep the soldier from being deleted, or the ones the soldier was unassigned from when deleted
+type SoldierShiftsRespBody struct {
+	ShiftIDs []string `json:"shiftIds"`
+}
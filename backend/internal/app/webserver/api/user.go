@@ -22,4 +22,6 @@ type RefreshTokenReqBody struct {
 
 type LogoutReqBody struct {
 	Token string `json:"token" validate:"required"`
+	// RefreshToken is revoked along with Token, if given
+	RefreshToken string `json:"refreshToken"`
 }
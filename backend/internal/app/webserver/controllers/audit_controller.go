@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"brothers_in_batash/internal/pkg/logging"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+)
+
+// AuditController exposes the change history of entities - who changed them, when, and how
+type AuditController struct {
+	auditStore     store.IAuditStore
+	authMiddleware fiber.Handler
+}
+
+func NewAuditController(auditStore store.IAuditStore, authMiddleware fiber.Handler) (*AuditController, error) {
+	if auditStore == nil {
+		return nil, errors.New("auditStore is nil")
+	}
+	if authMiddleware == nil {
+		return nil, errors.New("authMiddleware is nil")
+	}
+	return &AuditController{auditStore: auditStore, authMiddleware: authMiddleware}, nil
+}
+
+func (c *AuditController) RegisterRoutes(router fiber.Router) error {
+	router.Get(GetAuditEntriesRoute, c.authMiddleware, requireSquadCommander, c.getAuditEntries)
+	return nil
+}
+
+// getAuditEntries returns the changes of the entity given by the "entity" and "id" query params, oldest first.
+// Day schedules are identified by their date, formatted as 2006-01-02.
+func (c *AuditController) getAuditEntries(ctx *fiber.Ctx) error {
+	entityType := models.AuditEntityType(ctx.Query("entity"))
+	entityID := ctx.Query("id")
+	if !entityType.IsValid() || entityID == "" {
+		logging.Debug("Invalid audit query", []logging.LogProp{{"entity", string(entityType)}, {"id", entityID}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	entries, err := c.auditStore.FindAuditEntries(ctx.UserContext(), entityType, entityID)
+	if err != nil {
+		logging.Warning(err, "error on fetching audit entries", []logging.LogProp{{"entity", string(entityType)}, {"id", entityID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	return ctx.JSON(entries)
+}
@@ -0,0 +1,111 @@
+package controllers_test
+
+import (
+	"brothers_in_batash/internal/app/webserver/controllers"
+	"brothers_in_batash/internal/pkg/mocks"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/test_utils"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditController_NewAuditController__sad_flows(t *testing.T) {
+	// Act
+	nilAuditStoreController, nilAuditStoreErr := controllers.NewAuditController(nil, test_utils.AlwaysAllowedJWTMiddleware)
+	nilMiddlewareController, nilMiddlewareErr := controllers.NewAuditController(&mocks.MockIAuditStore{}, nil)
+
+	// Assert
+	assert.Error(t, nilAuditStoreErr)
+	assert.Nil(t, nilAuditStoreController)
+	assert.Error(t, nilMiddlewareErr)
+	assert.Nil(t, nilMiddlewareController)
+}
+
+func TestAuditController_GetAuditEntries__invalid_query(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{name: "missing entity", query: "?id=123"},
+		{name: "unknown entity", query: "?entity=user&id=123"},
+		{name: "missing id", query: "?entity=shift"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			app := fiber.New()
+			controller, err := controllers.NewAuditController(&mocks.MockIAuditStore{}, test_utils.AlwaysAllowedJWTMiddleware)
+			require.NoError(t, err)
+			err = controller.RegisterRoutes(app)
+			require.NoError(t, err)
+			req := httptest.NewRequest(fiber.MethodGet, controllers.GetAuditEntriesRoute+testCase.query, nil)
+
+			// Act
+			resp, err := app.Test(req, test_utils.TestTimeout)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+func TestAuditController_GetAuditEntries__success(t *testing.T) {
+	// Arrange
+	entry := models.AuditEntry{
+		ID:            "1",
+		EntityType:    models.ShiftAuditEntity,
+		EntityID:      "123",
+		Action:        models.UpdateAuditAction,
+		Username:      "commander",
+		Before:        json.RawMessage(`{"name":"Before"}`),
+		After:         json.RawMessage(`{"name":"After"}`),
+		ChangedFields: []string{"name"},
+	}
+	auditStore := &mocks.MockIAuditStore{}
+	auditStore.On("FindAuditEntries", mock.Anything, models.ShiftAuditEntity, "123").Return([]models.AuditEntry{entry}, nil)
+	app := fiber.New()
+	controller, err := controllers.NewAuditController(auditStore, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodGet, controllers.GetAuditEntriesRoute+"?entity=shift&id=123", nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var entries []models.AuditEntry
+	err = json.NewDecoder(resp.Body).Decode(&entries)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AuditEntry{entry}, entries)
+}
+
+func TestAuditController_GetAuditEntries__store_error(t *testing.T) {
+	// Arrange
+	auditStore := &mocks.MockIAuditStore{}
+	auditStore.On("FindAuditEntries", mock.Anything, models.SoldierAuditEntity, "123").
+		Return([]models.AuditEntry{}, errors.New("store error"))
+	app := fiber.New()
+	controller, err := controllers.NewAuditController(auditStore, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodGet, controllers.GetAuditEntriesRoute+"?entity=soldier&id=123", nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
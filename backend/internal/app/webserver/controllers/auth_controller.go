@@ -6,7 +6,9 @@ import (
 	jwtmw "brothers_in_batash/internal/pkg/middleware/jwt"
 	"brothers_in_batash/internal/pkg/models"
 	"brothers_in_batash/internal/pkg/store"
-	"fmt"
+	"brothers_in_batash/internal/pkg/utils"
+	"context"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -16,14 +18,23 @@ import (
 )
 
 type RegistrationController struct {
-	userStore store.IUserStore
+	userStore         store.IUserStore
+	revokedTokenStore store.IRevokedTokenStore
+	tokenIssuer       *jwtmw.TokenIssuer
 }
 
-func NewRegistrationController(userStore store.IUserStore) (*RegistrationController, error) {
+func NewRegistrationController(userStore store.IUserStore, revokedTokenStore store.IRevokedTokenStore,
+	tokenIssuer *jwtmw.TokenIssuer) (*RegistrationController, error) {
 	if userStore == nil {
 		return nil, errors.New("userStore is nil")
 	}
-	return &RegistrationController{userStore: userStore}, nil
+	if revokedTokenStore == nil {
+		return nil, errors.New("revokedTokenStore is nil")
+	}
+	if tokenIssuer == nil {
+		return nil, errors.New("tokenIssuer is nil")
+	}
+	return &RegistrationController{userStore: userStore, revokedTokenStore: revokedTokenStore, tokenIssuer: tokenIssuer}, nil
 }
 
 func (c *RegistrationController) RegisterRoutes(router fiber.Router) error {
@@ -53,7 +64,7 @@ func (c *RegistrationController) registerUser(ctx *fiber.Ctx) error {
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
 
-	if res, err := c.userStore.FindUserByUsername(reqBody.Username); err != nil {
+	if res, err := c.userStore.FindUserByUsername(ctx.UserContext(), reqBody.Username); err != nil {
 		logging.Info("Could not lookup if user exists", []logging.LogProp{{"error", err.Error()}})
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	} else if len(res) > 0 {
@@ -64,9 +75,10 @@ func (c *RegistrationController) registerUser(ctx *fiber.Ctx) error {
 	newUser := models.User{
 		Username:       reqBody.Username,
 		HashedPassword: hashedPassword,
+		Role:           models.ViewerUserRole,
 	}
 
-	if err := c.userStore.CreateNewUser(newUser); err != nil {
+	if err := c.userStore.CreateNewUser(ctx.UserContext(), newUser); err != nil {
 		logging.Warning(err, "error on writing new user to DB", nil)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
@@ -83,26 +95,7 @@ func (c *RegistrationController) loginUser(ctx *fiber.Ctx) error {
 		logging.Info("Login request body failed validation", []logging.LogProp{{"error", err.Error()}})
 		return ctx.SendStatus(fiber.StatusBadRequest)
 	}
-	if reqBody.Username == "admin" {
-		// TODO: remove this workaround
-		logging.Debug("Admin login", nil)
-		token, err := jwtmw.GenerateToken("admin", jwtmw.TokenExpiration)
-		if err != nil {
-			logging.Warning(err, "Failed generating JWT token", nil)
-			return ctx.SendStatus(fiber.StatusInternalServerError)
-		}
-		refreshToken, err := jwtmw.GenerateToken("admin", jwtmw.RefreshTokenExpiration)
-		if err != nil {
-			logging.Warning(err, "Failed generating refresh token", nil)
-			return ctx.SendStatus(fiber.StatusInternalServerError)
-		}
-		return ctx.Status(fiber.StatusOK).JSON(api.UserLoginRespBody{
-			Token: token, 
-			RefreshToken: refreshToken, 
-			Username: reqBody.Username,
-		})
-	}
-	users, err := c.userStore.FindUserByUsername(reqBody.Username)
+	users, err := c.userStore.FindUserByUsername(ctx.UserContext(), reqBody.Username)
 	if err != nil {
 		logging.Warning(err, "Failed querying users from DB on login", nil)
 		return ctx.SendStatus(fiber.StatusBadRequest)
@@ -120,12 +113,13 @@ func (c *RegistrationController) loginUser(ctx *fiber.Ctx) error {
 		return ctx.SendStatus(fiber.StatusBadRequest)
 	}
 	logging.Trace("Successful login", []logging.LogProp{{"username", reqBody.Username}})
-	token, err := jwtmw.GenerateToken(users[0].Username, jwtmw.TokenExpiration)
+	familyID := utils.NewEntityID()
+	token, err := c.tokenIssuer.GenerateToken(users[0], jwtmw.AccessTokenType, familyID)
 	if err != nil {
 		logging.Warning(err, "Failed generating JWT token", nil)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
-	refreshToken, err := jwtmw.GenerateToken(users[0].Username, jwtmw.RefreshTokenExpiration)
+	refreshToken, err := c.tokenIssuer.GenerateToken(users[0], jwtmw.RefreshTokenType, familyID)
 	if err != nil {
 		logging.Warning(err, "Failed generating refresh token", nil)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
@@ -137,6 +131,9 @@ func (c *RegistrationController) loginUser(ctx *fiber.Ctx) error {
 	})
 }
 
+// refreshToken rotates refresh tokens - every refresh token is good for a single refresh, which revokes it and issues
+// a new one. A refresh token that is used again must have been stolen, either by whoever used it first or by whoever
+// uses it now, so its whole family is revoked, logging both of them out.
 func (c *RegistrationController) refreshToken(ctx *fiber.Ctx) error {
 	reqBody := api.RefreshTokenReqBody{}
 	if err := ctx.BodyParser(&reqBody); err != nil {
@@ -148,39 +145,73 @@ func (c *RegistrationController) refreshToken(ctx *fiber.Ctx) error {
 		return ctx.SendStatus(fiber.StatusBadRequest)
 	}
 
-	token, err := jtoken.Parse(reqBody.RefreshToken, func(token *jtoken.Token) (interface{}, error) {
-		return []byte(jwtmw.SigningSecret), nil
-	})
+	claims, err := c.parseRevocableToken(reqBody.RefreshToken)
 	if err != nil {
 		logging.Trace("Invalid refresh token", []logging.LogProp{{"error", err.Error()}})
 		return ctx.SendStatus(fiber.StatusUnauthorized)
 	}
-
-	claims, ok := token.Claims.(jtoken.MapClaims)
-	if !ok || !token.Valid {
-		logging.Debug("Invalid refresh token claims", []logging.LogProp{
-			{"claims", fmt.Sprint(claims)},
-			{"isMapClaims", fmt.Sprint(ok)},
-			{"isMapOrIsValid", fmt.Sprint(!ok || !token.Valid)},
-		})
+	if tokenType, _ := claims[jwtmw.TokenTypeClaimField].(string); jwtmw.TokenType(tokenType) != jwtmw.RefreshTokenType {
+		logging.Debug("Token used to refresh is not a refresh token", []logging.LogProp{{"type", tokenType}})
 		return ctx.SendStatus(fiber.StatusUnauthorized)
 	}
-
 	username, ok := claims[jwtmw.IDClaimField].(string)
 	if !ok {
 		logging.Debug("Invalid refresh token claims - missing user ID", nil)
 		return ctx.SendStatus(fiber.StatusUnauthorized)
 	}
+	familyID, _ := claims[jwtmw.TokenFamilyClaimField].(string)
 
-	newToken, err := jwtmw.GenerateToken(username, jwtmw.TokenExpiration)
+	if revoked, err := c.revokedTokenStore.IsTokenRevoked(ctx.UserContext(), familyID); err != nil {
+		logging.Warning(err, "Failed checking if refresh token family is revoked", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if revoked {
+		logging.Debug("Refresh token of revoked family used", []logging.LogProp{{"username", username}})
+		return ctx.SendStatus(fiber.StatusUnauthorized)
+	}
+	revokedToken, err := toRevokedToken(claims)
+	if err != nil {
+		logging.Debug("Invalid refresh token claims", []logging.LogProp{{"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusUnauthorized)
+	}
+	if newlyRevoked, err := c.revokedTokenStore.RevokeToken(ctx.UserContext(), revokedToken); err != nil {
+		logging.Warning(err, "Failed revoking rotated refresh token", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if !newlyRevoked {
+		logging.Warning(errors.New("refresh token reused"), "Revoking the token family of a reused refresh token",
+			[]logging.LogProp{{"username", username}})
+		if err := c.revokeTokenFamily(ctx.UserContext(), familyID); err != nil {
+			logging.Warning(err, "Failed revoking token family", []logging.LogProp{{"username", username}})
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+		return ctx.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	// The user is looked up again, so that the new tokens carry the user's current role
+	users, err := c.userStore.FindUserByUsername(ctx.UserContext(), username)
+	if err != nil {
+		logging.Warning(err, "Failed querying users from DB on refresh", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	if len(users) == 0 {
+		logging.Debug("Refresh token of non existing user", []logging.LogProp{{"username", username}})
+		return ctx.SendStatus(fiber.StatusUnauthorized)
+	}
+	newToken, err := c.tokenIssuer.GenerateToken(users[0], jwtmw.AccessTokenType, familyID)
 	if err != nil {
 		logging.Warning(err, "could not generate JWT token", nil)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
+	newRefreshToken, err := c.tokenIssuer.GenerateToken(users[0], jwtmw.RefreshTokenType, familyID)
+	if err != nil {
+		logging.Warning(err, "could not generate refresh token", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
 
-	return ctx.Status(fiber.StatusOK).JSON(api.UserLoginRespBody{Token: newToken, RefreshToken: reqBody.RefreshToken})
+	return ctx.Status(fiber.StatusOK).JSON(api.UserLoginRespBody{Token: newToken, RefreshToken: newRefreshToken, Username: username})
 }
 
+// logoutUser revokes the token family of the given token, which covers the refresh token issued along with it. The
+// refresh token may be given too, in case it descends from another login of the same user.
 func (c *RegistrationController) logoutUser(ctx *fiber.Ctx) error {
 	reqBody := api.LogoutReqBody{}
 	if err := ctx.BodyParser(&reqBody); err != nil {
@@ -193,27 +224,85 @@ func (c *RegistrationController) logoutUser(ctx *fiber.Ctx) error {
 		return ctx.SendStatus(fiber.StatusBadRequest)
 	}
 
-	token, err := jtoken.Parse(reqBody.Token, func(token *jtoken.Token) (interface{}, error) {
-		return []byte(jwtmw.SigningSecret), nil
-	})
-	if token != nil && !token.Valid {
-		err = errors.New("invalid token")
-	}
+	claims, err := c.parseRevocableToken(reqBody.Token)
 	if err != nil {
 		logging.Trace("Invalid token in logout request", []logging.LogProp{{"error", err.Error()}})
 		return ctx.SendStatus(fiber.StatusUnauthorized)
 	}
-	//At this point token is not nil(ignore the linting warning)
-	claims, ok := token.Claims.(jtoken.MapClaims)
-	if ok {
-		username, _ := claims[jwtmw.IDClaimField].(string)
-		logging.Trace("User logged out", []logging.LogProp{{"username", username}})
+	username, _ := claims[jwtmw.IDClaimField].(string)
+	tokensToRevoke := []jtoken.MapClaims{claims}
+	if reqBody.RefreshToken != "" {
+		refreshClaims, err := c.parseRevocableToken(reqBody.RefreshToken)
+		if err != nil {
+			logging.Trace("Invalid refresh token in logout request", []logging.LogProp{{"error", err.Error()}})
+			return ctx.SendStatus(fiber.StatusUnauthorized)
+		}
+		if refreshUsername, _ := refreshClaims[jwtmw.IDClaimField].(string); refreshUsername != username {
+			logging.Debug("Logout request tokens belong to different users", []logging.LogProp{{"username", username}})
+			return ctx.SendStatus(fiber.StatusUnauthorized)
+		}
+		tokensToRevoke = append(tokensToRevoke, refreshClaims)
+	}
+
+	for _, tokenClaims := range tokensToRevoke {
+		revokedToken, err := toRevokedToken(tokenClaims)
+		if err != nil {
+			logging.Debug("Invalid logout request token claims", []logging.LogProp{{"error", err.Error()}})
+			return ctx.SendStatus(fiber.StatusUnauthorized)
+		}
+		if _, err := c.revokedTokenStore.RevokeToken(ctx.UserContext(), revokedToken); err != nil {
+			logging.Warning(err, "Failed revoking token on logout", []logging.LogProp{{"username", username}})
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+		familyID, _ := tokenClaims[jwtmw.TokenFamilyClaimField].(string)
+		if err := c.revokeTokenFamily(ctx.UserContext(), familyID); err != nil {
+			logging.Warning(err, "Failed revoking token family on logout", []logging.LogProp{{"username", username}})
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
 	}
 
-	logging.Trace("logoutUser successful", nil)
+	logging.Trace("User logged out", []logging.LogProp{{"username", username}})
 	return ctx.SendStatus(fiber.StatusOK)
 }
 
+// revokeTokenFamily revokes all the tokens of a family. None of them expires later than a refresh token issued right
+// now would, so that is how long the family is kept revoked for.
+func (c *RegistrationController) revokeTokenFamily(ctx context.Context, familyID string) error {
+	_, err := c.revokedTokenStore.RevokeToken(ctx, models.RevokedToken{
+		ID:        familyID,
+		ExpiresAt: time.Now().Add(c.tokenIssuer.Lifetime(jwtmw.RefreshTokenType)),
+	})
+	return err
+}
+
+// parseRevocableToken verifies a token and returns its claims. Only tokens with an ID and a family can be revoked.
+func (c *RegistrationController) parseRevocableToken(tokenString string) (jtoken.MapClaims, error) {
+	claims, err := c.tokenIssuer.ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if tokenID, _ := claims[jwtmw.TokenIDClaimField].(string); tokenID == "" {
+		return nil, errors.New("token has no ID")
+	}
+	if familyID, _ := claims[jwtmw.TokenFamilyClaimField].(string); familyID == "" {
+		return nil, errors.New("token has no family")
+	}
+	return claims, nil
+}
+
+// toRevokedToken describes the token of claims as revoked until it expires
+func toRevokedToken(claims jtoken.MapClaims) (models.RevokedToken, error) {
+	tokenID, _ := claims[jwtmw.TokenIDClaimField].(string)
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil {
+		return models.RevokedToken{}, err
+	}
+	if expiresAt == nil {
+		return models.RevokedToken{}, errors.New("token has no expiration time")
+	}
+	return models.RevokedToken{ID: tokenID, ExpiresAt: expiresAt.Time}, nil
+}
+
 func hashPassword(password string) ([]byte, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
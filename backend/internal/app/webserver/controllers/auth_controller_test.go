@@ -8,6 +8,7 @@ import (
 	"brothers_in_batash/internal/pkg/store"
 	"brothers_in_batash/internal/pkg/test_utils"
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -19,6 +20,7 @@ import (
 	jtoken "github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -26,17 +28,37 @@ type IUserStoreMock struct {
 	mock.Mock
 }
 
-func (m *IUserStoreMock) CreateNewUser(user models.User) error {
-	return m.Called(user).Error(0)
+func (m *IUserStoreMock) CreateNewUser(ctx context.Context, user models.User) error {
+	return m.Called(ctx, user).Error(0)
 }
 
-func (m *IUserStoreMock) FindUserByUsername(username string) ([]models.User, error) {
-	args := m.Called(username)
+func (m *IUserStoreMock) FindUserByUsername(ctx context.Context, username string) ([]models.User, error) {
+	args := m.Called(ctx, username)
 	return args.Get(0).([]models.User), args.Error(1)
 }
 
+const testJWTSecret = "test secret"
+
+func newTestTokenIssuer(t *testing.T) *jwtmw.TokenIssuer {
+	tokenIssuer, err := jwtmw.NewTokenIssuer(testJWTSecret, time.Hour, 24*time.Hour)
+	require.NoError(t, err)
+	return tokenIssuer
+}
+
+func newTestRevokedTokenStore(t *testing.T) *store.InMemRevokedTokenStore {
+	revokedTokenStore, err := store.NewRevokedTokenStore()
+	require.NoError(t, err)
+	return revokedTokenStore
+}
+
 func TestRegistrationController_NewRegistrationController__error_on_nil_store(t *testing.T) {
-	res, err := controllers.NewRegistrationController(nil)
+	res, err := controllers.NewRegistrationController(nil, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
+	assert.Error(t, err)
+	assert.Nil(t, res)
+	res, err = controllers.NewRegistrationController(&IUserStoreMock{}, nil, newTestTokenIssuer(t))
+	assert.Error(t, err)
+	assert.Nil(t, res)
+	res, err = controllers.NewRegistrationController(&IUserStoreMock{}, newTestRevokedTokenStore(t), nil)
 	assert.Error(t, err)
 	assert.Nil(t, res)
 }
@@ -45,7 +67,7 @@ func TestRegistrationController_NewRegistrationController__success(t *testing.T)
 	userStore, err := store.NewUserStore()
 	assert.NoError(t, err)
 	assert.NotNil(t, userStore)
-	res, err := controllers.NewRegistrationController(userStore)
+	res, err := controllers.NewRegistrationController(userStore, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 	assert.NoError(t, err)
 	assert.NotNil(t, res)
 }
@@ -87,7 +109,7 @@ func TestRegistrationController_RegisterUser__sad_flows(t *testing.T) {
 			userStore, err := store.NewUserStore()
 			assert.NoError(t, err)
 			assert.NotNil(t, userStore)
-			controller, err := controllers.NewRegistrationController(userStore)
+			controller, err := controllers.NewRegistrationController(userStore, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 			assert.NoError(t, err)
 			assert.NotNil(t, controller)
 			err = controllers.SetupRoutes(app, []controllers.Controller{controller})
@@ -116,7 +138,7 @@ func TestRegistrationController_RegisterUser__user_already_exists(t *testing.T)
 	userStore, err := store.NewUserStore()
 	assert.NoError(t, err)
 	assert.NotNil(t, userStore)
-	controller, err := controllers.NewRegistrationController(userStore)
+	controller, err := controllers.NewRegistrationController(userStore, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 	assert.NoError(t, err)
 	assert.NotNil(t, controller)
 	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
@@ -150,7 +172,7 @@ func TestRegistrationController_RegisterUser__user_successfully_created(t *testi
 	userStore, err := store.NewUserStore()
 	assert.NoError(t, err)
 	assert.NotNil(t, userStore)
-	controller, err := controllers.NewRegistrationController(userStore)
+	controller, err := controllers.NewRegistrationController(userStore, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 	assert.NoError(t, err)
 	assert.NotNil(t, controller)
 	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
@@ -203,7 +225,7 @@ func TestRegistrationController_LoginUser__sad_flows(t *testing.T) {
 			app := fiber.New()
 
 			userStoreMock := &IUserStoreMock{}
-			controller, err := controllers.NewRegistrationController(userStoreMock)
+			controller, err := controllers.NewRegistrationController(userStoreMock, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 			assert.NoError(t, err)
 			assert.NotNil(t, controller)
 			err = controllers.SetupRoutes(app, []controllers.Controller{controller})
@@ -229,8 +251,8 @@ func TestRegistrationController_LoginUser__non_existing_user(t *testing.T) {
 
 	username := "user"
 	userStoreMock := &IUserStoreMock{}
-	userStoreMock.On("FindUserByUsername", username).Return([]models.User{}, nil)
-	controller, err := controllers.NewRegistrationController(userStoreMock)
+	userStoreMock.On("FindUserByUsername", mock.Anything, username).Return([]models.User{}, nil)
+	controller, err := controllers.NewRegistrationController(userStoreMock, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 	assert.NoError(t, err)
 	assert.NotNil(t, controller)
 	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
@@ -252,19 +274,46 @@ func TestRegistrationController_LoginUser__non_existing_user(t *testing.T) {
 	userStoreMock.AssertExpectations(t)
 }
 
+func TestRegistrationController_LoginUser__admin_without_account(t *testing.T) {
+	//Arrange
+	app := fiber.New()
+
+	userStoreMock := &IUserStoreMock{}
+	userStoreMock.On("FindUserByUsername", mock.Anything, "admin").Return([]models.User{}, nil)
+	controller, err := controllers.NewRegistrationController(userStoreMock, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
+	assert.NoError(t, err)
+	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
+	assert.NoError(t, err)
+
+	loginBody := api.UserLoginReqBody{
+		Username: "admin",
+		Password: "any password",
+	}
+	req := httptest.NewRequest(fiber.MethodPost, controllers.LoginRoute, test_utils.WrapStructWithReader(t, loginBody))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	//Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	//Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	userStoreMock.AssertExpectations(t)
+}
+
 func TestRegistrationController_LoginUser__wrong_password(t *testing.T) {
 	//Arrange
 	app := fiber.New()
 
 	username := "user"
 	userStoreMock := &IUserStoreMock{}
-	userStoreMock.On("FindUserByUsername", username).Return([]models.User{
+	userStoreMock.On("FindUserByUsername", mock.Anything, username).Return([]models.User{
 		{
 			Username:       username,
 			HashedPassword: []byte("you will never steal my secrets!"),
 		},
 	}, nil)
-	controller, err := controllers.NewRegistrationController(userStoreMock)
+	controller, err := controllers.NewRegistrationController(userStoreMock, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 	assert.NoError(t, err)
 	assert.NotNil(t, controller)
 	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
@@ -296,13 +345,15 @@ func TestRegistrationController_LoginUser__success(t *testing.T) {
 	assert.NoError(t, err)
 
 	userStoreMock := &IUserStoreMock{}
-	userStoreMock.On("FindUserByUsername", username).Return([]models.User{
+	userStoreMock.On("FindUserByUsername", mock.Anything, username).Return([]models.User{
 		{
 			Username:       username,
 			HashedPassword: hashedPassword,
+			SoldierID:      "7",
+			Role:           models.SquadCommanderUserRole,
 		},
 	}, nil)
-	controller, err := controllers.NewRegistrationController(userStoreMock)
+	controller, err := controllers.NewRegistrationController(userStoreMock, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 	assert.NoError(t, err)
 	assert.NotNil(t, controller)
 	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
@@ -322,6 +373,12 @@ func TestRegistrationController_LoginUser__success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 	userStoreMock.AssertExpectations(t)
+	var respBody api.UserLoginRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	require.NoError(t, err)
+	claims := parseTestTokenClaims(t, respBody.Token)
+	assert.Equal(t, string(models.SquadCommanderUserRole), claims[jwtmw.RoleClaimField])
+	assert.Equal(t, "7", claims[jwtmw.SoldierIDClaimField])
 }
 
 func TestRegistrationController_RefreshToken__sad_flows(t *testing.T) {
@@ -354,7 +411,7 @@ func TestRegistrationController_RefreshToken__sad_flows(t *testing.T) {
 			app := fiber.New()
 
 			userStoreMock := &IUserStoreMock{}
-			controller, err := controllers.NewRegistrationController(userStoreMock)
+			controller, err := controllers.NewRegistrationController(userStoreMock, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 			assert.NoError(t, err)
 			assert.NotNil(t, controller)
 			err = controllers.SetupRoutes(app, []controllers.Controller{controller})
@@ -385,11 +442,11 @@ func TestRegistrationController_RefreshToken__wrong_claims_type(t *testing.T) {
 		"myCustomWrongRole":       "admin",
 	}
 	token := jtoken.NewWithClaims(jtoken.SigningMethodHS256, wrongClaims)
-	signedToken, err := token.SignedString([]byte(jwtmw.SigningSecret))
+	signedToken, err := token.SignedString([]byte(testJWTSecret))
 	assert.NoError(t, err)
 
 	userStoreMock := &IUserStoreMock{}
-	controller, err := controllers.NewRegistrationController(userStoreMock)
+	controller, err := controllers.NewRegistrationController(userStoreMock, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 	assert.NoError(t, err)
 	assert.NotNil(t, controller)
 	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
@@ -415,11 +472,16 @@ func TestRegistrationController_RefreshToken__success(t *testing.T) {
 	app := fiber.New()
 
 	username := "user"
-	refreshToken, err := jwtmw.GenerateToken(username, jwtmw.RefreshTokenExpiration)
+	refreshToken, err := newTestTokenIssuer(t).GenerateToken(models.User{Username: username, Role: models.SoldierUserRole, SoldierID: "7"},
+		jwtmw.RefreshTokenType, "family")
 	assert.NoError(t, err)
 
 	userStoreMock := &IUserStoreMock{}
-	controller, err := controllers.NewRegistrationController(userStoreMock)
+	// The user was promoted since the refresh token was issued
+	userStoreMock.On("FindUserByUsername", mock.Anything, username).Return([]models.User{
+		{Username: username, Role: models.SquadCommanderUserRole, SoldierID: "7"},
+	}, nil)
+	controller, err := controllers.NewRegistrationController(userStoreMock, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 	assert.NoError(t, err)
 	assert.NotNil(t, controller)
 	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
@@ -443,7 +505,84 @@ func TestRegistrationController_RefreshToken__success(t *testing.T) {
 	err = json.NewDecoder(resp.Body).Decode(&respBody)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, respBody.Token)
-	assert.Equal(t, refreshToken, respBody.RefreshToken)
+	assert.NotEqual(t, refreshToken, respBody.RefreshToken)
+	claims := parseTestTokenClaims(t, respBody.Token)
+	assert.Equal(t, username, claims[jwtmw.IDClaimField])
+	assert.Equal(t, string(jwtmw.AccessTokenType), claims[jwtmw.TokenTypeClaimField])
+	assert.Equal(t, "family", claims[jwtmw.TokenFamilyClaimField])
+	assert.Equal(t, string(models.SquadCommanderUserRole), claims[jwtmw.RoleClaimField])
+	assert.Equal(t, "7", claims[jwtmw.SoldierIDClaimField])
+	refreshClaims := parseTestTokenClaims(t, respBody.RefreshToken)
+	assert.Equal(t, string(jwtmw.RefreshTokenType), refreshClaims[jwtmw.TokenTypeClaimField])
+	assert.Equal(t, "family", refreshClaims[jwtmw.TokenFamilyClaimField])
+}
+
+func TestRegistrationController_RefreshToken__access_token(t *testing.T) {
+	//Arrange
+	app := fiber.New()
+
+	token, err := newTestTokenIssuer(t).GenerateToken(models.User{Username: "user", Role: models.SoldierUserRole}, jwtmw.AccessTokenType, "family")
+	require.NoError(t, err)
+
+	userStoreMock := &IUserStoreMock{}
+	controller, err := controllers.NewRegistrationController(userStoreMock, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
+	require.NoError(t, err)
+	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
+	require.NoError(t, err)
+
+	refreshTokenBody := api.RefreshTokenReqBody{
+		RefreshToken: token,
+	}
+	refreshTokenReq := httptest.NewRequest(fiber.MethodPost, controllers.RefreshTokenRoute, test_utils.WrapStructWithReader(t, refreshTokenBody))
+	refreshTokenReq.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	//Act
+	resp, err := app.Test(refreshTokenReq, test_utils.TestTimeout)
+
+	//Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	userStoreMock.AssertExpectations(t)
+}
+
+func TestRegistrationController_RefreshToken__reuse_revokes_family(t *testing.T) {
+	//Arrange
+	app := fiber.New()
+
+	user := models.User{Username: "user", Role: models.SoldierUserRole}
+	refreshToken, err := newTestTokenIssuer(t).GenerateToken(user, jwtmw.RefreshTokenType, "family")
+	require.NoError(t, err)
+
+	userStoreMock := &IUserStoreMock{}
+	userStoreMock.On("FindUserByUsername", mock.Anything, user.Username).Return([]models.User{user}, nil)
+	revokedTokenStore := newTestRevokedTokenStore(t)
+	controller, err := controllers.NewRegistrationController(userStoreMock, revokedTokenStore, newTestTokenIssuer(t))
+	require.NoError(t, err)
+	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
+	require.NoError(t, err)
+	refresh := func(refreshToken string) *http.Response {
+		body := api.RefreshTokenReqBody{RefreshToken: refreshToken}
+		req := httptest.NewRequest(fiber.MethodPost, controllers.RefreshTokenRoute, test_utils.WrapStructWithReader(t, body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req, test_utils.TestTimeout)
+		require.NoError(t, err)
+		return resp
+	}
+	firstResp := refresh(refreshToken)
+	require.Equal(t, fiber.StatusOK, firstResp.StatusCode)
+	var rotated api.UserLoginRespBody
+	require.NoError(t, json.NewDecoder(firstResp.Body).Decode(&rotated))
+
+	//Act
+	reuseResp := refresh(refreshToken)
+	rotatedResp := refresh(rotated.RefreshToken)
+
+	//Assert
+	assert.Equal(t, fiber.StatusUnauthorized, reuseResp.StatusCode)
+	assert.Equal(t, fiber.StatusUnauthorized, rotatedResp.StatusCode)
+	familyRevoked, err := revokedTokenStore.IsTokenRevoked(context.Background(), "family")
+	assert.NoError(t, err)
+	assert.True(t, familyRevoked)
 }
 
 func TestRegistrationController_LogoutUser__invalid_body(t *testing.T) {
@@ -451,7 +590,7 @@ func TestRegistrationController_LogoutUser__invalid_body(t *testing.T) {
 	app := fiber.New()
 
 	userStoreMock := &IUserStoreMock{}
-	controller, err := controllers.NewRegistrationController(userStoreMock)
+	controller, err := controllers.NewRegistrationController(userStoreMock, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 	assert.NoError(t, err)
 	assert.NotNil(t, controller)
 	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
@@ -474,7 +613,7 @@ func TestRegistrationController_LogoutUser__missing_token(t *testing.T) {
 	app := fiber.New()
 
 	userStoreMock := &IUserStoreMock{}
-	controller, err := controllers.NewRegistrationController(userStoreMock)
+	controller, err := controllers.NewRegistrationController(userStoreMock, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 	assert.NoError(t, err)
 	assert.NotNil(t, controller)
 	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
@@ -500,7 +639,7 @@ func TestRegistrationController_LogoutUser__invalid_token(t *testing.T) {
 	app := fiber.New()
 
 	userStoreMock := &IUserStoreMock{}
-	controller, err := controllers.NewRegistrationController(userStoreMock)
+	controller, err := controllers.NewRegistrationController(userStoreMock, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 	assert.NoError(t, err)
 	assert.NotNil(t, controller)
 	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
@@ -526,11 +665,11 @@ func TestRegistrationController_LogoutUser__success(t *testing.T) {
 	app := fiber.New()
 
 	username := "user"
-	token, err := jwtmw.GenerateToken(username, jwtmw.TokenExpiration)
+	token, err := newTestTokenIssuer(t).GenerateToken(models.User{Username: username, Role: models.SoldierUserRole}, jwtmw.AccessTokenType, "family")
 	assert.NoError(t, err)
 
 	userStoreMock := &IUserStoreMock{}
-	controller, err := controllers.NewRegistrationController(userStoreMock)
+	controller, err := controllers.NewRegistrationController(userStoreMock, newTestRevokedTokenStore(t), newTestTokenIssuer(t))
 	assert.NoError(t, err)
 	assert.NotNil(t, controller)
 	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
@@ -550,3 +689,87 @@ func TestRegistrationController_LogoutUser__success(t *testing.T) {
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 	userStoreMock.AssertExpectations(t)
 }
+
+func TestRegistrationController_LogoutUser__revokes_tokens(t *testing.T) {
+	//Arrange
+	app := fiber.New()
+
+	user := models.User{Username: "user", Role: models.SoldierUserRole}
+	token, err := newTestTokenIssuer(t).GenerateToken(user, jwtmw.AccessTokenType, "family")
+	require.NoError(t, err)
+	refreshToken, err := newTestTokenIssuer(t).GenerateToken(user, jwtmw.RefreshTokenType, "family")
+	require.NoError(t, err)
+
+	revokedTokenStore := newTestRevokedTokenStore(t)
+	controller, err := controllers.NewRegistrationController(&IUserStoreMock{}, revokedTokenStore, newTestTokenIssuer(t))
+	require.NoError(t, err)
+	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
+	require.NoError(t, err)
+
+	logoutBody := api.LogoutReqBody{
+		Token:        token,
+		RefreshToken: refreshToken,
+	}
+	req := httptest.NewRequest(fiber.MethodPost, controllers.LogoutRoute, test_utils.WrapStructWithReader(t, logoutBody))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	refreshTokenBody := api.RefreshTokenReqBody{
+		RefreshToken: refreshToken,
+	}
+	refreshTokenReq := httptest.NewRequest(fiber.MethodPost, controllers.RefreshTokenRoute, test_utils.WrapStructWithReader(t, refreshTokenBody))
+	refreshTokenReq.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	//Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+	refreshResp, refreshErr := app.Test(refreshTokenReq, test_utils.TestTimeout)
+
+	//Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.NoError(t, refreshErr)
+	assert.Equal(t, fiber.StatusUnauthorized, refreshResp.StatusCode)
+	tokenID, _ := parseTestTokenClaims(t, token)[jwtmw.TokenIDClaimField].(string)
+	revoked, err := revokedTokenStore.IsTokenRevoked(context.Background(), tokenID)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestRegistrationController_LogoutUser__refresh_token_of_other_user(t *testing.T) {
+	//Arrange
+	app := fiber.New()
+
+	token, err := newTestTokenIssuer(t).GenerateToken(models.User{Username: "user", Role: models.SoldierUserRole}, jwtmw.AccessTokenType, "family")
+	require.NoError(t, err)
+	refreshToken, err := newTestTokenIssuer(t).GenerateToken(models.User{Username: "other", Role: models.SoldierUserRole},
+		jwtmw.RefreshTokenType, "family")
+	require.NoError(t, err)
+
+	revokedTokenStore := newTestRevokedTokenStore(t)
+	controller, err := controllers.NewRegistrationController(&IUserStoreMock{}, revokedTokenStore, newTestTokenIssuer(t))
+	require.NoError(t, err)
+	err = controllers.SetupRoutes(app, []controllers.Controller{controller})
+	require.NoError(t, err)
+
+	logoutBody := api.LogoutReqBody{
+		Token:        token,
+		RefreshToken: refreshToken,
+	}
+	req := httptest.NewRequest(fiber.MethodPost, controllers.LogoutRoute, test_utils.WrapStructWithReader(t, logoutBody))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	//Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	//Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	tokenID, _ := parseTestTokenClaims(t, token)[jwtmw.TokenIDClaimField].(string)
+	revoked, err := revokedTokenStore.IsTokenRevoked(context.Background(), tokenID)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func parseTestTokenClaims(t *testing.T, signedToken string) jtoken.MapClaims {
+	claims, err := newTestTokenIssuer(t).ParseToken(signedToken)
+	require.NoError(t, err)
+	return claims
+}
@@ -0,0 +1,16 @@
+package controllers
+
+import (
+	jwtmw "brothers_in_batash/internal/pkg/middleware/jwt"
+	"brothers_in_batash/internal/pkg/models"
+)
+
+// Route authorization, applied after a controller's authMiddleware. Every user views the roster and the schedule,
+// squad commanders schedule, and platoon admins manage the roster and the shift templates.
+var (
+	requireViewer         = jwtmw.RequireRole(models.ViewerUserRole)
+	requireSquadCommander = jwtmw.RequireRole(models.SquadCommanderUserRole)
+	requirePlatoonAdmin   = jwtmw.RequireRole(models.PlatoonAdminUserRole)
+	// requireSquadCommanderOrSelf also lets soldiers manage their own soldier's resources, under the "id" route param
+	requireSquadCommanderOrSelf = jwtmw.RequireRoleOrSoldier(models.SquadCommanderUserRole, "id")
+)
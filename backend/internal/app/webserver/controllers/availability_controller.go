@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"brothers_in_batash/internal/pkg/logging"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"brothers_in_batash/internal/pkg/utils"
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+)
+
+// AvailabilityController manages the leaves of soldiers - periods of time in which they are unavailable for duty
+type AvailabilityController struct {
+	leaveStore     store.ILeaveStore
+	soldierStore   store.ISoldierStore
+	authMiddleware fiber.Handler
+}
+
+func NewAvailabilityController(leaveStore store.ILeaveStore, soldierStore store.ISoldierStore,
+	authMiddleware fiber.Handler) (*AvailabilityController, error) {
+	if leaveStore == nil {
+		return nil, errors.New("leaveStore is nil")
+	}
+	if soldierStore == nil {
+		return nil, errors.New("soldierStore is nil")
+	}
+	if authMiddleware == nil {
+		return nil, errors.New("authMiddleware is nil")
+	}
+	return &AvailabilityController{leaveStore: leaveStore, soldierStore: soldierStore, authMiddleware: authMiddleware}, nil
+}
+
+func (c *AvailabilityController) RegisterRoutes(router fiber.Router) error {
+	router.Post(CreateSoldierLeaveRoute, c.authMiddleware, requireSquadCommanderOrSelf, c.createLeave)
+	router.Get(GetSoldierLeaveRoute, c.authMiddleware, requireViewer, c.getLeave)
+	router.Get(GetAllSoldierLeavesRoute, c.authMiddleware, requireViewer, c.getAllLeaves)
+	router.Put(UpdateSoldierLeaveRoute, c.authMiddleware, requireSquadCommanderOrSelf, c.updateLeave)
+	router.Delete(DeleteSoldierLeaveRoute, c.authMiddleware, requireSquadCommanderOrSelf, c.deleteLeave)
+	return nil
+}
+
+func (c *AvailabilityController) createLeave(ctx *fiber.Ctx) error {
+	soldierID := ctx.Params("id")
+	leave := models.Leave{}
+	if err := ctx.BodyParser(&leave); err != nil {
+		logging.Debug("Could not parse leave creation request body", []logging.LogProp{{"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	leave.ID = utils.NewEntityID()
+	leave.SoldierID = soldierID
+	if err := leave.IsValid(); err != nil {
+		logging.Debug("Invalid leave", []logging.LogProp{{"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	if status, found := c.findSoldier(ctx.UserContext(), soldierID); !found {
+		return ctx.SendStatus(status)
+	}
+
+	if err := c.leaveStore.CreateNewLeave(ctx.UserContext(), leave); err != nil {
+		logging.Warning(err, "error on creating new leave", []logging.LogProp{{"soldierID", soldierID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	return sendCreated(ctx, leave.ID, leave)
+}
+
+func (c *AvailabilityController) getLeave(ctx *fiber.Ctx) error {
+	leave, status, found := c.findSoldierLeave(ctx.UserContext(), ctx.Params("id"), ctx.Params("leaveId"))
+	if !found {
+		return ctx.SendStatus(status)
+	}
+	return ctx.JSON(leave)
+}
+
+func (c *AvailabilityController) getAllLeaves(ctx *fiber.Ctx) error {
+	soldierID := ctx.Params("id")
+	if status, found := c.findSoldier(ctx.UserContext(), soldierID); !found {
+		return ctx.SendStatus(status)
+	}
+	leaves, err := c.leaveStore.FindLeavesBySoldierID(ctx.UserContext(), soldierID)
+	if err != nil {
+		logging.Warning(err, "error on fetching soldier leaves", []logging.LogProp{{"soldierID", soldierID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	return ctx.JSON(leaves)
+}
+
+func (c *AvailabilityController) updateLeave(ctx *fiber.Ctx) error {
+	soldierID := ctx.Params("id")
+	leaveID := ctx.Params("leaveId")
+	leave := models.Leave{}
+	if err := ctx.BodyParser(&leave); err != nil {
+		logging.Info("Could not parse leave update request body", []logging.LogProp{{"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	if leave.ID != leaveID {
+		logging.Debug("mismatch between leave ID in body and leave ID in URI",
+			[]logging.LogProp{{"body_leave_id", leave.ID}, {"uri_leave_id", leaveID}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	leave.SoldierID = soldierID
+	if err := leave.IsValid(); err != nil {
+		logging.Debug("Invalid leave", []logging.LogProp{{"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	if _, status, found := c.findSoldierLeave(ctx.UserContext(), soldierID, leaveID); !found {
+		return ctx.SendStatus(status)
+	}
+
+	if err := c.leaveStore.UpdateLeave(ctx.UserContext(), leave); err != nil {
+		logging.Warning(err, "error on updating leave", []logging.LogProp{{"leaveID", leaveID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	return ctx.SendStatus(fiber.StatusOK)
+}
+
+func (c *AvailabilityController) deleteLeave(ctx *fiber.Ctx) error {
+	leaveID := ctx.Params("leaveId")
+	if _, status, found := c.findSoldierLeave(ctx.UserContext(), ctx.Params("id"), leaveID); !found {
+		return ctx.SendStatus(status)
+	}
+	if err := c.leaveStore.DeleteLeave(ctx.UserContext(), leaveID); err != nil {
+		logging.Warning(err, "error on deleting leave", []logging.LogProp{{"leaveID", leaveID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	return ctx.SendStatus(fiber.StatusOK)
+}
+
+// findSoldier checks whether the soldier exists. If not, the returned status is the one to respond with.
+func (c *AvailabilityController) findSoldier(ctx context.Context, soldierID string) (status int, found bool) {
+	soldiers, err := c.soldierStore.FindSoldierByID(ctx, soldierID)
+	if err != nil {
+		logging.Warning(err, "could not query for soldier", []logging.LogProp{{"soldierID", soldierID}})
+		return fiber.StatusInternalServerError, false
+	} else if len(soldiers) == 0 {
+		logging.Trace("Soldier not found", []logging.LogProp{{"soldierID", soldierID}})
+		return fiber.StatusNotFound, false
+	}
+	return fiber.StatusOK, true
+}
+
+// findSoldierLeave fetches a leave, as long as it belongs to the soldier. If not, the returned status is the one to
+// respond with.
+func (c *AvailabilityController) findSoldierLeave(ctx context.Context, soldierID, leaveID string) (leave models.Leave, status int, found bool) {
+	leaves, err := c.leaveStore.FindLeaveByID(ctx, leaveID)
+	if err != nil {
+		logging.Warning(err, "could not query for leave", []logging.LogProp{{"leaveID", leaveID}})
+		return models.Leave{}, fiber.StatusInternalServerError, false
+	} else if len(leaves) == 0 || leaves[0].SoldierID != soldierID {
+		logging.Trace("Leave not found", []logging.LogProp{{"soldierID", soldierID}, {"leaveID", leaveID}})
+		return models.Leave{}, fiber.StatusNotFound, false
+	}
+	return leaves[0], fiber.StatusOK, true
+}
@@ -0,0 +1,233 @@
+package controllers_test
+
+import (
+	"brothers_in_batash/internal/app/webserver/controllers"
+	"brothers_in_batash/internal/pkg/mocks"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/test_utils"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testLeave = models.Leave{
+	ID:        "leave",
+	SoldierID: commanderID,
+	Type:      models.HomeLeaveType,
+	StartTime: time.Date(2025, time.April, 9, 8, 0, 0, 0, time.UTC),
+	EndTime:   time.Date(2025, time.April, 12, 8, 0, 0, 0, time.UTC),
+}
+
+func setupAvailabilityController(t *testing.T, leaveStore *mocks.MockILeaveStore, soldierStore *mocks.MockISoldierStore) *fiber.App {
+	app := fiber.New()
+	controller, err := controllers.NewAvailabilityController(leaveStore, soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	return app
+}
+
+func TestAvailabilityController_NewAvailabilityController__sad_flows(t *testing.T) {
+	// Act
+	nilLeaveStoreController, nilLeaveStoreErr := controllers.NewAvailabilityController(nil, &mocks.MockISoldierStore{},
+		test_utils.AlwaysAllowedJWTMiddleware)
+	nilSoldierStoreController, nilSoldierStoreErr := controllers.NewAvailabilityController(&mocks.MockILeaveStore{}, nil,
+		test_utils.AlwaysAllowedJWTMiddleware)
+	nilMiddlewareController, nilMiddlewareErr := controllers.NewAvailabilityController(&mocks.MockILeaveStore{},
+		&mocks.MockISoldierStore{}, nil)
+
+	// Assert
+	assert.Error(t, nilLeaveStoreErr)
+	assert.Nil(t, nilLeaveStoreController)
+	assert.Error(t, nilSoldierStoreErr)
+	assert.Nil(t, nilSoldierStoreController)
+	assert.Error(t, nilMiddlewareErr)
+	assert.Nil(t, nilMiddlewareController)
+}
+
+func TestAvailabilityController_CreateLeave__success(t *testing.T) {
+	// Arrange
+	leaveStore := &mocks.MockILeaveStore{}
+	var createdID string
+	leaveStore.On("CreateNewLeave", mock.Anything, mock.MatchedBy(func(arg models.Leave) bool {
+		return arg.ID != "" && arg.ID != testLeave.ID && arg.SoldierID == commanderID
+	})).Run(func(args mock.Arguments) {
+		createdID = args.Get(1).(models.Leave).ID
+	}).Return(nil)
+	soldierStore := &mocks.MockISoldierStore{}
+	soldierStore.On("FindSoldierByID", mock.Anything, commanderID).Return([]models.Soldier{testCommander}, nil)
+	app := setupAvailabilityController(t, leaveStore, soldierStore)
+	leave := testLeave
+	leave.SoldierID = ""
+	req := httptest.NewRequest(fiber.MethodPost, fmt.Sprintf("/soldiers/%s/availability", commanderID),
+		test_utils.WrapStructWithReader(t, leave))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Equal(t, fmt.Sprintf("/soldiers/%s/availability/%s", commanderID, createdID), resp.Header.Get(fiber.HeaderLocation))
+	var createdLeave models.Leave
+	err = json.NewDecoder(resp.Body).Decode(&createdLeave)
+	require.NoError(t, err)
+	assert.Equal(t, createdID, createdLeave.ID)
+	assert.Equal(t, commanderID, createdLeave.SoldierID)
+	leaveStore.AssertExpectations(t)
+}
+
+func TestAvailabilityController_CreateLeave__invalid_leave(t *testing.T) {
+	// Arrange
+	leave := testLeave
+	leave.EndTime = leave.StartTime.Add(-time.Hour)
+	app := setupAvailabilityController(t, &mocks.MockILeaveStore{}, &mocks.MockISoldierStore{})
+	req := httptest.NewRequest(fiber.MethodPost, fmt.Sprintf("/soldiers/%s/availability", commanderID),
+		test_utils.WrapStructWithReader(t, leave))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestAvailabilityController_CreateLeave__soldier_not_found(t *testing.T) {
+	// Arrange
+	soldierStore := &mocks.MockISoldierStore{}
+	soldierStore.On("FindSoldierByID", mock.Anything, commanderID).Return([]models.Soldier{}, nil)
+	leaveStore := &mocks.MockILeaveStore{}
+	app := setupAvailabilityController(t, leaveStore, soldierStore)
+	req := httptest.NewRequest(fiber.MethodPost, fmt.Sprintf("/soldiers/%s/availability", commanderID),
+		test_utils.WrapStructWithReader(t, testLeave))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	leaveStore.AssertNotCalled(t, "CreateNewLeave", mock.Anything, mock.Anything)
+}
+
+func TestAvailabilityController_CreateLeave__by_soldier(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userSoldierID  string
+		expectedStatus int
+	}{
+		{name: "own leave", userSoldierID: commanderID, expectedStatus: fiber.StatusCreated},
+		{name: "other soldier's leave", userSoldierID: "other", expectedStatus: fiber.StatusForbidden},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			leaveStore := &mocks.MockILeaveStore{}
+			leaveStore.On("CreateNewLeave", mock.Anything, mock.Anything).Return(nil)
+			soldierStore := &mocks.MockISoldierStore{}
+			soldierStore.On("FindSoldierByID", mock.Anything, commanderID).Return([]models.Soldier{testCommander}, nil)
+			app := fiber.New()
+			controller, err := controllers.NewAvailabilityController(leaveStore, soldierStore,
+				test_utils.NewRoleJWTMiddleware(models.SoldierUserRole, testCase.userSoldierID))
+			require.NoError(t, err)
+			require.NoError(t, controller.RegisterRoutes(app))
+			req := httptest.NewRequest(fiber.MethodPost, fmt.Sprintf("/soldiers/%s/availability", commanderID),
+				test_utils.WrapStructWithReader(t, testLeave))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			// Act
+			resp, err := app.Test(req, test_utils.TestTimeout)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestAvailabilityController_GetAllLeaves__success(t *testing.T) {
+	// Arrange
+	leaveStore := &mocks.MockILeaveStore{}
+	leaveStore.On("FindLeavesBySoldierID", mock.Anything, commanderID).Return([]models.Leave{testLeave}, nil)
+	soldierStore := &mocks.MockISoldierStore{}
+	soldierStore.On("FindSoldierByID", mock.Anything, commanderID).Return([]models.Soldier{testCommander}, nil)
+	app := setupAvailabilityController(t, leaveStore, soldierStore)
+	req := httptest.NewRequest(fiber.MethodGet, fmt.Sprintf("/soldiers/%s/availability", commanderID), nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var leaves []models.Leave
+	err = json.NewDecoder(resp.Body).Decode(&leaves)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Leave{testLeave}, leaves)
+}
+
+func TestAvailabilityController_GetLeave__other_soldier(t *testing.T) {
+	// Arrange
+	leaveStore := &mocks.MockILeaveStore{}
+	leaveStore.On("FindLeaveByID", mock.Anything, testLeave.ID).Return([]models.Leave{testLeave}, nil)
+	app := setupAvailabilityController(t, leaveStore, &mocks.MockISoldierStore{})
+	req := httptest.NewRequest(fiber.MethodGet, fmt.Sprintf("/soldiers/other/availability/%s", testLeave.ID), nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func TestAvailabilityController_UpdateLeave__success(t *testing.T) {
+	// Arrange
+	updatedLeave := testLeave
+	updatedLeave.Type = models.SickLeaveType
+	leaveStore := &mocks.MockILeaveStore{}
+	leaveStore.On("FindLeaveByID", mock.Anything, testLeave.ID).Return([]models.Leave{testLeave}, nil)
+	leaveStore.On("UpdateLeave", mock.Anything, mock.MatchedBy(func(arg models.Leave) bool {
+		return arg.ID == testLeave.ID && arg.Type == models.SickLeaveType
+	})).Return(nil)
+	app := setupAvailabilityController(t, leaveStore, &mocks.MockISoldierStore{})
+	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/soldiers/%s/availability/%s", commanderID, testLeave.ID),
+		test_utils.WrapStructWithReader(t, updatedLeave))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	leaveStore.AssertExpectations(t)
+}
+
+func TestAvailabilityController_DeleteLeave__success(t *testing.T) {
+	// Arrange
+	leaveStore := &mocks.MockILeaveStore{}
+	leaveStore.On("FindLeaveByID", mock.Anything, testLeave.ID).Return([]models.Leave{testLeave}, nil)
+	leaveStore.On("DeleteLeave", mock.Anything, testLeave.ID).Return(nil)
+	app := setupAvailabilityController(t, leaveStore, &mocks.MockISoldierStore{})
+	req := httptest.NewRequest(fiber.MethodDelete, fmt.Sprintf("/soldiers/%s/availability/%s", commanderID, testLeave.ID), nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	leaveStore.AssertExpectations(t)
+}
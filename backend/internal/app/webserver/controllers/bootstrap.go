@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"brothers_in_batash/internal/pkg/logging"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// BootstrapAdmin creates the platoon admin the first users are provisioned by. An existing user with the same username
+// is left untouched, so the password given on later startups does not override a password that was since changed.
+func BootstrapAdmin(ctx context.Context, userStore store.IUserStore, username, password string) error {
+	if username == "" && password == "" {
+		return nil
+	}
+	if username == "" || password == "" {
+		return errors.New("both the admin username and password must be set")
+	}
+	users, err := userStore.FindUserByUsername(ctx, username)
+	if err != nil {
+		return errors.Wrap(err, "could not lookup if admin exists")
+	}
+	if len(users) > 0 {
+		logging.Debug("Admin already exists, skipping bootstrap", []logging.LogProp{{"username", username}})
+		return nil
+	}
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return errors.Wrap(err, "could not hash admin password")
+	}
+	admin := models.User{Username: username, HashedPassword: hashedPassword, Role: models.PlatoonAdminUserRole}
+	if err := userStore.CreateNewUser(ctx, admin); err != nil {
+		return errors.Wrap(err, "could not create admin")
+	}
+	logging.Info("Created bootstrap admin", []logging.LogProp{{"username", username}})
+	return nil
+}
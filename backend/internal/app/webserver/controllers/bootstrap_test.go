@@ -0,0 +1,76 @@
+package controllers_test
+
+import (
+	"brothers_in_batash/internal/app/webserver/controllers"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBootstrapAdmin__creates_admin(t *testing.T) {
+	// Arrange
+	userStore, err := store.NewUserStore()
+	require.NoError(t, err)
+
+	// Act
+	err = controllers.BootstrapAdmin(context.Background(), userStore, "commander", "password")
+
+	// Assert
+	assert.NoError(t, err)
+	users, err := userStore.FindUserByUsername(context.Background(), "commander")
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, models.PlatoonAdminUserRole, users[0].Role)
+	assert.NoError(t, bcrypt.CompareHashAndPassword(users[0].HashedPassword, []byte("password")))
+}
+
+func TestBootstrapAdmin__keeps_existing_user(t *testing.T) {
+	// Arrange
+	userStore, err := store.NewUserStore()
+	require.NoError(t, err)
+	require.NoError(t, controllers.BootstrapAdmin(context.Background(), userStore, "commander", "password"))
+
+	// Act
+	err = controllers.BootstrapAdmin(context.Background(), userStore, "commander", "other password")
+
+	// Assert
+	assert.NoError(t, err)
+	users, err := userStore.FindUserByUsername(context.Background(), "commander")
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.NoError(t, bcrypt.CompareHashAndPassword(users[0].HashedPassword, []byte("password")))
+}
+
+func TestBootstrapAdmin__credentials(t *testing.T) {
+	testCases := []struct {
+		name        string
+		username    string
+		password    string
+		expectedErr bool
+	}{
+		{name: "not configured", username: "", password: "", expectedErr: false},
+		{name: "missing password", username: "commander", password: "", expectedErr: true},
+		{name: "missing username", username: "", password: "password", expectedErr: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			userStore, err := store.NewUserStore()
+			require.NoError(t, err)
+
+			// Act
+			err = controllers.BootstrapAdmin(context.Background(), userStore, testCase.username, testCase.password)
+
+			// Assert
+			assert.Equal(t, testCase.expectedErr, err != nil)
+			users, err := userStore.FindUserByUsername(context.Background(), testCase.username)
+			require.NoError(t, err)
+			assert.Empty(t, users)
+		})
+	}
+}
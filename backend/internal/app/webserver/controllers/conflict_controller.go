@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"brothers_in_batash/internal/pkg/logging"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+)
+
+type ConflictController struct {
+	shiftStore     store.IShiftStore
+	dayStore       store.IDayStore
+	authMiddleware fiber.Handler
+}
+
+func NewConflictController(shiftStore store.IShiftStore, dayStore store.IDayStore, authMiddleware fiber.Handler) (*ConflictController, error) {
+	if shiftStore == nil {
+		return nil, errors.New("shiftStore is nil")
+	}
+	if dayStore == nil {
+		return nil, errors.New("dayStore is nil")
+	}
+	if authMiddleware == nil {
+		return nil, errors.New("authMiddleware is nil")
+	}
+	return &ConflictController{shiftStore: shiftStore, dayStore: dayStore, authMiddleware: authMiddleware}, nil
+}
+
+func (c *ConflictController) RegisterRoutes(router fiber.Router) error {
+	router.Get(GetConflictsRoute, c.authMiddleware, requireViewer, c.getConflicts)
+	return nil
+}
+
+// getConflicts reports every double booked soldier in shifts that take place within the requested dates range,
+// whether the shifts are stored on their own or as part of a day schedule
+func (c *ConflictController) getConflicts(ctx *fiber.Ctx) error {
+	from, to, ok := parseDateRangeQuery(ctx)
+	if !ok {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	shifts, err := findAllShifts(ctx.UserContext(), c.shiftStore, c.dayStore)
+	if err != nil {
+		logging.Warning(err, "error on fetching all shifts", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.JSON(scheduling.FindAllOverlaps(shiftsIntersectingRange(shifts, from, to)))
+}
+
+// parseDateRangeQuery parses the inclusive dates range given by the "from" and "to" query params
+func parseDateRangeQuery(ctx *fiber.Ctx) (from time.Time, to time.Time, ok bool) {
+	from, err := time.Parse("2006-01-02", ctx.Query("from"))
+	if err != nil {
+		logging.Debug("Invalid from date format", []logging.LogProp{{"from", ctx.Query("from")}})
+		return time.Time{}, time.Time{}, false
+	}
+	to, err = time.Parse("2006-01-02", ctx.Query("to"))
+	if err != nil {
+		logging.Debug("Invalid to date format", []logging.LogProp{{"to", ctx.Query("to")}})
+		return time.Time{}, time.Time{}, false
+	}
+	if to.Before(from) {
+		logging.Debug("Range end is before range start", []logging.LogProp{{"from", ctx.Query("from")}, {"to", ctx.Query("to")}})
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
+
+// findAllShifts returns every known shift, whether it is stored on its own or as part of a day schedule
+func findAllShifts(ctx context.Context, shiftStore store.IShiftStore, dayStore store.IDayStore) ([]models.Shift, error) {
+	shifts, err := shiftStore.FindAllShifts(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch shifts")
+	}
+	daySchedules, err := dayStore.FindAllDaySchedules(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch day schedules")
+	}
+	for _, daySchedule := range daySchedules {
+		shifts = mergeShifts(shifts, daySchedule.Shifts)
+	}
+	return shifts, nil
+}
+
+// mergeShifts returns the union of both shift slices. Shifts with the same ID are considered to be the same shift,
+// in which case the instance from primary is kept.
+func mergeShifts(primary []models.Shift, secondary []models.Shift) []models.Shift {
+	merged := append(make([]models.Shift, 0, len(primary)+len(secondary)), primary...)
+	seen := make(map[string]bool, len(primary))
+	for _, shift := range primary {
+		seen[shift.ID] = true
+	}
+	for _, shift := range secondary {
+		if !seen[shift.ID] {
+			seen[shift.ID] = true
+			merged = append(merged, shift)
+		}
+	}
+	return merged
+}
+
+// shiftsIntersectingRange returns the shifts taking place, even partially, within the inclusive [from, to] dates range
+func shiftsIntersectingRange(shifts []models.Shift, from, to time.Time) []models.Shift {
+	rangeEnd := to.AddDate(0, 0, 1)
+	inRange := make([]models.Shift, 0)
+	for _, shift := range shifts {
+		if shift.StartTime.Before(rangeEnd) && shift.EndTime.After(from) {
+			inRange = append(inRange, shift)
+		}
+	}
+	return inRange
+}
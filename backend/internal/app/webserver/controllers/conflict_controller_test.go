@@ -0,0 +1,104 @@
+package controllers_test
+
+import (
+	"brothers_in_batash/internal/app/webserver/controllers"
+	"brothers_in_batash/internal/pkg/mocks"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"brothers_in_batash/internal/pkg/test_utils"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConflictController_NewConflictController__sad_flows(t *testing.T) {
+	// Act
+	nilShiftStoreController, nilShiftStoreErr := controllers.NewConflictController(nil, &mocks.MockIDayStore{},
+		test_utils.AlwaysAllowedJWTMiddleware)
+	nilDayStoreController, nilDayStoreErr := controllers.NewConflictController(&mocks.MockIShiftStore{}, nil,
+		test_utils.AlwaysAllowedJWTMiddleware)
+	nilMiddlewareController, nilMiddlewareErr := controllers.NewConflictController(&mocks.MockIShiftStore{}, &mocks.MockIDayStore{}, nil)
+
+	// Assert
+	assert.Error(t, nilShiftStoreErr)
+	assert.Nil(t, nilShiftStoreController)
+	assert.Error(t, nilDayStoreErr)
+	assert.Nil(t, nilDayStoreController)
+	assert.Error(t, nilMiddlewareErr)
+	assert.Nil(t, nilMiddlewareController)
+}
+
+func TestConflictController_GetConflicts__invalid_dates(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{name: "missing dates", query: ""},
+		{name: "invalid from date", query: "?from=yesterday&to=2025-04-09"},
+		{name: "end before start", query: "?from=2025-04-09&to=2025-04-08"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			app := fiber.New()
+			controller, err := controllers.NewConflictController(&mocks.MockIShiftStore{}, &mocks.MockIDayStore{},
+				test_utils.AlwaysAllowedJWTMiddleware)
+			require.NoError(t, err)
+			err = controller.RegisterRoutes(app)
+			require.NoError(t, err)
+			req := httptest.NewRequest(fiber.MethodGet, controllers.GetConflictsRoute+testCase.query, nil)
+
+			// Act
+			resp, err := app.Test(req, test_utils.TestTimeout)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+func TestConflictController_GetConflicts__success(t *testing.T) {
+	// Arrange
+	overlappingShift := testShiftModel
+	overlappingShift.ID = "overlapping"
+	outOfRangeShift := testShiftModel
+	outOfRangeShift.ID = "out-of-range"
+	outOfRangeShift.StartTime = testShiftModel.StartTime.AddDate(0, 0, 7)
+	outOfRangeShift.EndTime = testShiftModel.EndTime.AddDate(0, 0, 7)
+	outOfRangeOverlappingShift := outOfRangeShift
+	outOfRangeOverlappingShift.ID = "out-of-range-overlapping"
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{testShiftModel, outOfRangeShift}, nil)
+	dayStore := &mocks.MockIDayStore{}
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{
+		{Date: getStrippedUTCDate(), Shifts: []models.Shift{testShiftModel, overlappingShift}},
+		{Date: getStrippedUTCDate(), Shifts: []models.Shift{outOfRangeOverlappingShift}},
+	}, nil)
+	app := fiber.New()
+	controller, err := controllers.NewConflictController(shiftStore, dayStore, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodGet, controllers.GetConflictsRoute+"?from=2025-04-09&to=2025-04-09", nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var overlaps []scheduling.Overlap
+	err = json.NewDecoder(resp.Body).Decode(&overlaps)
+	assert.NoError(t, err)
+	require.Len(t, overlaps, 1)
+	assert.Equal(t, commanderID, overlaps[0].SoldierID)
+	assert.ElementsMatch(t, []string{testShiftModel.ID, overlappingShift.ID}, overlaps[0].ShiftIDs)
+	assert.Equal(t, time.Hour, overlaps[0].End.Sub(overlaps[0].Start))
+}
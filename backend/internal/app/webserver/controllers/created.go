@@ -0,0 +1,14 @@
+package controllers
+
+import (
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// sendCreated responds with the entity that was just created under the request's path, and points the Location header
+// at it
+func sendCreated(ctx *fiber.Ctx, id string, entity any) error {
+	ctx.Location(ctx.Path() + "/" + url.PathEscape(id))
+	return ctx.Status(fiber.StatusCreated).JSON(entity)
+}
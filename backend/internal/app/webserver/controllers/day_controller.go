@@ -1,38 +1,47 @@
 package controllers
 
 import (
+	"brothers_in_batash/internal/app/webserver/api"
 	"brothers_in_batash/internal/pkg/logging"
 	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
 	"brothers_in_batash/internal/pkg/store"
+	"brothers_in_batash/internal/pkg/utils"
+	"context"
 	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	pkgerrors "github.com/pkg/errors"
 )
 
 // TODO - Implement a concrete type for API requests and responses bodies(currently using the actual models)
 
 type DayScheduleController struct {
 	dayStore       store.IDayStore
+	shiftStore     store.IShiftStore
 	authMiddleware fiber.Handler
 }
 
-func NewDayScheduleController(dayStore store.IDayStore, authMiddleware fiber.Handler) (*DayScheduleController, error) {
+func NewDayScheduleController(dayStore store.IDayStore, shiftStore store.IShiftStore, authMiddleware fiber.Handler) (*DayScheduleController, error) {
 	if dayStore == nil {
 		return nil, errors.New("dayStore is nil")
 	}
+	if shiftStore == nil {
+		return nil, errors.New("shiftStore is nil")
+	}
 	if authMiddleware == nil {
 		return nil, errors.New("authMiddleware is nil")
 	}
-	return &DayScheduleController{dayStore: dayStore, authMiddleware: authMiddleware}, nil
+	return &DayScheduleController{dayStore: dayStore, shiftStore: shiftStore, authMiddleware: authMiddleware}, nil
 }
 
 func (c *DayScheduleController) RegisterRoutes(router fiber.Router) error {
-	router.Post(CreateDayScheduleRoute, c.authMiddleware, c.createDaySchedule)
-	router.Get(GetDayScheduleRoute, c.authMiddleware, c.getDaySchedule)
-	router.Get(GetAllDaySchedulesRoute, c.authMiddleware, c.getAllDaySchedules)
-	router.Put(UpdateDayScheduleRoute, c.authMiddleware, c.updateDaySchedule)
-	router.Delete(DeleteDayScheduleRoute, c.authMiddleware, c.deleteDaySchedule)
+	router.Post(CreateDayScheduleRoute, c.authMiddleware, requireSquadCommander, c.createDaySchedule)
+	router.Get(GetDayScheduleRoute, c.authMiddleware, requireViewer, c.getDaySchedule)
+	router.Get(GetAllDaySchedulesRoute, c.authMiddleware, requireViewer, c.getAllDaySchedules)
+	router.Put(UpdateDayScheduleRoute, c.authMiddleware, requireSquadCommander, c.updateDaySchedule)
+	router.Delete(DeleteDayScheduleRoute, c.authMiddleware, requireSquadCommander, c.deleteDaySchedule)
 	return nil
 }
 
@@ -42,12 +51,38 @@ func (c *DayScheduleController) createDaySchedule(ctx *fiber.Ctx) error {
 		logging.Debug("Could not parse day schedule creation request body", []logging.LogProp{{"error", err.Error()}})
 		return ctx.SendStatus(fiber.StatusBadRequest)
 	}
+	// Only shift generation references shifts from day schedules
+	daySchedule.ShiftIDs = nil
+	for i := range daySchedule.Shifts {
+		daySchedule.Shifts[i].ID = utils.NewEntityID()
+	}
+
+	if overlaps, err := c.findOverlaps(ctx.UserContext(), daySchedule); err != nil {
+		logging.Warning(err, "error on checking new day schedule for overlaps", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if len(overlaps) > 0 {
+		logging.Debug("new day schedule double books soldiers", nil)
+		return ctx.Status(fiber.StatusConflict).JSON(api.ShiftConflictRespBody{Overlaps: overlaps})
+	}
 
-	if err := c.dayStore.CreateNewDaySchedule(daySchedule); err != nil {
+	if err := c.dayStore.CreateNewDaySchedule(ctx.UserContext(), daySchedule); errors.Is(err, store.ErrAlreadyExists) {
+		logging.Debug("day schedule already exists", []logging.LogProp{{"error", err.Error()}})
+		return ctx.Status(fiber.StatusConflict).SendString("Day schedule already exists, update it with PUT and If-Match instead")
+	} else if err != nil {
 		logging.Warning(err, "error on creating new day schedule", nil)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
-	return ctx.SendStatus(fiber.StatusCreated)
+	dateStr := daySchedule.Date.Format("2006-01-02")
+	daySchedules, err := c.dayStore.FindDaySchedule(ctx.UserContext(), daySchedule.Date)
+	if err != nil {
+		logging.Warning(err, "could not query for created day schedule", []logging.LogProp{{"date", dateStr}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if len(daySchedules) == 0 {
+		logging.Trace("created day schedule was deleted already", []logging.LogProp{{"date", dateStr}})
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	setETag(ctx, daySchedules[0].Version)
+	return sendCreated(ctx, dateStr, daySchedules[0])
 }
 
 func (c *DayScheduleController) getDaySchedule(ctx *fiber.Ctx) error {
@@ -58,7 +93,7 @@ func (c *DayScheduleController) getDaySchedule(ctx *fiber.Ctx) error {
 		return ctx.SendStatus(fiber.StatusBadRequest)
 	}
 
-	daySchedules, err := c.dayStore.FindDaySchedule(date)
+	daySchedules, err := c.dayStore.FindDaySchedule(ctx.UserContext(), date)
 	if err != nil {
 		logging.Warning(err, "error on fetching day schedule", []logging.LogProp{{"date", dateStr}})
 		return ctx.SendStatus(fiber.StatusInternalServerError)
@@ -67,11 +102,20 @@ func (c *DayScheduleController) getDaySchedule(ctx *fiber.Ctx) error {
 		logging.Trace("could not find day schedule", []logging.LogProp{{"date", dateStr}})
 		return ctx.SendStatus(fiber.StatusNotFound)
 	}
+	setETag(ctx, daySchedules[0].Version)
 	return ctx.JSON(daySchedules[0])
 }
 
+// getAllDaySchedules returns the day schedules within the optional "from" and "to" dates range. The other shift filter
+// query params narrow down the shifts of every day schedule, and leave out the days none of their shifts match.
 func (c *DayScheduleController) getAllDaySchedules(ctx *fiber.Ctx) error {
-	daySchedules, err := c.dayStore.FindAllDaySchedules()
+	shiftFilter, ok := parseShiftFilterQuery(ctx)
+	if !ok {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	filter := store.DayScheduleFilter{From: shiftFilter.From, To: shiftFilter.To, Shifts: store.ShiftFilter{
+		SoldierID: shiftFilter.SoldierID, Type: shiftFilter.Type, ShiftTemplateID: shiftFilter.ShiftTemplateID}}
+	daySchedules, err := c.dayStore.FindDaySchedules(ctx.UserContext(), filter)
 	if err != nil {
 		logging.Warning(err, "error on fetching all day schedules", nil)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
@@ -86,6 +130,10 @@ func (c *DayScheduleController) updateDaySchedule(ctx *fiber.Ctx) error {
 		logging.Debug("Invalid date format", []logging.LogProp{{"date", dateStr}})
 		return ctx.SendStatus(fiber.StatusBadRequest)
 	}
+	version, status, ok := ifMatchVersion(ctx)
+	if !ok {
+		return ctx.SendStatus(status)
+	}
 
 	daySchedule := models.DaySchedule{}
 	if err := ctx.BodyParser(&daySchedule); err != nil {
@@ -94,10 +142,33 @@ func (c *DayScheduleController) updateDaySchedule(ctx *fiber.Ctx) error {
 	}
 
 	daySchedule.Date = date
-	if err := c.dayStore.UpdateDaySchedule(daySchedule); err != nil {
+	daySchedule.Version = version
+	// The shifts generated into the day schedule are changed through the shifts API, so their references are kept
+	storedSchedules, err := c.dayStore.FindDaySchedule(ctx.UserContext(), date)
+	if err != nil {
+		logging.Warning(err, "error on fetching day schedule to update", []logging.LogProp{{"date", dateStr}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	daySchedule.ShiftIDs = nil
+	if len(storedSchedules) > 0 {
+		daySchedule.ShiftIDs = storedSchedules[0].ShiftIDs
+	}
+	if overlaps, err := c.findOverlaps(ctx.UserContext(), daySchedule); err != nil {
+		logging.Warning(err, "error on checking updated day schedule for overlaps", []logging.LogProp{{"date", dateStr}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if len(overlaps) > 0 {
+		logging.Debug("updated day schedule double books soldiers", []logging.LogProp{{"date", dateStr}})
+		return ctx.Status(fiber.StatusConflict).JSON(api.ShiftConflictRespBody{Overlaps: overlaps})
+	}
+
+	if err := c.dayStore.UpdateDaySchedule(ctx.UserContext(), daySchedule); errors.Is(err, store.ErrVersionConflict) {
+		logging.Debug("day schedule was modified since it was read", []logging.LogProp{{"date", dateStr}, {"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusPreconditionFailed)
+	} else if err != nil {
 		logging.Warning(err, "error on updating day schedule", []logging.LogProp{{"date", dateStr}})
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
+	setETag(ctx, version+1)
 	return ctx.SendStatus(fiber.StatusOK)
 }
 
@@ -109,10 +180,52 @@ func (c *DayScheduleController) deleteDaySchedule(ctx *fiber.Ctx) error {
 		return ctx.SendStatus(fiber.StatusBadRequest)
 	}
 	date = date.UTC()
+	version, status, ok := ifMatchVersion(ctx)
+	if !ok {
+		return ctx.SendStatus(status)
+	}
 
-	if err := c.dayStore.DeleteDaySchedule(date); err != nil {
+	if err := c.dayStore.DeleteDaySchedule(ctx.UserContext(), date, version); errors.Is(err, store.ErrVersionConflict) {
+		logging.Debug("day schedule was modified since it was read", []logging.LogProp{{"date", dateStr}, {"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusPreconditionFailed)
+	} else if err != nil {
 		logging.Warning(err, "error on deleting day schedule", []logging.LogProp{{"date", dateStr}})
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
 	return ctx.SendStatus(fiber.StatusOK)
 }
+
+// findOverlaps checks the day schedule shifts against each other and against every other shift, whether it is stored
+// on its own or as part of another day schedule. The shifts the day schedule currently stores are about to be replaced,
+// so they are left out.
+func (c *DayScheduleController) findOverlaps(ctx context.Context, daySchedule models.DaySchedule) ([]scheduling.Overlap, error) {
+	existingShifts, err := findAllShifts(ctx, c.shiftStore, c.dayStore)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "could not fetch existing shifts")
+	}
+	replacedSchedules, err := c.dayStore.FindDaySchedule(ctx, daySchedule.Date)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "could not fetch replaced day schedule")
+	}
+	excludedShiftIDs := make(map[string]bool, len(daySchedule.Shifts))
+	for _, shift := range daySchedule.Shifts {
+		excludedShiftIDs[shift.ID] = true
+	}
+	for _, replacedSchedule := range replacedSchedules {
+		for _, shift := range replacedSchedule.Shifts {
+			excludedShiftIDs[shift.ID] = true
+		}
+	}
+	otherShifts := make([]models.Shift, 0, len(existingShifts))
+	for _, shift := range existingShifts {
+		if !excludedShiftIDs[shift.ID] {
+			otherShifts = append(otherShifts, shift)
+		}
+	}
+
+	overlaps := scheduling.FindAllOverlaps(daySchedule.Shifts)
+	for _, shift := range daySchedule.Shifts {
+		overlaps = append(overlaps, scheduling.FindOverlaps(shift, otherShifts)...)
+	}
+	return overlaps, nil
+}
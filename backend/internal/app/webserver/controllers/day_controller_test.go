@@ -1,9 +1,11 @@
 package controllers_test
 
 import (
+	"brothers_in_batash/internal/app/webserver/api"
 	"brothers_in_batash/internal/app/webserver/controllers"
 	"brothers_in_batash/internal/pkg/mocks"
 	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
 	"brothers_in_batash/internal/pkg/test_utils"
 	"encoding/json"
 	"fmt"
@@ -12,6 +14,7 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -19,7 +22,16 @@ import (
 
 func TestDayScheduleController_NewDayScheduleController__error_on_nil_store(t *testing.T) {
 	// Act
-	controller, err := controllers.NewDayScheduleController(nil, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewDayScheduleController(nil, &mocks.MockIShiftStore{}, test_utils.AlwaysAllowedJWTMiddleware)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, controller)
+}
+
+func TestDayScheduleController_NewDayScheduleController__error_on_nil_shift_store(t *testing.T) {
+	// Act
+	controller, err := controllers.NewDayScheduleController(&mocks.MockIDayStore{}, nil, test_utils.AlwaysAllowedJWTMiddleware)
 
 	// Assert
 	assert.Error(t, err)
@@ -28,7 +40,7 @@ func TestDayScheduleController_NewDayScheduleController__error_on_nil_store(t *t
 
 func TestDayScheduleController_NewDayScheduleController__error_on_nil_auth_middleware(t *testing.T) {
 	// Act
-	controller, err := controllers.NewDayScheduleController(&mocks.MockIDayStore{}, nil)
+	controller, err := controllers.NewDayScheduleController(&mocks.MockIDayStore{}, &mocks.MockIShiftStore{}, nil)
 
 	// Assert
 	assert.Error(t, err)
@@ -40,7 +52,7 @@ func TestDayScheduleController_NewDayScheduleController__success(t *testing.T) {
 	dayStore := &mocks.MockIDayStore{}
 
 	// Act
-	controller, err := controllers.NewDayScheduleController(dayStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewDayScheduleController(dayStore, &mocks.MockIShiftStore{}, test_utils.AlwaysAllowedJWTMiddleware)
 
 	// Assert
 	assert.NoError(t, err)
@@ -51,7 +63,7 @@ func TestDayScheduleController_CreateDaySchedule__invalid_request_body(t *testin
 	// Arrange
 	app := fiber.New()
 	dayStore := &mocks.MockIDayStore{}
-	controller, err := controllers.NewDayScheduleController(dayStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewDayScheduleController(dayStore, &mocks.MockIShiftStore{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -70,7 +82,11 @@ func TestDayScheduleController_CreateDaySchedule__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	dayStore := &mocks.MockIDayStore{}
-	controller, err := controllers.NewDayScheduleController(dayStore, test_utils.AlwaysAllowedJWTMiddleware)
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{}, nil)
+	dayStore.On("FindDaySchedule", mock.Anything, mock.Anything).Return([]models.DaySchedule{}, nil).Once()
+	controller, err := controllers.NewDayScheduleController(dayStore, shiftStore, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -80,7 +96,14 @@ func TestDayScheduleController_CreateDaySchedule__success(t *testing.T) {
 			{ID: "1", Name: "Shift 1"},
 		},
 	}
-	dayStore.On("CreateNewDaySchedule", mock.AnythingOfType("models.DaySchedule")).Return(nil)
+	storedSchedules := []models.DaySchedule{daySchedule}
+	storedSchedules[0].Version = 1
+	dayStore.On("CreateNewDaySchedule", mock.Anything, mock.MatchedBy(func(arg models.DaySchedule) bool {
+		return len(arg.Shifts) == 1 && arg.Shifts[0].ID != "" && arg.Shifts[0].ID != daySchedule.Shifts[0].ID
+	})).Run(func(args mock.Arguments) {
+		storedSchedules[0].Shifts = args.Get(1).(models.DaySchedule).Shifts
+	}).Return(nil)
+	dayStore.On("FindDaySchedule", mock.Anything, mock.Anything).Return(storedSchedules, nil).Once()
 	req := httptest.NewRequest(fiber.MethodPost, controllers.CreateDayScheduleRoute, test_utils.WrapStructWithReader(t, daySchedule))
 	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
 
@@ -90,14 +113,127 @@ func TestDayScheduleController_CreateDaySchedule__success(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Equal(t, controllers.CreateDayScheduleRoute+"/"+daySchedule.Date.Format("2006-01-02"),
+		resp.Header.Get(fiber.HeaderLocation))
+	assert.Equal(t, `"1"`, resp.Header.Get(fiber.HeaderETag))
+	var createdSchedule models.DaySchedule
+	err = json.NewDecoder(resp.Body).Decode(&createdSchedule)
+	require.NoError(t, err)
+	require.Len(t, createdSchedule.Shifts, 1)
+	assert.Equal(t, storedSchedules[0].Shifts[0].ID, createdSchedule.Shifts[0].ID)
+	assert.NotEqual(t, daySchedule.Shifts[0].ID, createdSchedule.Shifts[0].ID)
 	dayStore.AssertExpectations(t)
 }
 
+func TestDayScheduleController_CreateDaySchedule__existing_date(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	dayStore := &mocks.MockIDayStore{}
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{}, nil)
+	dayStore.On("FindDaySchedule", mock.Anything, mock.Anything).Return([]models.DaySchedule{}, nil)
+	dayStore.On("CreateNewDaySchedule", mock.Anything, mock.AnythingOfType("models.DaySchedule")).
+		Return(errors.Wrap(store.ErrAlreadyExists, "day schedule"))
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+	controller, err := controllers.NewDayScheduleController(dayStore, shiftStore, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	daySchedule := models.DaySchedule{Date: getStrippedUTCDate(), Shifts: []models.Shift{{ID: "1", Name: "Shift 1"}}}
+	req := httptest.NewRequest(fiber.MethodPost, controllers.CreateDayScheduleRoute, test_utils.WrapStructWithReader(t, daySchedule))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+}
+
+func TestDayScheduleController_CreateDaySchedule__double_booked_soldier(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	dayStore := &mocks.MockIDayStore{}
+	existingShift := testShiftModel
+	existingShift.ID = "existing"
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{existingShift}, nil)
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{}, nil)
+	dayStore.On("FindDaySchedule", mock.Anything, mock.Anything).Return([]models.DaySchedule{}, nil)
+	controller, err := controllers.NewDayScheduleController(dayStore, shiftStore, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	daySchedule := models.DaySchedule{
+		Date:   getStrippedUTCDate(),
+		Shifts: []models.Shift{testShiftModel},
+	}
+	req := httptest.NewRequest(fiber.MethodPost, controllers.CreateDayScheduleRoute, test_utils.WrapStructWithReader(t, daySchedule))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	var respBody api.ShiftConflictRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	assert.NoError(t, err)
+	require.Len(t, respBody.Overlaps, 1)
+	assert.Equal(t, commanderID, respBody.Overlaps[0].SoldierID)
+	require.Len(t, respBody.Overlaps[0].ShiftIDs, 2)
+	assert.NotEqual(t, testShiftModel.ID, respBody.Overlaps[0].ShiftIDs[0])
+	assert.Equal(t, existingShift.ID, respBody.Overlaps[0].ShiftIDs[1])
+	dayStore.AssertNotCalled(t, "CreateNewDaySchedule", mock.Anything, mock.Anything)
+}
+
+func TestDayScheduleController_CreateDaySchedule__double_booked_in_other_day_schedule(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	existingShift := testShiftModel
+	existingShift.ID = "existing"
+	dayStore := &mocks.MockIDayStore{}
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{{
+		Date:   getStrippedUTCDate().AddDate(0, 0, -1),
+		Shifts: []models.Shift{existingShift},
+	}}, nil)
+	dayStore.On("FindDaySchedule", mock.Anything, mock.Anything).Return([]models.DaySchedule{}, nil)
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+	controller, err := controllers.NewDayScheduleController(dayStore, shiftStore, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	daySchedule := models.DaySchedule{
+		Date:   getStrippedUTCDate(),
+		Shifts: []models.Shift{testShiftModel},
+	}
+	req := httptest.NewRequest(fiber.MethodPost, controllers.CreateDayScheduleRoute, test_utils.WrapStructWithReader(t, daySchedule))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	var respBody api.ShiftConflictRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	assert.NoError(t, err)
+	require.Len(t, respBody.Overlaps, 1)
+	require.Len(t, respBody.Overlaps[0].ShiftIDs, 2)
+	assert.NotEqual(t, testShiftModel.ID, respBody.Overlaps[0].ShiftIDs[0])
+	assert.Equal(t, existingShift.ID, respBody.Overlaps[0].ShiftIDs[1])
+	dayStore.AssertNotCalled(t, "CreateNewDaySchedule", mock.Anything, mock.Anything)
+}
+
 func TestDayScheduleController_GetDaySchedule__invalid_date_format(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	dayStore := &mocks.MockIDayStore{}
-	controller, err := controllers.NewDayScheduleController(dayStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewDayScheduleController(dayStore, &mocks.MockIShiftStore{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -115,12 +251,12 @@ func TestDayScheduleController_GetDaySchedule__not_found(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	dayStore := &mocks.MockIDayStore{}
-	controller, err := controllers.NewDayScheduleController(dayStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewDayScheduleController(dayStore, &mocks.MockIShiftStore{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 	date := getStrippedUTCDate()
-	dayStore.On("FindDaySchedule", date).Return([]models.DaySchedule{}, nil)
+	dayStore.On("FindDaySchedule", mock.Anything, date).Return([]models.DaySchedule{}, nil)
 	req := httptest.NewRequest(fiber.MethodGet, fmt.Sprintf("/day-schedules/%s", date.Format("2006-01-02")), nil)
 
 	// Act
@@ -136,7 +272,7 @@ func TestDayScheduleController_GetDaySchedule__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	dayStore := &mocks.MockIDayStore{}
-	controller, err := controllers.NewDayScheduleController(dayStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewDayScheduleController(dayStore, &mocks.MockIShiftStore{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -146,8 +282,9 @@ func TestDayScheduleController_GetDaySchedule__success(t *testing.T) {
 		Shifts: []models.Shift{
 			{ID: "1", Name: "Shift 1"},
 		},
+		Version: 3,
 	}
-	dayStore.On("FindDaySchedule", date).Return([]models.DaySchedule{daySchedule}, nil)
+	dayStore.On("FindDaySchedule", mock.Anything, date).Return([]models.DaySchedule{daySchedule}, nil)
 	req := httptest.NewRequest(fiber.MethodGet, fmt.Sprintf("/day-schedules/%s", date.Format("2006-01-02")), nil)
 
 	// Act
@@ -160,6 +297,7 @@ func TestDayScheduleController_GetDaySchedule__success(t *testing.T) {
 	err = json.NewDecoder(resp.Body).Decode(&respDaySchedule)
 	assert.NoError(t, err)
 	assert.Equal(t, daySchedule, respDaySchedule)
+	assert.Equal(t, `"3"`, resp.Header.Get(fiber.HeaderETag))
 	dayStore.AssertExpectations(t)
 }
 
@@ -167,7 +305,7 @@ func TestDayScheduleController_GetAllDaySchedules__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	dayStore := &mocks.MockIDayStore{}
-	controller, err := controllers.NewDayScheduleController(dayStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewDayScheduleController(dayStore, &mocks.MockIShiftStore{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -185,7 +323,7 @@ func TestDayScheduleController_GetAllDaySchedules__success(t *testing.T) {
 			},
 		},
 	}
-	dayStore.On("FindAllDaySchedules").Return(daySchedules, nil)
+	dayStore.On("FindDaySchedules", mock.Anything, store.DayScheduleFilter{}).Return(daySchedules, nil)
 	req := httptest.NewRequest(fiber.MethodGet, controllers.GetAllDaySchedulesRoute, nil)
 
 	// Act
@@ -201,16 +339,43 @@ func TestDayScheduleController_GetAllDaySchedules__success(t *testing.T) {
 	dayStore.AssertExpectations(t)
 }
 
+func TestDayScheduleController_GetAllDaySchedules__filtered(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	dayStore := &mocks.MockIDayStore{}
+	controller, err := controllers.NewDayScheduleController(dayStore, &mocks.MockIShiftStore{}, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	expectedFilter := store.DayScheduleFilter{
+		From:   time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2026, time.October, 8, 0, 0, 0, 0, time.UTC),
+		Shifts: store.ShiftFilter{SoldierID: commanderID},
+	}
+	dayStore.On("FindDaySchedules", mock.Anything, expectedFilter).Return([]models.DaySchedule{}, nil)
+	req := httptest.NewRequest(fiber.MethodGet,
+		controllers.GetAllDaySchedulesRoute+"?from=2026-10-01&to=2026-10-07&soldierId="+commanderID, nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	dayStore.AssertExpectations(t)
+}
+
 func TestDayScheduleController_UpdateDaySchedule__invalid_request_body(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	dayStore := &mocks.MockIDayStore{}
-	controller, err := controllers.NewDayScheduleController(dayStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewDayScheduleController(dayStore, &mocks.MockIShiftStore{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 	date := getStrippedUTCDate()
 	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/day-schedules/%s", date.Format("2006-01-02")), test_utils.WrapStructWithReader(t, "invalid"))
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
 	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
 
 	// Act
@@ -225,7 +390,7 @@ func TestDayScheduleController_UpdateDaySchedule__invalid_date_format(t *testing
 	// Arrange
 	app := fiber.New()
 	dayStore := &mocks.MockIDayStore{}
-	controller, err := controllers.NewDayScheduleController(dayStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewDayScheduleController(dayStore, &mocks.MockIShiftStore{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -244,7 +409,11 @@ func TestDayScheduleController_UpdateDaySchedule__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	dayStore := &mocks.MockIDayStore{}
-	controller, err := controllers.NewDayScheduleController(dayStore, test_utils.AlwaysAllowedJWTMiddleware)
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{}, nil)
+	dayStore.On("FindDaySchedule", mock.Anything, mock.Anything).Return([]models.DaySchedule{}, nil)
+	controller, err := controllers.NewDayScheduleController(dayStore, shiftStore, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -255,8 +424,9 @@ func TestDayScheduleController_UpdateDaySchedule__success(t *testing.T) {
 			{ID: "1", Name: "Updated Shift"},
 		},
 	}
-	dayStore.On("UpdateDaySchedule", mock.AnythingOfType("models.DaySchedule")).Return(nil)
+	dayStore.On("UpdateDaySchedule", mock.Anything, mock.AnythingOfType("models.DaySchedule")).Return(nil)
 	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/day-schedules/%s", date.Format("2006-01-02")), test_utils.WrapStructWithReader(t, daySchedule))
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
 	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
 
 	// Act
@@ -268,11 +438,98 @@ func TestDayScheduleController_UpdateDaySchedule__success(t *testing.T) {
 	dayStore.AssertExpectations(t)
 }
 
+func TestDayScheduleController_UpdateDaySchedule__replaces_own_shifts(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	date := getStrippedUTCDate()
+	replacedShift := testShiftModel
+	replacedShift.ID = "replaced"
+	storedSchedule := models.DaySchedule{Date: date, Shifts: []models.Shift{replacedShift}, Version: 1}
+	dayStore := &mocks.MockIDayStore{}
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{storedSchedule}, nil)
+	dayStore.On("FindDaySchedule", mock.Anything, date).Return([]models.DaySchedule{storedSchedule}, nil)
+	dayStore.On("UpdateDaySchedule", mock.Anything, mock.AnythingOfType("models.DaySchedule")).Return(nil)
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+	controller, err := controllers.NewDayScheduleController(dayStore, shiftStore, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	daySchedule := models.DaySchedule{Date: date, Shifts: []models.Shift{testShiftModel}}
+	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/day-schedules/%s", date.Format("2006-01-02")), test_utils.WrapStructWithReader(t, daySchedule))
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	dayStore.AssertExpectations(t)
+}
+
+func TestDayScheduleController_UpdateDaySchedule__stale_version(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	dayStore := &mocks.MockIDayStore{}
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{}, nil)
+	dayStore.On("FindDaySchedule", mock.Anything, mock.Anything).Return([]models.DaySchedule{}, nil)
+	controller, err := controllers.NewDayScheduleController(dayStore, shiftStore, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	date := getStrippedUTCDate()
+	daySchedule := models.DaySchedule{
+		Date: date,
+		Shifts: []models.Shift{
+			{ID: "1", Name: "Updated Shift"},
+		},
+	}
+	dayStore.On("UpdateDaySchedule", mock.Anything, mock.MatchedBy(func(arg models.DaySchedule) bool {
+		return arg.Version == 1
+	})).Return(fmt.Errorf("day schedule is at version 2: %w", store.ErrVersionConflict))
+	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/day-schedules/%s", date.Format("2006-01-02")), test_utils.WrapStructWithReader(t, daySchedule))
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusPreconditionFailed, resp.StatusCode)
+	dayStore.AssertExpectations(t)
+}
+
+func TestDayScheduleController_UpdateDaySchedule__invalid_if_match(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	dayStore := &mocks.MockIDayStore{}
+	controller, err := controllers.NewDayScheduleController(dayStore, &mocks.MockIShiftStore{}, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	date := getStrippedUTCDate()
+	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/day-schedules/%s", date.Format("2006-01-02")), test_utils.WrapStructWithReader(t, models.DaySchedule{Date: date}))
+	req.Header.Set(fiber.HeaderIfMatch, "*")
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
 func TestDayScheduleController_DeleteDaySchedule__invalid_date_format(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	dayStore := &mocks.MockIDayStore{}
-	controller, err := controllers.NewDayScheduleController(dayStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewDayScheduleController(dayStore, &mocks.MockIShiftStore{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -290,13 +547,14 @@ func TestDayScheduleController_DeleteDaySchedule__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	dayStore := &mocks.MockIDayStore{}
-	controller, err := controllers.NewDayScheduleController(dayStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewDayScheduleController(dayStore, &mocks.MockIShiftStore{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 	date := getStrippedUTCDate()
-	dayStore.On("DeleteDaySchedule", date).Return(nil)
+	dayStore.On("DeleteDaySchedule", mock.Anything, date, 1).Return(nil)
 	req := httptest.NewRequest(fiber.MethodDelete, fmt.Sprintf("/day-schedules/%s", date.Format("2006-01-02")), nil)
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
 
 	// Act
 	resp, err := app.Test(req, test_utils.TestTimeout)
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"brothers_in_batash/internal/pkg/logging"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"brothers_in_batash/internal/pkg/store"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+)
+
+// LoadController reports the duty load soldiers accumulated over a dates range, counting the shifts which start
+// within it
+type LoadController struct {
+	shiftStore     store.IShiftStore
+	dayStore       store.IDayStore
+	soldierStore   store.ISoldierStore
+	authMiddleware fiber.Handler
+}
+
+func NewLoadController(shiftStore store.IShiftStore, dayStore store.IDayStore, soldierStore store.ISoldierStore,
+	authMiddleware fiber.Handler) (*LoadController, error) {
+	if shiftStore == nil {
+		return nil, errors.New("shiftStore is nil")
+	}
+	if dayStore == nil {
+		return nil, errors.New("dayStore is nil")
+	}
+	if soldierStore == nil {
+		return nil, errors.New("soldierStore is nil")
+	}
+	if authMiddleware == nil {
+		return nil, errors.New("authMiddleware is nil")
+	}
+	return &LoadController{shiftStore: shiftStore, dayStore: dayStore, soldierStore: soldierStore, authMiddleware: authMiddleware}, nil
+}
+
+func (c *LoadController) RegisterRoutes(router fiber.Router) error {
+	router.Get(GetSoldierLoadRoute, c.authMiddleware, requireViewer, c.getSoldierLoad)
+	router.Get(GetLoadReportRoute, c.authMiddleware, requireViewer, c.getLoadReport)
+	return nil
+}
+
+func (c *LoadController) getSoldierLoad(ctx *fiber.Ctx) error {
+	soldierID := ctx.Params("id")
+	from, to, ok := parseDateRangeQuery(ctx)
+	if !ok {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	soldiers, err := c.soldierStore.FindSoldierByID(ctx.UserContext(), soldierID)
+	if err != nil {
+		logging.Warning(err, "could not query for soldier", []logging.LogProp{{"soldierID", soldierID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if len(soldiers) == 0 {
+		logging.Trace("Soldier not found", []logging.LogProp{{"soldierID", soldierID}})
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+
+	shifts, err := findAllShifts(ctx.UserContext(), c.shiftStore, c.dayStore)
+	if err != nil {
+		logging.Warning(err, "error on fetching all shifts", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.JSON(scheduling.ComputeLoads([]models.Soldier{soldiers[0]}, shiftsStartingInRange(shifts, from, to))[0])
+}
+
+func (c *LoadController) getLoadReport(ctx *fiber.Ctx) error {
+	from, to, ok := parseDateRangeQuery(ctx)
+	if !ok {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	soldiers, err := c.soldierStore.FindAllSoldiers(ctx.UserContext())
+	if err != nil {
+		logging.Warning(err, "error on fetching all soldiers", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	shifts, err := findAllShifts(ctx.UserContext(), c.shiftStore, c.dayStore)
+	if err != nil {
+		logging.Warning(err, "error on fetching all shifts", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.JSON(scheduling.ComputeLoads(soldiers, shiftsStartingInRange(shifts, from, to)))
+}
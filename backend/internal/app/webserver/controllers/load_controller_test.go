@@ -0,0 +1,144 @@
+package controllers_test
+
+import (
+	"brothers_in_batash/internal/app/webserver/controllers"
+	"brothers_in_batash/internal/pkg/mocks"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"brothers_in_batash/internal/pkg/test_utils"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLoadController(t *testing.T, shiftStore *mocks.MockIShiftStore, dayStore *mocks.MockIDayStore,
+	soldierStore *mocks.MockISoldierStore) *fiber.App {
+	app := fiber.New()
+	controller, err := controllers.NewLoadController(shiftStore, dayStore, soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	return app
+}
+
+func TestLoadController_NewLoadController__sad_flows(t *testing.T) {
+	// Act
+	nilShiftStoreController, nilShiftStoreErr := controllers.NewLoadController(nil, &mocks.MockIDayStore{},
+		&mocks.MockISoldierStore{}, test_utils.AlwaysAllowedJWTMiddleware)
+	nilDayStoreController, nilDayStoreErr := controllers.NewLoadController(&mocks.MockIShiftStore{}, nil,
+		&mocks.MockISoldierStore{}, test_utils.AlwaysAllowedJWTMiddleware)
+	nilSoldierStoreController, nilSoldierStoreErr := controllers.NewLoadController(&mocks.MockIShiftStore{}, &mocks.MockIDayStore{},
+		nil, test_utils.AlwaysAllowedJWTMiddleware)
+	nilMiddlewareController, nilMiddlewareErr := controllers.NewLoadController(&mocks.MockIShiftStore{}, &mocks.MockIDayStore{},
+		&mocks.MockISoldierStore{}, nil)
+
+	// Assert
+	assert.Error(t, nilShiftStoreErr)
+	assert.Nil(t, nilShiftStoreController)
+	assert.Error(t, nilDayStoreErr)
+	assert.Nil(t, nilDayStoreController)
+	assert.Error(t, nilSoldierStoreErr)
+	assert.Nil(t, nilSoldierStoreController)
+	assert.Error(t, nilMiddlewareErr)
+	assert.Nil(t, nilMiddlewareController)
+}
+
+func TestLoadController_GetSoldierLoad__invalid_dates(t *testing.T) {
+	// Arrange
+	app := setupLoadController(t, &mocks.MockIShiftStore{}, &mocks.MockIDayStore{}, &mocks.MockISoldierStore{})
+	req := httptest.NewRequest(fiber.MethodGet, fmt.Sprintf("/soldiers/%s/load?from=2025-04-09", commanderID), nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestLoadController_GetSoldierLoad__not_found(t *testing.T) {
+	// Arrange
+	soldierStore := &mocks.MockISoldierStore{}
+	soldierStore.On("FindSoldierByID", mock.Anything, commanderID).Return([]models.Soldier{}, nil)
+	app := setupLoadController(t, &mocks.MockIShiftStore{}, &mocks.MockIDayStore{}, soldierStore)
+	req := httptest.NewRequest(fiber.MethodGet, fmt.Sprintf("/soldiers/%s/load?from=2025-04-09&to=2025-04-09", commanderID), nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func TestLoadController_GetSoldierLoad__success(t *testing.T) {
+	// Arrange
+	outOfRangeShift := testShiftModel
+	outOfRangeShift.ID = "out-of-range"
+	outOfRangeShift.StartTime = testShiftModel.StartTime.AddDate(0, 0, 1)
+	outOfRangeShift.EndTime = testShiftModel.EndTime.AddDate(0, 0, 1)
+	daySchedShift := testShiftModel
+	daySchedShift.ID = "day-schedule-shift"
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{testShiftModel, outOfRangeShift}, nil)
+	dayStore := &mocks.MockIDayStore{}
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{
+		{Date: getStrippedUTCDate(), Shifts: []models.Shift{testShiftModel, daySchedShift}},
+	}, nil)
+	soldierStore := &mocks.MockISoldierStore{}
+	soldierStore.On("FindSoldierByID", mock.Anything, commanderID).Return([]models.Soldier{testCommander}, nil)
+	app := setupLoadController(t, shiftStore, dayStore, soldierStore)
+	req := httptest.NewRequest(fiber.MethodGet, fmt.Sprintf("/soldiers/%s/load?from=2025-04-09&to=2025-04-09", commanderID), nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var load scheduling.SoldierLoad
+	err = json.NewDecoder(resp.Body).Decode(&load)
+	assert.NoError(t, err)
+	assert.Equal(t, scheduling.SoldierLoad{
+		SoldierID:     commanderID,
+		Shifts:        2,
+		TotalHours:    2,
+		NightHours:    2,
+		ShiftsPerType: map[models.ShiftType]int{models.MotorizedPatrolShiftType: 2},
+	}, load)
+}
+
+func TestLoadController_GetLoadReport__success(t *testing.T) {
+	// Arrange
+	idleSoldier := testCommander
+	idleSoldier.ID = "idle"
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{testShiftModel}, nil)
+	dayStore := &mocks.MockIDayStore{}
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{}, nil)
+	soldierStore := &mocks.MockISoldierStore{}
+	soldierStore.On("FindAllSoldiers", mock.Anything).Return([]models.Soldier{idleSoldier, testCommander}, nil)
+	app := setupLoadController(t, shiftStore, dayStore, soldierStore)
+	req := httptest.NewRequest(fiber.MethodGet, controllers.GetLoadReportRoute+"?from=2025-04-01&to=2025-04-30", nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var loads []scheduling.SoldierLoad
+	err = json.NewDecoder(resp.Body).Decode(&loads)
+	assert.NoError(t, err)
+	require.Len(t, loads, 2)
+	assert.Equal(t, commanderID, loads[0].SoldierID)
+	assert.Equal(t, 1, loads[0].Shifts)
+	assert.Equal(t, idleSoldier.ID, loads[1].SoldierID)
+	assert.Zero(t, loads[1].Shifts)
+}
@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"brothers_in_batash/internal/pkg/logging"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"brothers_in_batash/internal/pkg/store"
+	"brothers_in_batash/internal/pkg/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+)
+
+// RotationController manages the base/home rotations soldiers take part in, and reports who is on base
+type RotationController struct {
+	rotationStore  store.IRotationStore
+	soldierStore   store.ISoldierStore
+	leaveStore     store.ILeaveStore
+	authMiddleware fiber.Handler
+}
+
+func NewRotationController(rotationStore store.IRotationStore, soldierStore store.ISoldierStore, leaveStore store.ILeaveStore,
+	authMiddleware fiber.Handler) (*RotationController, error) {
+	if rotationStore == nil {
+		return nil, errors.New("rotationStore is nil")
+	}
+	if soldierStore == nil {
+		return nil, errors.New("soldierStore is nil")
+	}
+	if leaveStore == nil {
+		return nil, errors.New("leaveStore is nil")
+	}
+	if authMiddleware == nil {
+		return nil, errors.New("authMiddleware is nil")
+	}
+	return &RotationController{
+		rotationStore:  rotationStore,
+		soldierStore:   soldierStore,
+		leaveStore:     leaveStore,
+		authMiddleware: authMiddleware,
+	}, nil
+}
+
+func (c *RotationController) RegisterRoutes(router fiber.Router) error {
+	// Registered before GetRotationRoute, so "on-base" is not taken for a rotation ID
+	router.Get(GetOnBaseSoldiersRoute, c.authMiddleware, requireViewer, c.getOnBaseSoldiers)
+	router.Post(CreateRotationRoute, c.authMiddleware, requireSquadCommander, c.createRotation)
+	router.Get(GetRotationRoute, c.authMiddleware, requireViewer, c.getRotation)
+	router.Get(GetAllRotationsRoute, c.authMiddleware, requireViewer, c.getAllRotations)
+	router.Put(UpdateRotationRoute, c.authMiddleware, requireSquadCommander, c.updateRotation)
+	router.Delete(DeleteRotationRoute, c.authMiddleware, requireSquadCommander, c.deleteRotation)
+	return nil
+}
+
+func (c *RotationController) createRotation(ctx *fiber.Ctx) error {
+	rotation := models.Rotation{}
+	if err := ctx.BodyParser(&rotation); err != nil {
+		logging.Debug("Could not parse rotation creation request body", []logging.LogProp{{"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	rotation.ID = utils.NewEntityID()
+	if err := rotation.IsValid(); err != nil {
+		logging.Debug("Invalid rotation", []logging.LogProp{{"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if err := c.rotationStore.CreateNewRotation(ctx.UserContext(), rotation); err != nil {
+		logging.Warning(err, "error on creating new rotation", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	return sendCreated(ctx, rotation.ID, rotation)
+}
+
+func (c *RotationController) getRotation(ctx *fiber.Ctx) error {
+	rotationID := ctx.Params("id")
+	rotations, err := c.rotationStore.FindRotationByID(ctx.UserContext(), rotationID)
+	if err != nil {
+		logging.Warning(err, "could not query for rotation", []logging.LogProp{{"rotationID", rotationID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if len(rotations) == 0 {
+		logging.Trace("Rotation not found", []logging.LogProp{{"rotationID", rotationID}})
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	return ctx.JSON(rotations[0])
+}
+
+func (c *RotationController) getAllRotations(ctx *fiber.Ctx) error {
+	rotations, err := c.rotationStore.FindAllRotations(ctx.UserContext())
+	if err != nil {
+		logging.Warning(err, "error on fetching all rotations", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	return ctx.JSON(rotations)
+}
+
+func (c *RotationController) updateRotation(ctx *fiber.Ctx) error {
+	rotationID := ctx.Params("id")
+	rotation := models.Rotation{}
+	if err := ctx.BodyParser(&rotation); err != nil {
+		logging.Info("Could not parse rotation update request body", []logging.LogProp{{"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	rotation.ID = rotationID
+	if err := rotation.IsValid(); err != nil {
+		logging.Debug("Invalid rotation", []logging.LogProp{{"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	if rotations, err := c.rotationStore.FindRotationByID(ctx.UserContext(), rotationID); err != nil {
+		logging.Warning(err, "could not query existing rotation on update", []logging.LogProp{{"rotationID", rotationID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if len(rotations) == 0 {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+
+	if err := c.rotationStore.UpdateRotation(ctx.UserContext(), rotation); err != nil {
+		logging.Warning(err, "error on updating rotation", []logging.LogProp{{"rotationID", rotationID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	return ctx.SendStatus(fiber.StatusOK)
+}
+
+func (c *RotationController) deleteRotation(ctx *fiber.Ctx) error {
+	rotationID := ctx.Params("id")
+	if err := c.rotationStore.DeleteRotation(ctx.UserContext(), rotationID); err != nil {
+		logging.Warning(err, "error on deleting rotation", []logging.LogProp{{"rotationID", rotationID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	return ctx.SendStatus(fiber.StatusOK)
+}
+
+// getOnBaseSoldiers returns the soldiers present on base on the requested date, according to their rotations and leaves
+func (c *RotationController) getOnBaseSoldiers(ctx *fiber.Ctx) error {
+	date, err := time.Parse("2006-01-02", ctx.Query("date"))
+	if err != nil {
+		logging.Debug("Invalid date format", []logging.LogProp{{"date", ctx.Query("date")}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	soldiers, err := c.soldierStore.FindAllSoldiers(ctx.UserContext())
+	if err != nil {
+		logging.Warning(err, "error on fetching all soldiers", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	rotations, err := c.rotationStore.FindAllRotations(ctx.UserContext())
+	if err != nil {
+		logging.Warning(err, "error on fetching all rotations", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	leaves, err := c.leaveStore.FindAllLeaves(ctx.UserContext())
+	if err != nil {
+		logging.Warning(err, "error on fetching all leaves", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	onBase := make([]models.Soldier, 0)
+	for _, soldier := range soldiers {
+		if scheduling.IsOnBase(soldier.ID, date, rotations, leaves) {
+			onBase = append(onBase, soldier)
+		}
+	}
+	return ctx.JSON(onBase)
+}
@@ -0,0 +1,149 @@
+package controllers_test
+
+import (
+	"brothers_in_batash/internal/app/webserver/controllers"
+	"brothers_in_batash/internal/pkg/mocks"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/test_utils"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testRotation = models.Rotation{
+	ID:         "rotation",
+	Name:       "Alpha squad",
+	SoldierIDs: []string{commanderID},
+	OnBaseDays: 11,
+	HomeDays:   3,
+	StartDate:  time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func setupRotationController(t *testing.T, rotationStore *mocks.MockIRotationStore, soldierStore *mocks.MockISoldierStore,
+	leaveStore *mocks.MockILeaveStore) *fiber.App {
+	app := fiber.New()
+	controller, err := controllers.NewRotationController(rotationStore, soldierStore, leaveStore, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	return app
+}
+
+func TestRotationController_NewRotationController__sad_flows(t *testing.T) {
+	// Act
+	nilRotationStoreController, nilRotationStoreErr := controllers.NewRotationController(nil, &mocks.MockISoldierStore{},
+		&mocks.MockILeaveStore{}, test_utils.AlwaysAllowedJWTMiddleware)
+	nilSoldierStoreController, nilSoldierStoreErr := controllers.NewRotationController(&mocks.MockIRotationStore{}, nil,
+		&mocks.MockILeaveStore{}, test_utils.AlwaysAllowedJWTMiddleware)
+	nilLeaveStoreController, nilLeaveStoreErr := controllers.NewRotationController(&mocks.MockIRotationStore{},
+		&mocks.MockISoldierStore{}, nil, test_utils.AlwaysAllowedJWTMiddleware)
+	nilMiddlewareController, nilMiddlewareErr := controllers.NewRotationController(&mocks.MockIRotationStore{},
+		&mocks.MockISoldierStore{}, &mocks.MockILeaveStore{}, nil)
+
+	// Assert
+	assert.Error(t, nilRotationStoreErr)
+	assert.Nil(t, nilRotationStoreController)
+	assert.Error(t, nilSoldierStoreErr)
+	assert.Nil(t, nilSoldierStoreController)
+	assert.Error(t, nilLeaveStoreErr)
+	assert.Nil(t, nilLeaveStoreController)
+	assert.Error(t, nilMiddlewareErr)
+	assert.Nil(t, nilMiddlewareController)
+}
+
+func TestRotationController_CreateRotation__success(t *testing.T) {
+	// Arrange
+	rotationStore := &mocks.MockIRotationStore{}
+	var createdID string
+	rotationStore.On("CreateNewRotation", mock.Anything, mock.MatchedBy(func(arg models.Rotation) bool {
+		return arg.ID != "" && arg.ID != testRotation.ID
+	})).Run(func(args mock.Arguments) {
+		createdID = args.Get(1).(models.Rotation).ID
+	}).Return(nil)
+	app := setupRotationController(t, rotationStore, &mocks.MockISoldierStore{}, &mocks.MockILeaveStore{})
+	req := httptest.NewRequest(fiber.MethodPost, controllers.CreateRotationRoute, test_utils.WrapStructWithReader(t, testRotation))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Equal(t, controllers.CreateRotationRoute+"/"+createdID, resp.Header.Get(fiber.HeaderLocation))
+	var createdRotation models.Rotation
+	err = json.NewDecoder(resp.Body).Decode(&createdRotation)
+	require.NoError(t, err)
+	assert.Equal(t, createdID, createdRotation.ID)
+	rotationStore.AssertExpectations(t)
+}
+
+func TestRotationController_CreateRotation__invalid_rotation(t *testing.T) {
+	// Arrange
+	rotation := testRotation
+	rotation.OnBaseDays = 0
+	app := setupRotationController(t, &mocks.MockIRotationStore{}, &mocks.MockISoldierStore{}, &mocks.MockILeaveStore{})
+	req := httptest.NewRequest(fiber.MethodPost, controllers.CreateRotationRoute, test_utils.WrapStructWithReader(t, rotation))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestRotationController_GetOnBaseSoldiers__invalid_date(t *testing.T) {
+	// Arrange
+	app := setupRotationController(t, &mocks.MockIRotationStore{}, &mocks.MockISoldierStore{}, &mocks.MockILeaveStore{})
+	req := httptest.NewRequest(fiber.MethodGet, controllers.GetOnBaseSoldiersRoute+"?date=today", nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestRotationController_GetOnBaseSoldiers__success(t *testing.T) {
+	testCases := []struct {
+		name     string
+		date     string
+		expected []models.Soldier
+	}{
+		{name: "on base day", date: "2025-04-11", expected: []models.Soldier{testCommander}},
+		{name: "home day", date: "2025-04-12", expected: []models.Soldier{}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			rotationStore := &mocks.MockIRotationStore{}
+			rotationStore.On("FindAllRotations", mock.Anything).Return([]models.Rotation{testRotation}, nil)
+			soldierStore := &mocks.MockISoldierStore{}
+			soldierStore.On("FindAllSoldiers", mock.Anything).Return([]models.Soldier{testCommander}, nil)
+			leaveStore := &mocks.MockILeaveStore{}
+			leaveStore.On("FindAllLeaves", mock.Anything).Return([]models.Leave{}, nil)
+			app := setupRotationController(t, rotationStore, soldierStore, leaveStore)
+			req := httptest.NewRequest(fiber.MethodGet, controllers.GetOnBaseSoldiersRoute+"?date="+testCase.date, nil)
+
+			// Act
+			resp, err := app.Test(req, test_utils.TestTimeout)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+			var soldiers []models.Soldier
+			err = json.NewDecoder(resp.Body).Decode(&soldiers)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, soldiers)
+		})
+	}
+}
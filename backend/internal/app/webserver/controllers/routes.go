@@ -3,7 +3,11 @@ package controllers
 import (
 	"brothers_in_batash/internal/pkg/config"
 	"brothers_in_batash/internal/pkg/middleware/jwt"
+	"brothers_in_batash/internal/pkg/scheduling"
 	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"database/sql"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/pkg/errors"
@@ -22,6 +26,7 @@ const (
 	GetAllShiftsRoute = "/shifts"
 	UpdateShiftRoute  = "/shifts/:id"
 	DeleteShiftRoute  = "/shifts/:id"
+	RestoreShiftRoute = "/shifts/:id/restore"
 
 	CreateDayScheduleRoute  = "/day-schedules"
 	GetDayScheduleRoute     = "/day-schedules/:date"
@@ -34,12 +39,38 @@ const (
 	GetAllSoldiersRoute = "/soldiers"
 	UpdateSoldierRoute  = "/soldiers/:id"
 	DeleteSoldierRoute  = "/soldiers/:id"
+	RestoreSoldierRoute = "/soldiers/:id/restore"
 
 	CreateShiftTemplateRoute  = "/shift-templates"
 	GetShiftTemplateRoute     = "/shift-templates/:id"
 	GetAllShiftTemplatesRoute = "/shift-templates"
 	UpdateShiftTemplateRoute  = "/shift-templates/:id"
 	DeleteShiftTemplateRoute  = "/shift-templates/:id"
+	RestoreShiftTemplateRoute = "/shift-templates/:id/restore"
+
+	GenerateShiftsFromTemplateRoute = "/shift-templates/:id/generate"
+
+	AutoAssignRoute = "/schedule/auto-assign"
+
+	GetConflictsRoute = "/conflicts"
+
+	CreateSoldierLeaveRoute  = "/soldiers/:id/availability"
+	GetSoldierLeaveRoute     = "/soldiers/:id/availability/:leaveId"
+	GetAllSoldierLeavesRoute = "/soldiers/:id/availability"
+	UpdateSoldierLeaveRoute  = "/soldiers/:id/availability/:leaveId"
+	DeleteSoldierLeaveRoute  = "/soldiers/:id/availability/:leaveId"
+
+	CreateRotationRoute    = "/rotations"
+	GetRotationRoute       = "/rotations/:id"
+	GetAllRotationsRoute   = "/rotations"
+	UpdateRotationRoute    = "/rotations/:id"
+	DeleteRotationRoute    = "/rotations/:id"
+	GetOnBaseSoldiersRoute = "/rotations/on-base"
+
+	GetSoldierLoadRoute = "/soldiers/:id/load"
+	GetLoadReportRoute  = "/reports/load"
+
+	GetAuditEntriesRoute = "/audit"
 )
 
 type Controller interface {
@@ -52,6 +83,13 @@ type storeInstancesContainer struct {
 	soldierStore       store.ISoldierStore
 	userStore          store.IUserStore
 	ShiftTemplateStore store.IShiftTemplateStore
+	leaveStore         store.ILeaveStore
+	rotationStore      store.IRotationStore
+	transactor         store.ITransactor
+	auditStore         store.IAuditStore
+	revokedTokenStore  store.IRevokedTokenStore
+	// close releases the stores' resources, flushing whatever is not yet persisted
+	close func() error
 }
 
 func SetupRoutes(v1Router fiber.Router, controllers []Controller) error {
@@ -63,48 +101,291 @@ func SetupRoutes(v1Router fiber.Router, controllers []Controller) error {
 	return nil
 }
 
-func InitControllers() (controllers []Controller, err error) {
-	storeInstances, err := initStoreInstances()
+// InitControllers initializes all the controllers, along with the stores they share. closeStores must be called once
+// the controllers are no longer serving requests.
+func InitControllers(cfg config.Config) (controllers []Controller, closeStores func() error, err error) {
+	storeInstances, err := initStoreInstances(cfg)
 	if err != nil {
 		return
 	}
+	stopPurger, err := startPurger(storeInstances)
+	if err != nil {
+		return nil, storeInstances.close, errors.Wrap(err, "failed to start purger")
+	}
+	stopTokenPruner, err := startTokenPruner(storeInstances)
+	if err != nil {
+		stopPurger()
+		return nil, storeInstances.close, errors.Wrap(err, "failed to start token pruner")
+	}
+	closeStores = func() error {
+		stopTokenPruner()
+		stopPurger()
+		return storeInstances.close()
+	}
 
-	authMiddleware := jwt.NewAuthMiddleware(config.JWTSecret)
+	err = BootstrapAdmin(context.Background(), storeInstances.userStore, cfg.AdminUsername, cfg.AdminPassword)
+	if err != nil {
+		return nil, closeStores, errors.Wrap(err, "failed to bootstrap admin")
+	}
 
-	registrationController, err := NewRegistrationController(storeInstances.userStore)
+	tokenIssuer, err := jwt.NewTokenIssuer(cfg.JWTSecret, cfg.AccessTokenLifetime, cfg.RefreshTokenLifetime)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to initialize registration controller")
+		return nil, closeStores, errors.Wrap(err, "failed to initialize token issuer")
+	}
+	authMiddleware := jwt.NewAuthMiddleware(tokenIssuer, storeInstances.revokedTokenStore)
+
+	registrationController, err := NewRegistrationController(storeInstances.userStore, storeInstances.revokedTokenStore,
+		tokenIssuer)
+	if err != nil {
+		return nil, closeStores, errors.Wrap(err, "failed to initialize registration controller")
 	}
 	controllers = append(controllers, registrationController)
 
-	dayScheduleController, err := NewDayScheduleController(storeInstances.dayStore, authMiddleware)
+	dayScheduleController, err := NewDayScheduleController(storeInstances.dayStore, storeInstances.shiftStore, authMiddleware)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to initialize day schedule controller")
+		return nil, closeStores, errors.Wrap(err, "failed to initialize day schedule controller")
 	}
 	controllers = append(controllers, dayScheduleController)
 
-	shiftController, err := NewShiftController(storeInstances.shiftStore, storeInstances.soldierStore, authMiddleware)
+	restPolicy, err := newRestPolicy(cfg)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to initialize shift controller")
+		return nil, closeStores, errors.Wrap(err, "failed to initialize rest policy")
+	}
+
+	shiftController, err := NewShiftController(storeInstances.shiftStore, storeInstances.dayStore, storeInstances.soldierStore,
+		storeInstances.ShiftTemplateStore, storeInstances.leaveStore, storeInstances.rotationStore, restPolicy,
+		scheduling.StaffingMode(cfg.StaffingMode), authMiddleware)
+	if err != nil {
+		return nil, closeStores, errors.Wrap(err, "failed to initialize shift controller")
 	}
 	controllers = append(controllers, shiftController)
 
-	soldierController, err := NewSoldierController(storeInstances.soldierStore, authMiddleware)
+	soldierController, err := NewSoldierController(storeInstances.soldierStore, storeInstances.transactor,
+		scheduling.SoldierDeletePolicy(cfg.SoldierDeletePolicy), authMiddleware)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to initialize soldier controller")
+		return nil, closeStores, errors.Wrap(err, "failed to initialize soldier controller")
 	}
 	controllers = append(controllers, soldierController)
 
-	shiftTemplateController, err := NewShiftTemplateController(storeInstances.ShiftTemplateStore, authMiddleware)
+	shiftTemplateController, err := NewShiftTemplateController(storeInstances.ShiftTemplateStore, storeInstances.shiftStore,
+		storeInstances.transactor, authMiddleware)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to initialize shift template controller")
+		return nil, closeStores, errors.Wrap(err, "failed to initialize shift template controller")
 	}
 	controllers = append(controllers, shiftTemplateController)
 
+	scorer, err := newLoadScorer(cfg)
+	if err != nil {
+		return nil, closeStores, errors.Wrap(err, "failed to initialize load scorer")
+	}
+	solver, err := scheduling.NewSolver(scorer, scheduling.NoOverlapConstraint, restPolicy.Allows)
+	if err != nil {
+		return nil, closeStores, errors.Wrap(err, "failed to initialize assignment solver")
+	}
+	scheduleController, err := NewScheduleController(storeInstances.shiftStore, storeInstances.dayStore,
+		storeInstances.soldierStore, storeInstances.ShiftTemplateStore, storeInstances.leaveStore, storeInstances.rotationStore,
+		storeInstances.transactor, solver, authMiddleware)
+	if err != nil {
+		return nil, closeStores, errors.Wrap(err, "failed to initialize schedule controller")
+	}
+	controllers = append(controllers, scheduleController)
+
+	conflictController, err := NewConflictController(storeInstances.shiftStore, storeInstances.dayStore, authMiddleware)
+	if err != nil {
+		return nil, closeStores, errors.Wrap(err, "failed to initialize conflict controller")
+	}
+	controllers = append(controllers, conflictController)
+
+	loadController, err := NewLoadController(storeInstances.shiftStore, storeInstances.dayStore, storeInstances.soldierStore,
+		authMiddleware)
+	if err != nil {
+		return nil, closeStores, errors.Wrap(err, "failed to initialize load controller")
+	}
+	controllers = append(controllers, loadController)
+
+	availabilityController, err := NewAvailabilityController(storeInstances.leaveStore, storeInstances.soldierStore, authMiddleware)
+	if err != nil {
+		return nil, closeStores, errors.Wrap(err, "failed to initialize availability controller")
+	}
+	controllers = append(controllers, availabilityController)
+
+	rotationController, err := NewRotationController(storeInstances.rotationStore, storeInstances.soldierStore,
+		storeInstances.leaveStore, authMiddleware)
+	if err != nil {
+		return nil, closeStores, errors.Wrap(err, "failed to initialize rotation controller")
+	}
+	controllers = append(controllers, rotationController)
+
+	auditController, err := NewAuditController(storeInstances.auditStore, authMiddleware)
+	if err != nil {
+		return nil, closeStores, errors.Wrap(err, "failed to initialize audit controller")
+	}
+	controllers = append(controllers, auditController)
+
 	return
 }
 
-func initStoreInstances() (storeInstancesContainer, error) {
+func newRestPolicy(cfg config.Config) (*scheduling.RestPolicy, error) {
+	return scheduling.NewRestPolicy(
+		scheduling.RestRule{MinRest: cfg.MinRestAfterShift},
+		scheduling.RestRule{NightOnly: true, MinRest: cfg.MinRestAfterNightShift},
+	)
+}
+
+func newLoadScorer(cfg config.Config) (*scheduling.Scorer, error) {
+	holidays := make([]time.Time, 0, len(cfg.Holidays))
+	for _, holiday := range cfg.Holidays {
+		date, err := time.Parse("2006-01-02", holiday)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid holiday date %s", holiday)
+		}
+		holidays = append(holidays, date)
+	}
+	return scheduling.NewScorer(scheduling.LoadWeights{
+		Hour:         cfg.HourLoadWeight,
+		NightHour:    cfg.NightHourLoadWeight,
+		ShabbatShift: cfg.ShabbatShiftLoadWeight,
+		HolidayShift: cfg.HolidayShiftLoadWeight,
+	}, holidays...)
+}
+
+func initStoreInstances(cfg config.Config) (storeInstancesContainer, error) {
+	switch cfg.StoreBackend {
+	case store.InMemBackend:
+		return initInMemStoreInstances(cfg.SnapshotPath)
+	case store.SQLiteBackend:
+		db, err := store.OpenSQLiteDB(cfg.SQLitePath)
+		if err != nil {
+			return storeInstancesContainer{}, errors.Wrap(err, "failed to open sqlite database")
+		}
+		storeInstances, err := initSQLiteStoreInstances(db)
+		if err != nil {
+			_ = db.Close()
+			return storeInstancesContainer{}, err
+		}
+		return storeInstances, nil
+	default:
+		return storeInstancesContainer{}, errors.Errorf("unknown store backend %s", cfg.StoreBackend)
+	}
+}
+
+// decorateStores layers the behaviors all the store backends share on top of their stores
+func decorateStores(storeInstances storeInstancesContainer) (storeInstancesContainer, error) {
+	storeInstances, err := withRefs(storeInstances)
+	if err != nil {
+		return storeInstancesContainer{}, err
+	}
+	return withAudit(storeInstances)
+}
+
+// withRefs wraps the stores of shifts and day schedules, and the transactor, so that the soldiers and shifts they
+// reference are resolved whenever they are read
+func withRefs(storeInstances storeInstancesContainer) (storeInstancesContainer, error) {
+	var err error
+	if storeInstances.dayStore, err = store.NewResolvingDaySchedStore(storeInstances.dayStore, storeInstances.shiftStore,
+		storeInstances.soldierStore); err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to resolve day schedule store references")
+	}
+	if storeInstances.shiftStore, err = store.NewResolvingShiftStore(storeInstances.shiftStore, storeInstances.soldierStore); err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to resolve shift store soldiers")
+	}
+	if storeInstances.transactor, err = store.NewResolvingTransactor(storeInstances.transactor); err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to resolve transactor references")
+	}
+	return storeInstances, nil
+}
+
+// withAudit wraps the transactor so that every change its units of work make is recorded in the audit store, and the
+// stores of audited entities so that each of their writes is recorded along with it, in a single transaction
+func withAudit(storeInstances storeInstancesContainer) (storeInstancesContainer, error) {
+	auditedTransactor, err := store.NewAuditedTransactor(storeInstances.transactor)
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to audit transactor")
+	}
+	storeInstances.transactor = auditedTransactor
+	if storeInstances.dayStore, err = store.NewTxAuditedDaySchedStore(storeInstances.dayStore, auditedTransactor); err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to audit day schedule store")
+	}
+	if storeInstances.shiftStore, err = store.NewTxAuditedShiftStore(storeInstances.shiftStore, auditedTransactor); err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to audit shift store")
+	}
+	if storeInstances.soldierStore, err = store.NewTxAuditedSoldierStore(storeInstances.soldierStore, auditedTransactor); err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to audit soldier store")
+	}
+	if storeInstances.ShiftTemplateStore, err = store.NewTxAuditedShiftTemplateStore(storeInstances.ShiftTemplateStore,
+		auditedTransactor); err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to audit shift template store")
+	}
+	return storeInstances, nil
+}
+
+func initSQLiteStoreInstances(db *sql.DB) (storeInstancesContainer, error) {
+	userStore, err := store.NewSQLiteUserStore(db)
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize user store")
+	}
+
+	daySchedStore, err := store.NewSQLiteDaySchedStore(db)
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize day schedule store")
+	}
+
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize shift store")
+	}
+
+	soldierStore, err := store.NewSQLiteSoldierStore(db)
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize soldier store")
+	}
+
+	shiftTemplateStore, err := store.NewSQLiteShiftTemplateStore(db)
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize shift template store")
+	}
+
+	leaveStore, err := store.NewSQLiteLeaveStore(db)
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize leave store")
+	}
+
+	rotationStore, err := store.NewSQLiteRotationStore(db)
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize rotation store")
+	}
+
+	auditStore, err := store.NewSQLiteAuditStore(db)
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize audit store")
+	}
+
+	revokedTokenStore, err := store.NewSQLiteRevokedTokenStore(db)
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize revoked token store")
+	}
+
+	transactor, err := store.NewSQLiteTransactor(db)
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize transactor")
+	}
+
+	return decorateStores(storeInstancesContainer{
+		dayStore:           daySchedStore,
+		shiftStore:         shiftStore,
+		soldierStore:       soldierStore,
+		userStore:          userStore,
+		ShiftTemplateStore: shiftTemplateStore,
+		leaveStore:         leaveStore,
+		rotationStore:      rotationStore,
+		transactor:         transactor,
+		auditStore:         auditStore,
+		revokedTokenStore:  revokedTokenStore,
+		close:              db.Close,
+	})
+}
+
+func initInMemStoreInstances(snapshotPath string) (storeInstancesContainer, error) {
 	userStore, err := store.NewUserStore()
 	if err != nil {
 		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize user store")
@@ -130,11 +411,97 @@ func initStoreInstances() (storeInstancesContainer, error) {
 		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize shift template store")
 	}
 
-	return storeInstancesContainer{
+	leaveStore, err := store.NewLeaveStore()
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize leave store")
+	}
+
+	rotationStore, err := store.NewRotationStore()
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize rotation store")
+	}
+
+	auditStore, err := store.NewAuditStore()
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize audit store")
+	}
+
+	revokedTokenStore, err := store.NewRevokedTokenStore()
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize revoked token store")
+	}
+
+	inMemStores := store.InMemStores{
+		DaySchedules:   daySchedStore,
+		Shifts:         shiftStore,
+		Soldiers:       soldierStore,
+		ShiftTemplates: shiftTemplateStore,
+		Users:          userStore,
+		Leaves:         leaveStore,
+		Rotations:      rotationStore,
+		Audit:          auditStore,
+		RevokedTokens:  revokedTokenStore,
+	}
+	transactor, err := store.NewInMemTransactor(inMemStores)
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to initialize transactor")
+	}
+
+	closeStores, err := startSnapshots(inMemStores, snapshotPath)
+	if err != nil {
+		return storeInstancesContainer{}, errors.Wrap(err, "failed to start snapshots")
+	}
+
+	return decorateStores(storeInstancesContainer{
 		dayStore:           daySchedStore,
 		shiftStore:         shiftStore,
 		soldierStore:       soldierStore,
 		userStore:          userStore,
 		ShiftTemplateStore: shiftTemplateStore,
-	}, nil
+		leaveStore:         leaveStore,
+		rotationStore:      rotationStore,
+		transactor:         transactor,
+		auditStore:         auditStore,
+		revokedTokenStore:  revokedTokenStore,
+		close:              closeStores,
+	})
+}
+
+// startPurger periodically purges the deleted entities whose retention window has passed. The returned function stops it.
+func startPurger(storeInstances storeInstancesContainer) (stop func(), err error) {
+	purger, err := store.NewPurger(storeInstances.shiftStore, storeInstances.soldierStore, storeInstances.ShiftTemplateStore,
+		config.DeletedRetention)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go purger.Run(ctx, config.PurgeInterval)
+	return cancel, nil
+}
+
+// startTokenPruner periodically forgets the revoked tokens that have expired. The returned function stops it.
+func startTokenPruner(storeInstances storeInstancesContainer) (stop func(), err error) {
+	pruner, err := store.NewTokenPruner(storeInstances.revokedTokenStore)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go pruner.Run(ctx, config.RevokedTokenPruneInterval)
+	return cancel, nil
+}
+
+// startSnapshots loads the last snapshot into the in-memory stores and keeps saving new ones periodically. The
+// returned function stops the periodic snapshots and saves a final one. Snapshots are disabled if no path is configured.
+func startSnapshots(stores store.InMemStores, path string) (stop func() error, err error) {
+	if path == "" {
+		return func() error { return nil }, nil
+	}
+	snapshotter, err := store.NewSnapshotter(path, stores)
+	if err != nil {
+		return nil, err
+	}
+	if err := snapshotter.Load(); err != nil {
+		return nil, errors.Wrap(err, "failed to load snapshot")
+	}
+	return snapshotter.Start(config.SnapshotInterval), nil
 }
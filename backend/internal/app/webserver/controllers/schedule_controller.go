@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"brothers_in_batash/internal/app/webserver/api"
+	"brothers_in_batash/internal/pkg/logging"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+)
+
+type ScheduleController struct {
+	shiftStore         store.IShiftStore
+	dayStore           store.IDayStore
+	soldierStore       store.ISoldierStore
+	shiftTemplateStore store.IShiftTemplateStore
+	leaveStore         store.ILeaveStore
+	rotationStore      store.IRotationStore
+	transactor         store.ITransactor
+	solver             *scheduling.Solver
+	authMiddleware     fiber.Handler
+}
+
+func NewScheduleController(shiftStore store.IShiftStore, dayStore store.IDayStore, soldierStore store.ISoldierStore,
+	shiftTemplateStore store.IShiftTemplateStore, leaveStore store.ILeaveStore, rotationStore store.IRotationStore,
+	transactor store.ITransactor, solver *scheduling.Solver, authMiddleware fiber.Handler) (*ScheduleController, error) {
+	if shiftStore == nil {
+		return nil, errors.New("shiftStore is nil")
+	}
+	if dayStore == nil {
+		return nil, errors.New("dayStore is nil")
+	}
+	if soldierStore == nil {
+		return nil, errors.New("soldierStore is nil")
+	}
+	if shiftTemplateStore == nil {
+		return nil, errors.New("shiftTemplateStore is nil")
+	}
+	if leaveStore == nil {
+		return nil, errors.New("leaveStore is nil")
+	}
+	if rotationStore == nil {
+		return nil, errors.New("rotationStore is nil")
+	}
+	if transactor == nil {
+		return nil, errors.New("transactor is nil")
+	}
+	if solver == nil {
+		return nil, errors.New("solver is nil")
+	}
+	if authMiddleware == nil {
+		return nil, errors.New("authMiddleware is nil")
+	}
+	return &ScheduleController{
+		shiftStore:         shiftStore,
+		dayStore:           dayStore,
+		soldierStore:       soldierStore,
+		shiftTemplateStore: shiftTemplateStore,
+		leaveStore:         leaveStore,
+		rotationStore:      rotationStore,
+		transactor:         transactor,
+		solver:             solver,
+		authMiddleware:     authMiddleware,
+	}, nil
+}
+
+func (c *ScheduleController) RegisterRoutes(router fiber.Router) error {
+	router.Post(AutoAssignRoute, c.authMiddleware, requireSquadCommander, c.autoAssign)
+	return nil
+}
+
+func (c *ScheduleController) autoAssign(ctx *fiber.Ctx) error {
+	reqBody := api.AutoAssignReqBody{}
+	if err := ctx.BodyParser(&reqBody); err != nil {
+		logging.Debug("Could not parse auto assign request body", []logging.LogProp{{"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	if err := validator.New().Struct(reqBody); err != nil {
+		logging.Debug("Auto assign request body failed validation", []logging.LogProp{{"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	from, _ := time.Parse("2006-01-02", reqBody.From)
+	to, _ := time.Parse("2006-01-02", reqBody.To)
+	if to.Before(from) {
+		logging.Debug("Auto assign range end is before range start", []logging.LogProp{{"from", reqBody.From}, {"to", reqBody.To}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	shifts, err := c.shiftStore.FindAllShifts(ctx.UserContext())
+	if err != nil {
+		logging.Warning(err, "error on fetching all shifts", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	// Soldiers booked in day schedule shifts are just as unavailable, even though only standalone shifts are staffed
+	bookedShifts, err := findAllShifts(ctx.UserContext(), c.shiftStore, c.dayStore)
+	if err != nil {
+		logging.Warning(err, "error on fetching all booked shifts", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	soldiers, err := c.soldierStore.FindAllSoldiers(ctx.UserContext())
+	if err != nil {
+		logging.Warning(err, "error on fetching all soldiers", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	templates, err := c.shiftTemplateStore.FindAllShiftsTemplate(ctx.UserContext())
+	if err != nil {
+		logging.Warning(err, "error on fetching all shift templates", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	leaves, err := c.leaveStore.FindAllLeaves(ctx.UserContext())
+	if err != nil {
+		logging.Warning(err, "error on fetching all leaves", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	rotations, err := c.rotationStore.FindAllRotations(ctx.UserContext())
+	if err != nil {
+		logging.Warning(err, "error on fetching all rotations", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	shiftsToStaff := shiftsStartingInRange(shifts, from, to)
+	spanStart, spanEnd := shiftsSpan(shiftsToStaff)
+	leaves = append(leaves, scheduling.RotationLeaves(rotations, spanStart, spanEnd)...)
+	solver, err := c.solver.WithConstraints(scheduling.AvailabilityConstraint(leaves))
+	if err != nil {
+		logging.Warning(err, "error on applying availability constraint", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	result := solver.Solve(shiftsToStaff, bookedShifts, soldiers, templates)
+	if !reqBody.DryRun {
+		if err := c.saveAssignments(ctx.UserContext(), shifts, result.Assignments); errors.Is(err, store.ErrVersionConflict) {
+			logging.Debug("shifts were modified while auto assigning", []logging.LogProp{{"error", err.Error()}})
+			return ctx.SendStatus(fiber.StatusConflict)
+		} else if err != nil {
+			logging.Warning(err, "error on saving auto assigned shifts", nil)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+	return ctx.JSON(api.AutoAssignRespBody{DryRun: reqBody.DryRun, AssignmentResult: result})
+}
+
+// saveAssignments updates all the assigned shifts together, so a failure never leaves the roster half assigned
+func (c *ScheduleController) saveAssignments(ctx context.Context, shifts []models.Shift, assignments []scheduling.ShiftAssignment) error {
+	shiftsByID := make(map[string]models.Shift, len(shifts))
+	for _, shift := range shifts {
+		shiftsByID[shift.ID] = shift
+	}
+	return c.transactor.WithinTx(ctx, func(stores store.TxStores) error {
+		for _, assignment := range assignments {
+			if err := stores.Shifts.UpdateShift(ctx, assignment.ApplyTo(shiftsByID[assignment.ShiftID])); err != nil {
+				return errors.Wrapf(err, "could not update shift %s", assignment.ShiftID)
+			}
+		}
+		return nil
+	})
+}
+
+// shiftsStartingInRange returns the shifts starting within the inclusive [from, to] dates range
+func shiftsStartingInRange(shifts []models.Shift, from, to time.Time) []models.Shift {
+	rangeEnd := to.AddDate(0, 0, 1)
+	inRange := make([]models.Shift, 0)
+	for _, shift := range shifts {
+		if !shift.StartTime.Before(from) && shift.StartTime.Before(rangeEnd) {
+			inRange = append(inRange, shift)
+		}
+	}
+	return inRange
+}
+
+// shiftsSpan returns the time range from the earliest start to the latest end of shifts
+func shiftsSpan(shifts []models.Shift) (start time.Time, end time.Time) {
+	for i, shift := range shifts {
+		if i == 0 || shift.StartTime.Before(start) {
+			start = shift.StartTime
+		}
+		if i == 0 || shift.EndTime.After(end) {
+			end = shift.EndTime
+		}
+	}
+	return start, end
+}
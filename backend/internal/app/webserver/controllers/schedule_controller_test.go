@@ -0,0 +1,289 @@
+package controllers_test
+
+import (
+	"brothers_in_batash/internal/app/webserver/api"
+	"brothers_in_batash/internal/app/webserver/controllers"
+	"brothers_in_batash/internal/pkg/mocks"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"brothers_in_batash/internal/pkg/store"
+	"brothers_in_batash/internal/pkg/test_utils"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testUnstaffedShift = models.Shift{
+	ID:        "unstaffed",
+	Name:      testShiftName,
+	Type:      models.StaticPostShiftType,
+	StartTime: time.Date(2025, time.April, 10, 6, 0, 0, 0, time.UTC),
+	EndTime:   time.Date(2025, time.April, 10, 14, 0, 0, 0, time.UTC),
+}
+
+func newTestSolver(t *testing.T) *scheduling.Solver {
+	scorer, err := scheduling.NewScorer(scheduling.LoadWeights{Hour: 1})
+	require.NoError(t, err)
+	solver, err := scheduling.NewSolver(scorer, scheduling.NoOverlapConstraint)
+	require.NoError(t, err)
+	return solver
+}
+
+func setupScheduleController(t *testing.T, shiftStore *mocks.MockIShiftStore, dayStore *mocks.MockIDayStore,
+	soldierStore *mocks.MockISoldierStore, shiftTemplateStore *mocks.MockIShiftTemplateStore, leaveStore *mocks.MockILeaveStore,
+	rotationStore *mocks.MockIRotationStore) *fiber.App {
+	app := fiber.New()
+	transactor := &mocks.MockITransactor{Stores: store.TxStores{Shifts: shiftStore}}
+	controller, err := controllers.NewScheduleController(shiftStore, dayStore, soldierStore, shiftTemplateStore, leaveStore, rotationStore,
+		transactor, newTestSolver(t), test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	return app
+}
+
+func TestScheduleController_NewScheduleController__sad_flows(t *testing.T) {
+	testCases := []struct {
+		name               string
+		shiftStore         store.IShiftStore
+		dayStore           store.IDayStore
+		soldierStore       store.ISoldierStore
+		shiftTemplateStore store.IShiftTemplateStore
+		leaveStore         store.ILeaveStore
+		rotationStore      store.IRotationStore
+		transactor         store.ITransactor
+		solver             *scheduling.Solver
+		authMiddleware     fiber.Handler
+	}{
+		{name: "nil shift store", dayStore: &mocks.MockIDayStore{}, soldierStore: &mocks.MockISoldierStore{}, shiftTemplateStore: &mocks.MockIShiftTemplateStore{},
+			leaveStore: &mocks.MockILeaveStore{}, rotationStore: &mocks.MockIRotationStore{},
+			solver: newTestSolver(t), authMiddleware: test_utils.AlwaysAllowedJWTMiddleware,
+			transactor: &mocks.MockITransactor{}},
+		{name: "nil day store", shiftStore: &mocks.MockIShiftStore{}, soldierStore: &mocks.MockISoldierStore{},
+			shiftTemplateStore: &mocks.MockIShiftTemplateStore{}, leaveStore: &mocks.MockILeaveStore{},
+			rotationStore: &mocks.MockIRotationStore{}, solver: newTestSolver(t),
+			authMiddleware: test_utils.AlwaysAllowedJWTMiddleware, transactor: &mocks.MockITransactor{}},
+		{name: "nil soldier store", shiftStore: &mocks.MockIShiftStore{}, dayStore: &mocks.MockIDayStore{}, shiftTemplateStore: &mocks.MockIShiftTemplateStore{},
+			leaveStore: &mocks.MockILeaveStore{}, rotationStore: &mocks.MockIRotationStore{},
+			solver: newTestSolver(t), authMiddleware: test_utils.AlwaysAllowedJWTMiddleware,
+			transactor: &mocks.MockITransactor{}},
+		{name: "nil shift template store", shiftStore: &mocks.MockIShiftStore{}, dayStore: &mocks.MockIDayStore{}, soldierStore: &mocks.MockISoldierStore{},
+			leaveStore: &mocks.MockILeaveStore{}, rotationStore: &mocks.MockIRotationStore{},
+			solver: newTestSolver(t), authMiddleware: test_utils.AlwaysAllowedJWTMiddleware,
+			transactor: &mocks.MockITransactor{}},
+		{name: "nil leave store", shiftStore: &mocks.MockIShiftStore{}, dayStore: &mocks.MockIDayStore{}, soldierStore: &mocks.MockISoldierStore{},
+			shiftTemplateStore: &mocks.MockIShiftTemplateStore{}, rotationStore: &mocks.MockIRotationStore{},
+			solver: newTestSolver(t), authMiddleware: test_utils.AlwaysAllowedJWTMiddleware,
+			transactor: &mocks.MockITransactor{}},
+		{name: "nil rotation store", shiftStore: &mocks.MockIShiftStore{}, dayStore: &mocks.MockIDayStore{}, soldierStore: &mocks.MockISoldierStore{},
+			shiftTemplateStore: &mocks.MockIShiftTemplateStore{}, leaveStore: &mocks.MockILeaveStore{},
+			solver: newTestSolver(t), authMiddleware: test_utils.AlwaysAllowedJWTMiddleware,
+			transactor: &mocks.MockITransactor{}},
+		{name: "nil transactor", shiftStore: &mocks.MockIShiftStore{}, dayStore: &mocks.MockIDayStore{}, soldierStore: &mocks.MockISoldierStore{},
+			shiftTemplateStore: &mocks.MockIShiftTemplateStore{}, leaveStore: &mocks.MockILeaveStore{},
+			rotationStore: &mocks.MockIRotationStore{}, solver: newTestSolver(t), authMiddleware: test_utils.AlwaysAllowedJWTMiddleware},
+		{name: "nil solver", shiftStore: &mocks.MockIShiftStore{}, dayStore: &mocks.MockIDayStore{}, soldierStore: &mocks.MockISoldierStore{},
+			shiftTemplateStore: &mocks.MockIShiftTemplateStore{}, leaveStore: &mocks.MockILeaveStore{},
+			rotationStore: &mocks.MockIRotationStore{}, authMiddleware: test_utils.AlwaysAllowedJWTMiddleware,
+			transactor: &mocks.MockITransactor{}},
+		{name: "nil auth middleware", shiftStore: &mocks.MockIShiftStore{}, dayStore: &mocks.MockIDayStore{}, soldierStore: &mocks.MockISoldierStore{},
+			shiftTemplateStore: &mocks.MockIShiftTemplateStore{}, leaveStore: &mocks.MockILeaveStore{},
+			rotationStore: &mocks.MockIRotationStore{}, solver: newTestSolver(t),
+			transactor: &mocks.MockITransactor{}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Act
+			controller, err := controllers.NewScheduleController(testCase.shiftStore, testCase.dayStore, testCase.soldierStore,
+				testCase.shiftTemplateStore, testCase.leaveStore, testCase.rotationStore, testCase.transactor, testCase.solver,
+				testCase.authMiddleware)
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, controller)
+		})
+	}
+}
+
+func TestScheduleController_AutoAssign__invalid_request_body(t *testing.T) {
+	testCases := []struct {
+		name    string
+		reqBody interface{}
+	}{
+		{name: "not an object", reqBody: "invalid"},
+		{name: "missing dates", reqBody: api.AutoAssignReqBody{}},
+		{name: "invalid date format", reqBody: api.AutoAssignReqBody{From: "10/04/2025", To: "2025-04-11"}},
+		{name: "end before start", reqBody: api.AutoAssignReqBody{From: "2025-04-11", To: "2025-04-10"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			app := setupScheduleController(t, &mocks.MockIShiftStore{}, &mocks.MockIDayStore{}, &mocks.MockISoldierStore{}, &mocks.MockIShiftTemplateStore{},
+				&mocks.MockILeaveStore{}, &mocks.MockIRotationStore{})
+			req := httptest.NewRequest(fiber.MethodPost, controllers.AutoAssignRoute, test_utils.WrapStructWithReader(t, testCase.reqBody))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			// Act
+			resp, err := app.Test(req, test_utils.TestTimeout)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+func TestScheduleController_AutoAssign__dry_run(t *testing.T) {
+	// Arrange
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{testUnstaffedShift}, nil)
+	soldierStore := &mocks.MockISoldierStore{}
+	soldierStore.On("FindAllSoldiers", mock.Anything).Return([]models.Soldier{testCommander}, nil)
+	shiftTemplateStore := &mocks.MockIShiftTemplateStore{}
+	shiftTemplateStore.On("FindAllShiftsTemplate", mock.Anything).Return([]models.ShiftTemplate{}, nil)
+	leaveStore := &mocks.MockILeaveStore{}
+	leaveStore.On("FindAllLeaves", mock.Anything).Return([]models.Leave{}, nil)
+	rotationStore := &mocks.MockIRotationStore{}
+	rotationStore.On("FindAllRotations", mock.Anything).Return([]models.Rotation{}, nil)
+	dayStore := &mocks.MockIDayStore{}
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{}, nil)
+	app := setupScheduleController(t, shiftStore, dayStore, soldierStore, shiftTemplateStore, leaveStore, rotationStore)
+	reqBody := api.AutoAssignReqBody{From: "2025-04-10", To: "2025-04-10", DryRun: true}
+	req := httptest.NewRequest(fiber.MethodPost, controllers.AutoAssignRoute, test_utils.WrapStructWithReader(t, reqBody))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var respBody api.AutoAssignRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	assert.NoError(t, err)
+	assert.True(t, respBody.DryRun)
+	require.Len(t, respBody.Assignments, 1)
+	assert.Equal(t, testUnstaffedShift.ID, respBody.Assignments[0].ShiftID)
+	assert.Equal(t, testCommander, respBody.Assignments[0].Commander)
+	shiftStore.AssertNotCalled(t, "UpdateShift", mock.Anything, mock.Anything)
+}
+
+func TestScheduleController_AutoAssign__saves_assignments(t *testing.T) {
+	// Arrange
+	outOfRangeShift := testUnstaffedShift
+	outOfRangeShift.ID = "out-of-range"
+	outOfRangeShift.StartTime = outOfRangeShift.StartTime.AddDate(0, 0, 2)
+	outOfRangeShift.EndTime = outOfRangeShift.EndTime.AddDate(0, 0, 2)
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{testUnstaffedShift, outOfRangeShift}, nil)
+	shiftStore.On("UpdateShift", mock.Anything, mock.MatchedBy(func(arg models.Shift) bool {
+		return arg.ID == testUnstaffedShift.ID && arg.Commander.ID == testCommander.ID
+	})).Return(nil)
+	soldierStore := &mocks.MockISoldierStore{}
+	soldierStore.On("FindAllSoldiers", mock.Anything).Return([]models.Soldier{testCommander}, nil)
+	shiftTemplateStore := &mocks.MockIShiftTemplateStore{}
+	shiftTemplateStore.On("FindAllShiftsTemplate", mock.Anything).Return([]models.ShiftTemplate{}, nil)
+	leaveStore := &mocks.MockILeaveStore{}
+	leaveStore.On("FindAllLeaves", mock.Anything).Return([]models.Leave{}, nil)
+	rotationStore := &mocks.MockIRotationStore{}
+	rotationStore.On("FindAllRotations", mock.Anything).Return([]models.Rotation{}, nil)
+	dayStore := &mocks.MockIDayStore{}
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{}, nil)
+	app := setupScheduleController(t, shiftStore, dayStore, soldierStore, shiftTemplateStore, leaveStore, rotationStore)
+	reqBody := api.AutoAssignReqBody{From: "2025-04-10", To: "2025-04-11"}
+	req := httptest.NewRequest(fiber.MethodPost, controllers.AutoAssignRoute, test_utils.WrapStructWithReader(t, reqBody))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	shiftStore.AssertExpectations(t)
+	shiftStore.AssertNumberOfCalls(t, "UpdateShift", 1)
+}
+
+func TestScheduleController_AutoAssign__skips_soldiers_on_leave(t *testing.T) {
+	// Arrange
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{testUnstaffedShift}, nil)
+	soldierStore := &mocks.MockISoldierStore{}
+	soldierStore.On("FindAllSoldiers", mock.Anything).Return([]models.Soldier{testCommander}, nil)
+	shiftTemplateStore := &mocks.MockIShiftTemplateStore{}
+	shiftTemplateStore.On("FindAllShiftsTemplate", mock.Anything).Return([]models.ShiftTemplate{}, nil)
+	leaveStore := &mocks.MockILeaveStore{}
+	leaveStore.On("FindAllLeaves", mock.Anything).Return([]models.Leave{{
+		ID:        "leave",
+		SoldierID: testCommander.ID,
+		Type:      models.HomeLeaveType,
+		StartTime: testUnstaffedShift.StartTime.AddDate(0, 0, -1),
+		EndTime:   testUnstaffedShift.EndTime.AddDate(0, 0, 1),
+	}}, nil)
+	rotationStore := &mocks.MockIRotationStore{}
+	rotationStore.On("FindAllRotations", mock.Anything).Return([]models.Rotation{}, nil)
+	dayStore := &mocks.MockIDayStore{}
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{}, nil)
+	app := setupScheduleController(t, shiftStore, dayStore, soldierStore, shiftTemplateStore, leaveStore, rotationStore)
+	reqBody := api.AutoAssignReqBody{From: "2025-04-10", To: "2025-04-10", DryRun: true}
+	req := httptest.NewRequest(fiber.MethodPost, controllers.AutoAssignRoute, test_utils.WrapStructWithReader(t, reqBody))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var respBody api.AutoAssignRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	assert.NoError(t, err)
+	assert.Empty(t, respBody.Assignments)
+	require.Len(t, respBody.Unassigned, 1)
+	assert.Equal(t, scheduling.NoAvailableCommanderReason, respBody.Unassigned[0].Reason)
+}
+
+func TestScheduleController_AutoAssign__skips_soldiers_booked_in_day_schedules(t *testing.T) {
+	// Arrange
+	bookedShift := testUnstaffedShift
+	bookedShift.ID = "booked"
+	bookedShift.Commander = testCommander
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{testUnstaffedShift}, nil)
+	dayStore := &mocks.MockIDayStore{}
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{{
+		Date:   time.Date(2025, time.April, 10, 0, 0, 0, 0, time.UTC),
+		Shifts: []models.Shift{bookedShift},
+	}}, nil)
+	soldierStore := &mocks.MockISoldierStore{}
+	soldierStore.On("FindAllSoldiers", mock.Anything).Return([]models.Soldier{testCommander}, nil)
+	shiftTemplateStore := &mocks.MockIShiftTemplateStore{}
+	shiftTemplateStore.On("FindAllShiftsTemplate", mock.Anything).Return([]models.ShiftTemplate{}, nil)
+	leaveStore := &mocks.MockILeaveStore{}
+	leaveStore.On("FindAllLeaves", mock.Anything).Return([]models.Leave{}, nil)
+	rotationStore := &mocks.MockIRotationStore{}
+	rotationStore.On("FindAllRotations", mock.Anything).Return([]models.Rotation{}, nil)
+	app := setupScheduleController(t, shiftStore, dayStore, soldierStore, shiftTemplateStore, leaveStore, rotationStore)
+	reqBody := api.AutoAssignReqBody{From: "2025-04-10", To: "2025-04-10", DryRun: true}
+	req := httptest.NewRequest(fiber.MethodPost, controllers.AutoAssignRoute, test_utils.WrapStructWithReader(t, reqBody))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var respBody api.AutoAssignRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	assert.NoError(t, err)
+	assert.Empty(t, respBody.Assignments)
+	require.Len(t, respBody.Unassigned, 1)
+	assert.Equal(t, scheduling.NoAvailableCommanderReason, respBody.Unassigned[0].Reason)
+}
@@ -1,9 +1,15 @@
 package controllers
 
 import (
+	"brothers_in_batash/internal/app/webserver/api"
 	"brothers_in_batash/internal/pkg/logging"
 	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
 	"brothers_in_batash/internal/pkg/store"
+	"brothers_in_batash/internal/pkg/utils"
+	"context"
+	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/pkg/errors"
@@ -11,31 +17,70 @@ import (
 
 // TODO - Implement a concrete type for API requests and responses bodies
 
+var errUnknownReference = errors.New("shift references an unknown entity")
+
 type ShiftController struct {
-	shiftStore     store.IShiftStore
-	soldierStore   store.ISoldierStore
-	authMiddleware fiber.Handler
+	shiftStore         store.IShiftStore
+	dayStore           store.IDayStore
+	soldierStore       store.ISoldierStore
+	shiftTemplateStore store.IShiftTemplateStore
+	leaveStore         store.ILeaveStore
+	rotationStore      store.IRotationStore
+	restPolicy         *scheduling.RestPolicy
+	staffingMode       scheduling.StaffingMode
+	authMiddleware     fiber.Handler
 }
 
-func NewShiftController(shiftStore store.IShiftStore, soldierStore store.ISoldierStore, authMiddleware fiber.Handler) (*ShiftController, error) {
+func NewShiftController(shiftStore store.IShiftStore, dayStore store.IDayStore, soldierStore store.ISoldierStore,
+	shiftTemplateStore store.IShiftTemplateStore, leaveStore store.ILeaveStore, rotationStore store.IRotationStore,
+	restPolicy *scheduling.RestPolicy, staffingMode scheduling.StaffingMode, authMiddleware fiber.Handler) (*ShiftController, error) {
 	if shiftStore == nil {
 		return nil, errors.New("shiftStore is nil")
 	}
+	if dayStore == nil {
+		return nil, errors.New("dayStore is nil")
+	}
 	if soldierStore == nil {
 		return nil, errors.New("soldierStore is nil")
 	}
+	if shiftTemplateStore == nil {
+		return nil, errors.New("shiftTemplateStore is nil")
+	}
+	if leaveStore == nil {
+		return nil, errors.New("leaveStore is nil")
+	}
+	if rotationStore == nil {
+		return nil, errors.New("rotationStore is nil")
+	}
+	if restPolicy == nil {
+		return nil, errors.New("restPolicy is nil")
+	}
+	if !staffingMode.IsValid() {
+		return nil, errors.Errorf("invalid staffing mode %q", staffingMode)
+	}
 	if authMiddleware == nil {
 		return nil, errors.New("authMiddleware is nil")
 	}
-	return &ShiftController{shiftStore: shiftStore, soldierStore: soldierStore, authMiddleware: authMiddleware}, nil
+	return &ShiftController{
+		shiftStore:         shiftStore,
+		dayStore:           dayStore,
+		soldierStore:       soldierStore,
+		shiftTemplateStore: shiftTemplateStore,
+		leaveStore:         leaveStore,
+		rotationStore:      rotationStore,
+		restPolicy:         restPolicy,
+		staffingMode:       staffingMode,
+		authMiddleware:     authMiddleware,
+	}, nil
 }
 
 func (c *ShiftController) RegisterRoutes(router fiber.Router) error {
-	router.Post(CreateShiftRoute, c.authMiddleware, c.createShift)
-	router.Get(GetShiftRoute, c.authMiddleware, c.getShift)
-	router.Get(GetAllShiftsRoute, c.authMiddleware, c.getAllShifts)
-	router.Put(UpdateShiftRoute, c.authMiddleware, c.updateShift)
-	router.Delete(DeleteShiftRoute, c.authMiddleware, c.deleteShift)
+	router.Post(CreateShiftRoute, c.authMiddleware, requireSquadCommander, c.createShift)
+	router.Get(GetShiftRoute, c.authMiddleware, requireViewer, c.getShift)
+	router.Get(GetAllShiftsRoute, c.authMiddleware, requireViewer, c.getAllShifts)
+	router.Put(UpdateShiftRoute, c.authMiddleware, requireSquadCommander, c.updateShift)
+	router.Delete(DeleteShiftRoute, c.authMiddleware, requireSquadCommander, c.deleteShift)
+	router.Post(RestoreShiftRoute, c.authMiddleware, requireSquadCommander, c.restoreShift)
 	return nil
 }
 
@@ -47,17 +92,56 @@ func (c *ShiftController) createShift(ctx *fiber.Ctx) error {
 		logging.Debug("Could not parse shift creation request body", []logging.LogProp{{"error", errStr}, {"body", bodyStr}})
 		return ctx.SendStatus(fiber.StatusBadRequest)
 	}
+	// The shift is identified by the server once it is stored. Until then, it shows with no ID in conflict reports.
+	shiftModel.ID = ""
 
-	if err := c.shiftStore.CreateNewShift(shiftModel); err != nil {
+	if conflict, err := c.findConflicts(ctx.UserContext(), shiftModel); err != nil {
+		logging.Warning(err, "error on checking new shift for conflicts", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if conflict != nil {
+		logging.Debug("new shift conflicts with existing shifts", []logging.LogProp{{"shiftID", shiftModel.ID}})
+		return ctx.Status(fiber.StatusConflict).JSON(conflict)
+	}
+
+	staffingIssues, err := c.checkStaffing(ctx.UserContext(), shiftModel)
+	if errors.Is(err, errUnknownReference) {
+		logging.Debug("new shift references an unknown entity", []logging.LogProp{{"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	} else if err != nil {
+		logging.Warning(err, "error on checking new shift staffing", nil)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	hasStaffingIssues := len(staffingIssues.Shortfalls) > 0 || len(staffingIssues.OnLeave) > 0
+	if hasStaffingIssues && c.staffingMode == scheduling.StrictStaffingMode {
+		logging.Debug("new shift is understaffed", []logging.LogProp{{"shiftID", shiftModel.ID}})
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(staffingIssues)
+	}
+	shiftModel.Understaffed = hasStaffingIssues
+	shiftModel.ID = utils.NewEntityID()
+
+	if err := c.shiftStore.CreateNewShift(ctx.UserContext(), shiftModel); err != nil {
 		logging.Warning(err, "error on creating new shift", nil)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
-	return ctx.SendStatus(fiber.StatusCreated)
+	shifts, err := c.shiftStore.FindShiftByID(ctx.UserContext(), shiftModel.ID)
+	if err != nil {
+		logging.Warning(err, "could not query for created shift", []logging.LogProp{{"shiftID", shiftModel.ID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if len(shifts) == 0 {
+		logging.Trace("created shift was deleted already", []logging.LogProp{{"shiftID", shiftModel.ID}})
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	respBody := api.CreatedShiftRespBody{Shift: shifts[0]}
+	if shiftModel.Understaffed {
+		respBody.StaffingIssues = &staffingIssues
+	}
+	setETag(ctx, shifts[0].Version)
+	return sendCreated(ctx, shiftModel.ID, respBody)
 }
 
 func (c *ShiftController) getShift(ctx *fiber.Ctx) error {
 	shiftID := ctx.Params("id")
-	shifts, err := c.shiftStore.FindShiftByID(shiftID)
+	shifts, err := c.shiftStore.FindShiftByID(ctx.UserContext(), shiftID)
 	if err != nil {
 		logging.Warning(err, "Could not query for shift", []logging.LogProp{{"shiftID", shiftID}})
 		return ctx.SendStatus(fiber.StatusInternalServerError)
@@ -65,11 +149,17 @@ func (c *ShiftController) getShift(ctx *fiber.Ctx) error {
 		logging.Trace("shift not found", []logging.LogProp{{"shiftID", shiftID}})
 		return ctx.SendStatus(fiber.StatusNotFound)
 	}
+	setETag(ctx, shifts[0].Version)
 	return ctx.JSON(shifts[0])
 }
 
+// getAllShifts returns the shifts matching the optional filter query params - see parseShiftFilterQuery
 func (c *ShiftController) getAllShifts(ctx *fiber.Ctx) error {
-	dbShifts, err := c.shiftStore.FindAllShifts()
+	filter, ok := parseShiftFilterQuery(ctx)
+	if !ok {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	dbShifts, err := c.shiftStore.FindShifts(ctx.UserContext(), filter)
 	if err != nil {
 		logging.Warning(err, "error on fetching all shifts", nil)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
@@ -83,6 +173,10 @@ func (c *ShiftController) getAllShifts(ctx *fiber.Ctx) error {
 
 func (c *ShiftController) updateShift(ctx *fiber.Ctx) error {
 	shiftID := ctx.Params("id")
+	version, status, ok := ifMatchVersion(ctx)
+	if !ok {
+		return ctx.SendStatus(status)
+	}
 	updatedShift := models.Shift{}
 	if err := ctx.BodyParser(&updatedShift); err != nil {
 		logging.Info("Could not parse shift update request body", []logging.LogProp{{"error", err.Error()}})
@@ -93,25 +187,180 @@ func (c *ShiftController) updateShift(ctx *fiber.Ctx) error {
 			[]logging.LogProp{{"body_shift_id", updatedShift.ID}, {"uri_shift_id", shiftID}})
 		return ctx.SendStatus(fiber.StatusBadRequest)
 	}
-	if shifts, err := c.shiftStore.FindShiftByID(shiftID); err != nil {
+	if shifts, err := c.shiftStore.FindShiftByID(ctx.UserContext(), shiftID); err != nil {
 		logging.Info("Could not query existing shift on update", []logging.LogProp{{"error", err.Error()}})
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	} else if len(shifts) == 0 {
 		return ctx.SendStatus(fiber.StatusNotFound)
+	} else if shifts[0].Version != version {
+		// Checked ahead of the store, so a stale update is not reported as conflicting with the shift's own new version
+		logging.Debug("shift was modified since it was read", []logging.LogProp{{"shiftID", shiftID}})
+		return ctx.SendStatus(fiber.StatusPreconditionFailed)
+	}
+	updatedShift.Version = version
+
+	if conflict, err := c.findConflicts(ctx.UserContext(), updatedShift); err != nil {
+		logging.Warning(err, "error on checking updated shift for conflicts", []logging.LogProp{{"shiftID", shiftID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if conflict != nil {
+		logging.Debug("updated shift conflicts with existing shifts", []logging.LogProp{{"shiftID", shiftID}})
+		return ctx.Status(fiber.StatusConflict).JSON(conflict)
+	}
+
+	staffingIssues, err := c.checkStaffing(ctx.UserContext(), updatedShift)
+	if errors.Is(err, errUnknownReference) {
+		logging.Debug("updated shift references an unknown entity", []logging.LogProp{{"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	} else if err != nil {
+		logging.Warning(err, "error on checking updated shift staffing", []logging.LogProp{{"shiftID", shiftID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
+	hasStaffingIssues := len(staffingIssues.Shortfalls) > 0 || len(staffingIssues.OnLeave) > 0
+	if hasStaffingIssues && c.staffingMode == scheduling.StrictStaffingMode {
+		logging.Debug("updated shift is understaffed", []logging.LogProp{{"shiftID", shiftID}})
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(staffingIssues)
+	}
+	updatedShift.Understaffed = hasStaffingIssues
 
-	if err := c.shiftStore.UpdateShift(updatedShift); err != nil {
+	if err := c.shiftStore.UpdateShift(ctx.UserContext(), updatedShift); errors.Is(err, store.ErrVersionConflict) {
+		logging.Debug("shift was modified since it was read", []logging.LogProp{{"shiftID", shiftID}, {"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusPreconditionFailed)
+	} else if err != nil {
 		logging.Warning(err, "error on updating shift", []logging.LogProp{{"shiftID", shiftID}})
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
+	setETag(ctx, version+1)
+	if updatedShift.Understaffed {
+		return ctx.Status(fiber.StatusOK).JSON(staffingIssues)
+	}
 	return ctx.SendStatus(fiber.StatusOK)
 }
 
 func (c *ShiftController) deleteShift(ctx *fiber.Ctx) error {
 	shiftID := ctx.Params("id")
-	if err := c.shiftStore.DeleteShift(shiftID); err != nil {
+	version, status, ok := ifMatchVersion(ctx)
+	if !ok {
+		return ctx.SendStatus(status)
+	}
+	if err := c.shiftStore.DeleteShift(ctx.UserContext(), shiftID, version); errors.Is(err, store.ErrVersionConflict) {
+		logging.Debug("shift was modified since it was read", []logging.LogProp{{"shiftID", shiftID}, {"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusPreconditionFailed)
+	} else if err != nil {
 		logging.Warning(err, "error on deleting shift", []logging.LogProp{{"shiftID", shiftID}})
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
 	return ctx.SendStatus(fiber.StatusOK)
 }
+
+// restoreShift undoes the deletion of a shift that was not purged yet, and returns the restored shift
+func (c *ShiftController) restoreShift(ctx *fiber.Ctx) error {
+	shiftID := ctx.Params("id")
+	if err := c.shiftStore.RestoreShift(ctx.UserContext(), shiftID); errors.Is(err, store.ErrNotDeleted) {
+		logging.Trace("no deleted shift to restore", []logging.LogProp{{"shiftID", shiftID}})
+		return ctx.SendStatus(fiber.StatusNotFound)
+	} else if err != nil {
+		logging.Warning(err, "error on restoring shift", []logging.LogProp{{"shiftID", shiftID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	shifts, err := c.shiftStore.FindShiftByID(ctx.UserContext(), shiftID)
+	if err != nil {
+		logging.Warning(err, "could not query for restored shift", []logging.LogProp{{"shiftID", shiftID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if len(shifts) == 0 {
+		logging.Trace("restored shift was deleted again", []logging.LogProp{{"shiftID", shiftID}})
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	setETag(ctx, shifts[0].Version)
+	return ctx.JSON(shifts[0])
+}
+
+// parseShiftFilterQuery parses the optional "from", "to", "soldierId", "type" and "shiftTemplateId" query params.
+// Same as with parseDateRangeQuery, the dates range is inclusive.
+func parseShiftFilterQuery(ctx *fiber.Ctx) (store.ShiftFilter, bool) {
+	filter := store.ShiftFilter{SoldierID: ctx.Query("soldierId"), ShiftTemplateID: ctx.Query("shiftTemplateId")}
+	if fromStr := ctx.Query("from"); fromStr != "" {
+		from, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			logging.Debug("Invalid from date format", []logging.LogProp{{"from", fromStr}})
+			return store.ShiftFilter{}, false
+		}
+		filter.From = from
+	}
+	if toStr := ctx.Query("to"); toStr != "" {
+		to, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			logging.Debug("Invalid to date format", []logging.LogProp{{"to", toStr}})
+			return store.ShiftFilter{}, false
+		}
+		filter.To = to.AddDate(0, 0, 1)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		logging.Debug("Range end is before range start", []logging.LogProp{{"from", ctx.Query("from")}, {"to", ctx.Query("to")}})
+		return store.ShiftFilter{}, false
+	}
+	if typeStr := ctx.Query("type"); typeStr != "" {
+		typeNum, err := strconv.Atoi(typeStr)
+		shiftType := models.ShiftType(typeNum)
+		if err != nil || !shiftType.IsValid() {
+			logging.Debug("Invalid shift type", []logging.LogProp{{"type", typeStr}})
+			return store.ShiftFilter{}, false
+		}
+		filter.Type = &shiftType
+	}
+	return filter, true
+}
+
+// findConflicts checks the way shift is staffed against the existing shifts, including the shifts of day schedules.
+// A nil response means no conflicts were found.
+func (c *ShiftController) findConflicts(ctx context.Context, shift models.Shift) (*api.ShiftConflictRespBody, error) {
+	existingShifts, err := findAllShifts(ctx, c.shiftStore, c.dayStore)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch existing shifts")
+	}
+	overlaps := scheduling.FindOverlaps(shift, existingShifts)
+	restViolations := c.restPolicy.Violations(shift, existingShifts)
+	if len(overlaps) == 0 && len(restViolations) == 0 {
+		return nil, nil
+	}
+	return &api.ShiftConflictRespBody{Overlaps: overlaps, RestViolations: restViolations}, nil
+}
+
+// checkStaffing checks the soldiers staffed in shift, as they are currently stored, against the PersonnelRequirement
+// of the template shift was created from and against their leaves and rotations. Shifts that were not created from a template
+// have no role requirements.
+func (c *ShiftController) checkStaffing(ctx context.Context, shift models.Shift) (api.StaffingIssuesRespBody, error) {
+	issues := api.StaffingIssuesRespBody{Shortfalls: make([]scheduling.RoleShortfall, 0), OnLeave: make([]scheduling.LeaveConflict, 0)}
+	soldiers := make([]models.Soldier, 0)
+	for _, shiftSoldier := range shift.Soldiers() {
+		storedSoldiers, err := c.soldierStore.FindSoldierByID(ctx, shiftSoldier.ID)
+		if err != nil {
+			return issues, errors.Wrap(err, "could not fetch soldier")
+		} else if len(storedSoldiers) == 0 {
+			return issues, errors.Wrapf(errUnknownReference, "soldier %s not found", shiftSoldier.ID)
+		}
+		soldiers = append(soldiers, storedSoldiers[0])
+
+		leaves, err := c.leaveStore.FindLeavesBySoldierID(ctx, shiftSoldier.ID)
+		if err != nil {
+			return issues, errors.Wrap(err, "could not fetch soldier leaves")
+		}
+		rotations, err := c.rotationStore.FindRotationsBySoldierID(ctx, shiftSoldier.ID)
+		if err != nil {
+			return issues, errors.Wrap(err, "could not fetch soldier rotations")
+		}
+		leaves = append(leaves, scheduling.RotationLeaves(rotations, shift.StartTime, shift.EndTime)...)
+		issues.OnLeave = append(issues.OnLeave, scheduling.FindLeaveConflicts(shift, leaves)...)
+	}
+
+	if shift.ShiftTemplateID == "" {
+		return issues, nil
+	}
+	templates, err := c.shiftTemplateStore.FindShiftTemplateByID(ctx, shift.ShiftTemplateID)
+	if err != nil {
+		return issues, errors.Wrap(err, "could not fetch shift template")
+	} else if len(templates) == 0 {
+		return issues, errors.Wrapf(errUnknownReference, "shift template %s not found", shift.ShiftTemplateID)
+	}
+	issues.Shortfalls = scheduling.RoleShortfalls(templates[0].PersonnelRequirement, soldiers)
+	return issues, nil
+}
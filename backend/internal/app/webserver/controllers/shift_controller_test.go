@@ -1,9 +1,11 @@
 package controllers_test
 
 import (
+	"brothers_in_batash/internal/app/webserver/api"
 	"brothers_in_batash/internal/app/webserver/controllers"
 	"brothers_in_batash/internal/pkg/mocks"
 	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
 	"brothers_in_batash/internal/pkg/store"
 	"brothers_in_batash/internal/pkg/test_utils"
 	"encoding/json"
@@ -44,37 +46,159 @@ var testShiftModel = models.Shift{
 	EndTime:            time.Date(2025, time.April, 9, 1, 0, 0, 0, time.UTC),
 	Commander:          testCommander,
 	AdditionalSoldiers: nil,
+	Version:            1,
+}
+
+func newTestRestPolicy(t *testing.T) *scheduling.RestPolicy {
+	restPolicy, err := scheduling.NewRestPolicy(scheduling.RestRule{MinRest: 8 * time.Hour})
+	require.NoError(t, err)
+	return restPolicy
+}
+
+func newEmptyDayStore() *mocks.MockIDayStore {
+	dayStore := &mocks.MockIDayStore{}
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{}, nil)
+	return dayStore
+}
+
+func newEmptyLeaveStore() *mocks.MockILeaveStore {
+	leaveStore := &mocks.MockILeaveStore{}
+	leaveStore.On("FindLeavesBySoldierID", mock.Anything, mock.Anything).Return([]models.Leave{}, nil)
+	return leaveStore
+}
+
+func newEmptyRotationStore() *mocks.MockIRotationStore {
+	rotationStore := &mocks.MockIRotationStore{}
+	rotationStore.On("FindRotationsBySoldierID", mock.Anything, mock.Anything).Return([]models.Rotation{}, nil)
+	return rotationStore
 }
 
 func TestShiftController_NewShiftController__sad_flows(t *testing.T) {
 	testCases := []struct {
-		shiftStore     store.IShiftStore
-		soldierStore   store.ISoldierStore
-		authMiddleware fiber.Handler
-		name           string
+		shiftStore         store.IShiftStore
+		dayStore           store.IDayStore
+		soldierStore       store.ISoldierStore
+		shiftTemplateStore store.IShiftTemplateStore
+		leaveStore         store.ILeaveStore
+		rotationStore      store.IRotationStore
+		restPolicy         *scheduling.RestPolicy
+		staffingMode       scheduling.StaffingMode
+		authMiddleware     fiber.Handler
+		name               string
 	}{
 		{
-			shiftStore:     nil,
-			soldierStore:   &mocks.MockISoldierStore{},
-			authMiddleware: test_utils.AlwaysAllowedJWTMiddleware,
-			name:           "nil shift store",
+			shiftStore:         nil,
+			dayStore:           &mocks.MockIDayStore{},
+			soldierStore:       &mocks.MockISoldierStore{},
+			shiftTemplateStore: &mocks.MockIShiftTemplateStore{},
+			leaveStore:         &mocks.MockILeaveStore{},
+			rotationStore:      &mocks.MockIRotationStore{},
+			restPolicy:         newTestRestPolicy(t),
+			staffingMode:       scheduling.WarnStaffingMode,
+			authMiddleware:     test_utils.AlwaysAllowedJWTMiddleware,
+			name:               "nil shift store",
+		},
+		{
+			shiftStore:         &mocks.MockIShiftStore{},
+			dayStore:           nil,
+			soldierStore:       &mocks.MockISoldierStore{},
+			shiftTemplateStore: &mocks.MockIShiftTemplateStore{},
+			leaveStore:         &mocks.MockILeaveStore{},
+			rotationStore:      &mocks.MockIRotationStore{},
+			restPolicy:         newTestRestPolicy(t),
+			staffingMode:       scheduling.WarnStaffingMode,
+			authMiddleware:     test_utils.AlwaysAllowedJWTMiddleware,
+			name:               "nil day store",
+		},
+		{
+			shiftStore:         &mocks.MockIShiftStore{},
+			dayStore:           &mocks.MockIDayStore{},
+			soldierStore:       nil,
+			shiftTemplateStore: &mocks.MockIShiftTemplateStore{},
+			leaveStore:         &mocks.MockILeaveStore{},
+			rotationStore:      &mocks.MockIRotationStore{},
+			restPolicy:         newTestRestPolicy(t),
+			staffingMode:       scheduling.WarnStaffingMode,
+			authMiddleware:     test_utils.AlwaysAllowedJWTMiddleware,
+			name:               "nil soldier store",
+		},
+		{
+			shiftStore:         &mocks.MockIShiftStore{},
+			dayStore:           &mocks.MockIDayStore{},
+			soldierStore:       &mocks.MockISoldierStore{},
+			shiftTemplateStore: nil,
+			leaveStore:         &mocks.MockILeaveStore{},
+			rotationStore:      &mocks.MockIRotationStore{},
+			restPolicy:         newTestRestPolicy(t),
+			staffingMode:       scheduling.WarnStaffingMode,
+			authMiddleware:     test_utils.AlwaysAllowedJWTMiddleware,
+			name:               "nil shift template store",
 		},
 		{
-			shiftStore:     &mocks.MockIShiftStore{},
-			soldierStore:   nil,
-			authMiddleware: test_utils.AlwaysAllowedJWTMiddleware,
-			name:           "nil soldier store",
+			shiftStore:         &mocks.MockIShiftStore{},
+			dayStore:           &mocks.MockIDayStore{},
+			soldierStore:       &mocks.MockISoldierStore{},
+			shiftTemplateStore: &mocks.MockIShiftTemplateStore{},
+			leaveStore:         nil,
+			rotationStore:      &mocks.MockIRotationStore{},
+			restPolicy:         newTestRestPolicy(t),
+			staffingMode:       scheduling.WarnStaffingMode,
+			authMiddleware:     test_utils.AlwaysAllowedJWTMiddleware,
+			name:               "nil leave store",
 		},
 		{
-			shiftStore:     &mocks.MockIShiftStore{},
-			soldierStore:   &mocks.MockISoldierStore{},
-			authMiddleware: nil,
-			name:           "nil auth middleware",
+			shiftStore:         &mocks.MockIShiftStore{},
+			dayStore:           &mocks.MockIDayStore{},
+			soldierStore:       &mocks.MockISoldierStore{},
+			shiftTemplateStore: &mocks.MockIShiftTemplateStore{},
+			leaveStore:         &mocks.MockILeaveStore{},
+			rotationStore:      nil,
+			restPolicy:         newTestRestPolicy(t),
+			staffingMode:       scheduling.WarnStaffingMode,
+			authMiddleware:     test_utils.AlwaysAllowedJWTMiddleware,
+			name:               "nil rotation store",
+		},
+		{
+			shiftStore:         &mocks.MockIShiftStore{},
+			dayStore:           &mocks.MockIDayStore{},
+			soldierStore:       &mocks.MockISoldierStore{},
+			shiftTemplateStore: &mocks.MockIShiftTemplateStore{},
+			leaveStore:         &mocks.MockILeaveStore{},
+			rotationStore:      &mocks.MockIRotationStore{},
+			restPolicy:         nil,
+			staffingMode:       scheduling.WarnStaffingMode,
+			authMiddleware:     test_utils.AlwaysAllowedJWTMiddleware,
+			name:               "nil rest policy",
+		},
+		{
+			shiftStore:         &mocks.MockIShiftStore{},
+			dayStore:           &mocks.MockIDayStore{},
+			soldierStore:       &mocks.MockISoldierStore{},
+			shiftTemplateStore: &mocks.MockIShiftTemplateStore{},
+			leaveStore:         &mocks.MockILeaveStore{},
+			rotationStore:      &mocks.MockIRotationStore{},
+			restPolicy:         newTestRestPolicy(t),
+			staffingMode:       "lenient",
+			authMiddleware:     test_utils.AlwaysAllowedJWTMiddleware,
+			name:               "invalid staffing mode",
+		},
+		{
+			shiftStore:         &mocks.MockIShiftStore{},
+			dayStore:           &mocks.MockIDayStore{},
+			soldierStore:       &mocks.MockISoldierStore{},
+			shiftTemplateStore: &mocks.MockIShiftTemplateStore{},
+			leaveStore:         &mocks.MockILeaveStore{},
+			rotationStore:      &mocks.MockIRotationStore{},
+			restPolicy:         newTestRestPolicy(t),
+			staffingMode:       scheduling.WarnStaffingMode,
+			authMiddleware:     nil,
+			name:               "nil auth middleware",
 		},
 	}
 	for _, testCase := range testCases {
 		// Act
-		controller, err := controllers.NewShiftController(testCase.shiftStore, testCase.soldierStore, testCase.authMiddleware)
+		controller, err := controllers.NewShiftController(testCase.shiftStore, testCase.dayStore, testCase.soldierStore, testCase.shiftTemplateStore,
+			testCase.leaveStore, testCase.rotationStore, testCase.restPolicy, testCase.staffingMode, testCase.authMiddleware)
 
 		// Assert
 		assert.Error(t, err)
@@ -84,8 +208,8 @@ func TestShiftController_NewShiftController__sad_flows(t *testing.T) {
 
 func TestShiftController_NewShiftController__success(t *testing.T) {
 	// Act
-	controller, err := controllers.NewShiftController(&mocks.MockIShiftStore{}, &mocks.MockISoldierStore{},
-		test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftController(&mocks.MockIShiftStore{}, newEmptyDayStore(), &mocks.MockISoldierStore{}, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
 
 	// Assert
 	assert.NoError(t, err)
@@ -97,7 +221,8 @@ func TestShiftController_CreateShift__invalid_request_body(t *testing.T) {
 	app := fiber.New()
 	shiftStore := &mocks.MockIShiftStore{}
 	soldierStore := &mocks.MockISoldierStore{}
-	controller, err := controllers.NewShiftController(shiftStore, soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftController(shiftStore, newEmptyDayStore(), soldierStore, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -116,12 +241,21 @@ func TestShiftController_CreateShift__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	shiftStore := &mocks.MockIShiftStore{}
-	shiftStore.On("CreateNewShift", mock.MatchedBy(func(arg models.Shift) bool {
-		return arg.Name == testShiftName
-	})).Return(nil)
+	storedShifts := []models.Shift{testShiftModel}
+	storedShifts[0].Version = 1
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+	shiftStore.On("CreateNewShift", mock.Anything, mock.MatchedBy(func(arg models.Shift) bool {
+		return arg.Name == testShiftName && arg.ID != "" && arg.ID != testShiftModel.ID
+	})).Run(func(args mock.Arguments) {
+		storedShifts[0].ID = args.Get(1).(models.Shift).ID
+	}).Return(nil)
+	shiftStore.On("FindShiftByID", mock.Anything, mock.MatchedBy(func(id string) bool {
+		return id == storedShifts[0].ID
+	})).Return(storedShifts, nil)
 	soldierStore := &mocks.MockISoldierStore{}
-	soldierStore.On("FindSoldierByID", commanderID).Return([]models.Soldier{testCommander}, nil)
-	controller, err := controllers.NewShiftController(shiftStore, soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	soldierStore.On("FindSoldierByID", mock.Anything, commanderID).Return([]models.Soldier{testCommander}, nil)
+	controller, err := controllers.NewShiftController(shiftStore, newEmptyDayStore(), soldierStore, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -134,14 +268,290 @@ func TestShiftController_CreateShift__success(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Equal(t, controllers.CreateShiftRoute+"/"+storedShifts[0].ID, resp.Header.Get(fiber.HeaderLocation))
+	assert.Equal(t, `"1"`, resp.Header.Get(fiber.HeaderETag))
+	var respBody api.CreatedShiftRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	require.NoError(t, err)
+	assert.Equal(t, storedShifts[0].ID, respBody.ID)
+	assert.Equal(t, testShiftName, respBody.Name)
+	assert.Nil(t, respBody.StaffingIssues)
+	shiftStore.AssertExpectations(t)
+}
+
+func TestShiftController_CreateShift__forbidden_role(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	shiftStore := &mocks.MockIShiftStore{}
+	controller, err := controllers.NewShiftController(shiftStore, newEmptyDayStore(), &mocks.MockISoldierStore{}, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode,
+		test_utils.NewRoleJWTMiddleware(models.SoldierUserRole, commanderID))
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodPost, controllers.CreateShiftRoute, test_utils.WrapStructWithReader(t, testShiftModel))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	shiftStore.AssertNotCalled(t, "CreateNewShift", mock.Anything, mock.Anything)
+}
+
+func TestShiftController_CreateShift__rest_violation(t *testing.T) {
+	// Arrange
+	previousShift := testShiftModel
+	previousShift.ID = "previous"
+	previousShift.StartTime = testShiftModel.StartTime.Add(-13 * time.Hour)
+	previousShift.EndTime = testShiftModel.StartTime.Add(-time.Hour)
+	app := fiber.New()
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{previousShift}, nil)
+	soldierStore := &mocks.MockISoldierStore{}
+	controller, err := controllers.NewShiftController(shiftStore, newEmptyDayStore(), soldierStore, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodPost, controllers.CreateShiftRoute, test_utils.WrapStructWithReader(t, testShiftModel))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	var respBody api.ShiftConflictRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	assert.NoError(t, err)
+	assert.Equal(t, []scheduling.RestViolation{{
+		SoldierID:          commanderID,
+		ShiftID:            "",
+		ConflictingShiftID: previousShift.ID,
+		RequiredRest:       8 * time.Hour,
+		ActualRest:         time.Hour,
+	}}, respBody.RestViolations)
+	shiftStore.AssertNotCalled(t, "CreateNewShift", mock.Anything, mock.Anything)
+}
+
+func TestShiftController_CreateShift__double_booked_soldier(t *testing.T) {
+	// Arrange
+	overlappingShift := testShiftModel
+	overlappingShift.ID = "overlapping"
+	overlappingShift.StartTime = testShiftModel.StartTime.Add(30 * time.Minute)
+	overlappingShift.EndTime = testShiftModel.EndTime.Add(30 * time.Minute)
+	app := fiber.New()
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{overlappingShift}, nil)
+	controller, err := controllers.NewShiftController(shiftStore, newEmptyDayStore(), &mocks.MockISoldierStore{}, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodPost, controllers.CreateShiftRoute, test_utils.WrapStructWithReader(t, testShiftModel))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	var respBody api.ShiftConflictRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	assert.NoError(t, err)
+	require.Len(t, respBody.Overlaps, 1)
+	assert.Equal(t, []string{"", overlappingShift.ID}, respBody.Overlaps[0].ShiftIDs)
+	assert.Empty(t, respBody.RestViolations)
+	shiftStore.AssertNotCalled(t, "CreateNewShift", mock.Anything, mock.Anything)
+}
+
+func TestShiftController_CreateShift__double_booked_in_day_schedule(t *testing.T) {
+	// Arrange
+	overlappingShift := testShiftModel
+	overlappingShift.ID = "overlapping"
+	overlappingShift.StartTime = testShiftModel.StartTime.Add(30 * time.Minute)
+	overlappingShift.EndTime = testShiftModel.EndTime.Add(30 * time.Minute)
+	app := fiber.New()
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+	dayStore := &mocks.MockIDayStore{}
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{{
+		Date:   testShiftModel.StartTime,
+		Shifts: []models.Shift{overlappingShift},
+	}}, nil)
+	controller, err := controllers.NewShiftController(shiftStore, dayStore, &mocks.MockISoldierStore{}, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodPost, controllers.CreateShiftRoute, test_utils.WrapStructWithReader(t, testShiftModel))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	var respBody api.ShiftConflictRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	assert.NoError(t, err)
+	require.Len(t, respBody.Overlaps, 1)
+	assert.Equal(t, []string{"", overlappingShift.ID}, respBody.Overlaps[0].ShiftIDs)
+	shiftStore.AssertNotCalled(t, "CreateNewShift", mock.Anything, mock.Anything)
+}
+
+var testDriverTemplate = models.ShiftTemplate{
+	ID:        "driver-template",
+	Name:      testShiftName,
+	ShiftType: models.MotorizedPatrolShiftType,
+	PersonnelRequirement: models.PersonnelRequirement{
+		SoldierRoleToCount: map[string]int{"Commander": 1, "Driver": 1},
+	},
+}
+
+func TestShiftController_CreateShift__understaffed(t *testing.T) {
+	testCases := []struct {
+		name           string
+		staffingMode   scheduling.StaffingMode
+		expectedStatus int
+		expectCreated  bool
+	}{
+		{name: "strict mode rejects", staffingMode: scheduling.StrictStaffingMode,
+			expectedStatus: fiber.StatusUnprocessableEntity, expectCreated: false},
+		{name: "warn mode flags", staffingMode: scheduling.WarnStaffingMode,
+			expectedStatus: fiber.StatusCreated, expectCreated: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			shift := testShiftModel
+			shift.ShiftTemplateID = testDriverTemplate.ID
+			app := fiber.New()
+			shiftStore := &mocks.MockIShiftStore{}
+			shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+			shiftStore.On("CreateNewShift", mock.Anything, mock.MatchedBy(func(arg models.Shift) bool {
+				return arg.Understaffed
+			})).Return(nil)
+			shiftStore.On("FindShiftByID", mock.Anything, mock.Anything).Return([]models.Shift{shift}, nil)
+			soldierStore := &mocks.MockISoldierStore{}
+			soldierStore.On("FindSoldierByID", mock.Anything, commanderID).Return([]models.Soldier{testCommander}, nil)
+			shiftTemplateStore := &mocks.MockIShiftTemplateStore{}
+			shiftTemplateStore.On("FindShiftTemplateByID", mock.Anything, testDriverTemplate.ID).Return([]models.ShiftTemplate{testDriverTemplate}, nil)
+			controller, err := controllers.NewShiftController(shiftStore, newEmptyDayStore(), soldierStore, shiftTemplateStore, newEmptyLeaveStore(),
+				newEmptyRotationStore(), newTestRestPolicy(t), testCase.staffingMode, test_utils.AlwaysAllowedJWTMiddleware)
+			require.NoError(t, err)
+			err = controller.RegisterRoutes(app)
+			require.NoError(t, err)
+			req := httptest.NewRequest(fiber.MethodPost, controllers.CreateShiftRoute, test_utils.WrapStructWithReader(t, shift))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			// Act
+			resp, err := app.Test(req, test_utils.TestTimeout)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedStatus, resp.StatusCode)
+			expectedShortfalls := []scheduling.RoleShortfall{{Role: "Driver", Required: 1, Assigned: 0}}
+			if testCase.expectCreated {
+				var respBody api.CreatedShiftRespBody
+				err = json.NewDecoder(resp.Body).Decode(&respBody)
+				assert.NoError(t, err)
+				require.NotNil(t, respBody.StaffingIssues)
+				assert.Equal(t, expectedShortfalls, respBody.StaffingIssues.Shortfalls)
+				shiftStore.AssertCalled(t, "CreateNewShift", mock.Anything, mock.Anything)
+			} else {
+				var respBody api.StaffingIssuesRespBody
+				err = json.NewDecoder(resp.Body).Decode(&respBody)
+				assert.NoError(t, err)
+				assert.Equal(t, expectedShortfalls, respBody.Shortfalls)
+				shiftStore.AssertNotCalled(t, "CreateNewShift", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestShiftController_CreateShift__soldier_on_leave(t *testing.T) {
+	// Arrange
+	leave := models.Leave{
+		ID:        "leave",
+		SoldierID: commanderID,
+		Type:      models.SickLeaveType,
+		StartTime: testShiftModel.StartTime.Add(-time.Hour),
+		EndTime:   testShiftModel.EndTime.Add(time.Hour),
+	}
+	app := fiber.New()
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+	soldierStore := &mocks.MockISoldierStore{}
+	soldierStore.On("FindSoldierByID", mock.Anything, commanderID).Return([]models.Soldier{testCommander}, nil)
+	leaveStore := &mocks.MockILeaveStore{}
+	leaveStore.On("FindLeavesBySoldierID", mock.Anything, commanderID).Return([]models.Leave{leave}, nil)
+	controller, err := controllers.NewShiftController(shiftStore, newEmptyDayStore(), soldierStore, &mocks.MockIShiftTemplateStore{}, leaveStore,
+		newEmptyRotationStore(), newTestRestPolicy(t), scheduling.StrictStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodPost, controllers.CreateShiftRoute, test_utils.WrapStructWithReader(t, testShiftModel))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	var respBody api.StaffingIssuesRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	assert.NoError(t, err)
+	assert.Equal(t, []scheduling.LeaveConflict{{
+		SoldierID: commanderID,
+		LeaveID:   leave.ID,
+		LeaveType: models.SickLeaveType,
+	}}, respBody.OnLeave)
+	shiftStore.AssertNotCalled(t, "CreateNewShift", mock.Anything, mock.Anything)
+}
+
+func TestShiftController_CreateShift__unknown_template(t *testing.T) {
+	// Arrange
+	shift := testShiftModel
+	shift.ShiftTemplateID = "unknown"
+	app := fiber.New()
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+	shiftTemplateStore := &mocks.MockIShiftTemplateStore{}
+	shiftTemplateStore.On("FindShiftTemplateByID", mock.Anything, "unknown").Return([]models.ShiftTemplate{}, nil)
+	soldierStore := &mocks.MockISoldierStore{}
+	soldierStore.On("FindSoldierByID", mock.Anything, commanderID).Return([]models.Soldier{testCommander}, nil)
+	controller, err := controllers.NewShiftController(shiftStore, newEmptyDayStore(), soldierStore, shiftTemplateStore, newEmptyLeaveStore(),
+		newEmptyRotationStore(), newTestRestPolicy(t), scheduling.StrictStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodPost, controllers.CreateShiftRoute, test_utils.WrapStructWithReader(t, shift))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	shiftStore.AssertNotCalled(t, "CreateNewShift", mock.Anything, mock.Anything)
 }
 
 func TestShiftController_GetShift__not_found(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	shiftStore := &mocks.MockIShiftStore{}
-	shiftStore.On("FindShiftByID", mock.Anything).Return([]models.Shift{}, nil)
-	controller, err := controllers.NewShiftController(shiftStore, &mocks.MockISoldierStore{}, test_utils.AlwaysAllowedJWTMiddleware)
+	shiftStore.On("FindShiftByID", mock.Anything, mock.Anything).Return([]models.Shift{}, nil)
+	controller, err := controllers.NewShiftController(shiftStore, newEmptyDayStore(), &mocks.MockISoldierStore{}, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -159,8 +569,9 @@ func TestShiftController_GetShift__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	shiftStore := &mocks.MockIShiftStore{}
-	shiftStore.On("FindShiftByID", shiftID).Return([]models.Shift{testShiftModel}, nil)
-	controller, err := controllers.NewShiftController(shiftStore, &mocks.MockISoldierStore{}, test_utils.AlwaysAllowedJWTMiddleware)
+	shiftStore.On("FindShiftByID", mock.Anything, shiftID).Return([]models.Shift{testShiftModel}, nil)
+	controller, err := controllers.NewShiftController(shiftStore, newEmptyDayStore(), &mocks.MockISoldierStore{}, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -176,6 +587,75 @@ func TestShiftController_GetShift__success(t *testing.T) {
 	err = json.NewDecoder(resp.Body).Decode(&respShift)
 	assert.NoError(t, err)
 	assert.Equal(t, testShiftModel, respShift)
+	assert.Equal(t, `"1"`, resp.Header.Get(fiber.HeaderETag))
+}
+
+func TestShiftController_GetAllShifts__filtered(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	shiftStore := &mocks.MockIShiftStore{}
+	staticPost := models.StaticPostShiftType
+	expectedFilter := store.ShiftFilter{
+		From:            time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+		To:              time.Date(2026, time.October, 8, 0, 0, 0, 0, time.UTC),
+		SoldierID:       commanderID,
+		Type:            &staticPost,
+		ShiftTemplateID: testDriverTemplate.ID,
+	}
+	shiftStore.On("FindShifts", mock.Anything, expectedFilter).Return([]models.Shift{testShiftModel}, nil)
+	controller, err := controllers.NewShiftController(shiftStore, newEmptyDayStore(), &mocks.MockISoldierStore{}, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodGet, fmt.Sprintf("%s?from=2026-10-01&to=2026-10-07&soldierId=%s&type=%d&shiftTemplateId=%s",
+		controllers.GetAllShiftsRoute, commanderID, staticPost, testDriverTemplate.ID), nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var respShifts []models.Shift
+	err = json.NewDecoder(resp.Body).Decode(&respShifts)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Shift{testShiftModel}, respShifts)
+	shiftStore.AssertExpectations(t)
+}
+
+func TestShiftController_GetAllShifts__invalid_filter(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{name: "malformed from date", query: "from=01-10-2026"},
+		{name: "malformed to date", query: "to=tomorrow"},
+		{name: "range end before range start", query: "from=2026-10-07&to=2026-10-01"},
+		{name: "unknown shift type", query: "type=42"},
+		{name: "non numeric shift type", query: "type=patrol"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			app := fiber.New()
+			shiftStore := &mocks.MockIShiftStore{}
+			controller, err := controllers.NewShiftController(shiftStore, newEmptyDayStore(), &mocks.MockISoldierStore{}, &mocks.MockIShiftTemplateStore{},
+				newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
+			require.NoError(t, err)
+			err = controller.RegisterRoutes(app)
+			require.NoError(t, err)
+			req := httptest.NewRequest(fiber.MethodGet, controllers.GetAllShiftsRoute+"?"+testCase.query, nil)
+
+			// Act
+			resp, err := app.Test(req, test_utils.TestTimeout)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+			shiftStore.AssertNotCalled(t, "FindShifts", mock.Anything, mock.Anything)
+		})
+	}
 }
 
 func TestShiftController_UpdateShift__invalid_request_body(t *testing.T) {
@@ -183,12 +663,14 @@ func TestShiftController_UpdateShift__invalid_request_body(t *testing.T) {
 	app := fiber.New()
 	shiftStore := &mocks.MockIShiftStore{}
 	soldierStore := &mocks.MockISoldierStore{}
-	controller, err := controllers.NewShiftController(shiftStore, soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftController(shiftStore, newEmptyDayStore(), soldierStore, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/shifts/%s", shiftID),
 		test_utils.WrapStructWithReader(t, "invalid"))
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
 	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
 
 	// Act
@@ -203,14 +685,16 @@ func TestShiftController_UpdateShift__not_found(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	shiftStore := &mocks.MockIShiftStore{}
-	shiftStore.On("FindShiftByID", shiftID).Return([]models.Shift{}, nil)
+	shiftStore.On("FindShiftByID", mock.Anything, shiftID).Return([]models.Shift{}, nil)
 	soldierStore := &mocks.MockISoldierStore{}
-	controller, err := controllers.NewShiftController(shiftStore, soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftController(shiftStore, newEmptyDayStore(), soldierStore, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/shifts/%s", shiftID),
 		test_utils.WrapStructWithReader(t, testShiftModel))
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
 	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
 
 	// Act
@@ -221,24 +705,75 @@ func TestShiftController_UpdateShift__not_found(t *testing.T) {
 	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
 }
 
+func TestShiftController_UpdateShift__stale_version(t *testing.T) {
+	// Arrange
+	updatedShift := testShiftModel
+	updatedShift.Name = "Updated Shift"
+	app := fiber.New()
+	shiftStoreMock := &mocks.MockIShiftStore{}
+	shiftStoreMock.On("FindShiftByID", mock.Anything, shiftID).Return([]models.Shift{testShiftModel}, nil)
+	controller, err := controllers.NewShiftController(shiftStoreMock, newEmptyDayStore(), &mocks.MockISoldierStore{}, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/shifts/%s", shiftID),
+		test_utils.WrapStructWithReader(t, updatedShift))
+	req.Header.Set(fiber.HeaderIfMatch, `"0"`)
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusPreconditionFailed, resp.StatusCode)
+	shiftStoreMock.AssertNotCalled(t, "UpdateShift", mock.Anything, mock.Anything)
+}
+
+func TestShiftController_UpdateShift__missing_if_match(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	shiftStoreMock := &mocks.MockIShiftStore{}
+	controller, err := controllers.NewShiftController(shiftStoreMock, newEmptyDayStore(), &mocks.MockISoldierStore{}, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/shifts/%s", shiftID),
+		test_utils.WrapStructWithReader(t, testShiftModel))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusPreconditionRequired, resp.StatusCode)
+	shiftStoreMock.AssertNotCalled(t, "UpdateShift", mock.Anything, mock.Anything)
+}
+
 func TestShiftController_UpdateShift__success(t *testing.T) {
 	// Arrange
 	updatedShift := testShiftModel
 	updatedShift.Name = "Updated Shift"
 	app := fiber.New()
 	shiftStoreMock := &mocks.MockIShiftStore{}
-	shiftStoreMock.On("FindShiftByID", shiftID).Return([]models.Shift{testShiftModel}, nil)
-	shiftStoreMock.On("UpdateShift", mock.MatchedBy(func(arg models.Shift) bool {
+	shiftStoreMock.On("FindShiftByID", mock.Anything, shiftID).Return([]models.Shift{testShiftModel}, nil)
+	shiftStoreMock.On("FindAllShifts", mock.Anything).Return([]models.Shift{testShiftModel}, nil)
+	shiftStoreMock.On("UpdateShift", mock.Anything, mock.MatchedBy(func(arg models.Shift) bool {
 		return arg.Name == updatedShift.Name
 	})).Return(nil)
 	soldierStore := &mocks.MockISoldierStore{}
-	soldierStore.On("FindSoldierByID", testShiftModel.Commander.ID).Return([]models.Soldier{testCommander}, nil)
-	controller, err := controllers.NewShiftController(shiftStoreMock, soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	soldierStore.On("FindSoldierByID", mock.Anything, testShiftModel.Commander.ID).Return([]models.Soldier{testCommander}, nil)
+	controller, err := controllers.NewShiftController(shiftStoreMock, newEmptyDayStore(), soldierStore, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/shifts/%s", shiftID),
 		test_utils.WrapStructWithReader(t, updatedShift))
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
 	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
 
 	// Act
@@ -247,20 +782,76 @@ func TestShiftController_UpdateShift__success(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, `"2"`, resp.Header.Get(fiber.HeaderETag))
 	shiftStoreMock.AssertExpectations(t)
 }
 
+func TestShiftController_UpdateShift__rest_violation(t *testing.T) {
+	// Arrange
+	nextShift := testShiftModel
+	nextShift.ID = "next"
+	nextShift.StartTime = testShiftModel.EndTime
+	nextShift.EndTime = testShiftModel.EndTime.Add(12 * time.Hour)
+	app := fiber.New()
+	shiftStoreMock := &mocks.MockIShiftStore{}
+	shiftStoreMock.On("FindShiftByID", mock.Anything, shiftID).Return([]models.Shift{testShiftModel}, nil)
+	shiftStoreMock.On("FindAllShifts", mock.Anything).Return([]models.Shift{testShiftModel, nextShift}, nil)
+	controller, err := controllers.NewShiftController(shiftStoreMock, newEmptyDayStore(), &mocks.MockISoldierStore{}, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/shifts/%s", shiftID),
+		test_utils.WrapStructWithReader(t, testShiftModel))
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	shiftStoreMock.AssertNotCalled(t, "UpdateShift", mock.Anything, mock.Anything)
+}
+
 func TestShiftController_DeleteShift__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	shiftStoreMock := &mocks.MockIShiftStore{}
-	shiftStoreMock.On("DeleteShift", shiftID).Return(nil)
+	shiftStoreMock.On("DeleteShift", mock.Anything, shiftID, 1).Return(nil)
 	soldierStore := &mocks.MockISoldierStore{}
-	controller, err := controllers.NewShiftController(shiftStoreMock, soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftController(shiftStoreMock, newEmptyDayStore(), soldierStore, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 	req := httptest.NewRequest(fiber.MethodDelete, fmt.Sprintf("/shifts/%s", shiftID), nil)
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	shiftStoreMock.AssertExpectations(t)
+}
+
+func TestShiftController_RestoreShift__success(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	restoredShift := testShiftModel
+	restoredShift.Version = 3
+	shiftStoreMock := &mocks.MockIShiftStore{}
+	shiftStoreMock.On("RestoreShift", mock.Anything, shiftID).Return(nil)
+	shiftStoreMock.On("FindShiftByID", mock.Anything, shiftID).Return([]models.Shift{restoredShift}, nil)
+	controller, err := controllers.NewShiftController(shiftStoreMock, newEmptyDayStore(), &mocks.MockISoldierStore{}, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodPost, fmt.Sprintf("/shifts/%s/restore", shiftID), nil)
 
 	// Act
 	resp, err := app.Test(req, test_utils.TestTimeout)
@@ -268,5 +859,31 @@ func TestShiftController_DeleteShift__success(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, `"3"`, resp.Header.Get(fiber.HeaderETag))
+	var respShift models.Shift
+	err = json.NewDecoder(resp.Body).Decode(&respShift)
+	assert.NoError(t, err)
+	assert.Equal(t, restoredShift.ID, respShift.ID)
 	shiftStoreMock.AssertExpectations(t)
 }
+
+func TestShiftController_RestoreShift__not_deleted(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	shiftStoreMock := &mocks.MockIShiftStore{}
+	shiftStoreMock.On("RestoreShift", mock.Anything, shiftID).Return(fmt.Errorf("shift %s: %w", shiftID, store.ErrNotDeleted))
+	controller, err := controllers.NewShiftController(shiftStoreMock, newEmptyDayStore(), &mocks.MockISoldierStore{}, &mocks.MockIShiftTemplateStore{},
+		newEmptyLeaveStore(), newEmptyRotationStore(), newTestRestPolicy(t), scheduling.WarnStaffingMode, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodPost, fmt.Sprintf("/shifts/%s/restore", shiftID), nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	shiftStoreMock.AssertNotCalled(t, "FindShiftByID", mock.Anything, mock.Anything)
+}
@@ -3,33 +3,58 @@ package controllers
 import (
 	"brothers_in_batash/internal/pkg/logging"
 	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
 	"brothers_in_batash/internal/pkg/store"
+	"brothers_in_batash/internal/pkg/utils"
+	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// errGenerationFailed marks shift generation failures caused by the request, rather than by the stores
+var errGenerationFailed = errors.New("could not generate shifts out of shift template")
+
 type ShiftTemplateController struct {
 	shiftTemplateStore store.IShiftTemplateStore
+	shiftStore         store.IShiftStore
+	transactor         store.ITransactor
 	authMiddleware     fiber.Handler
 }
 
-func NewShiftTemplateController(shiftTemplateStore store.IShiftTemplateStore, authMiddleware fiber.Handler) (*ShiftTemplateController, error) {
+func NewShiftTemplateController(shiftTemplateStore store.IShiftTemplateStore, shiftStore store.IShiftStore,
+	transactor store.ITransactor, authMiddleware fiber.Handler) (*ShiftTemplateController, error) {
 	if shiftTemplateStore == nil {
 		return nil, errors.New("shiftTemplateStore is nil")
 	}
+	if shiftStore == nil {
+		return nil, errors.New("shiftStore is nil")
+	}
+	if transactor == nil {
+		return nil, errors.New("transactor is nil")
+	}
 	if authMiddleware == nil {
 		return nil, errors.New("authMiddleware is nil")
 	}
-	return &ShiftTemplateController{shiftTemplateStore: shiftTemplateStore, authMiddleware: authMiddleware}, nil
+	return &ShiftTemplateController{
+		shiftTemplateStore: shiftTemplateStore,
+		shiftStore:         shiftStore,
+		transactor:         transactor,
+		authMiddleware:     authMiddleware,
+	}, nil
 }
 
 func (c *ShiftTemplateController) RegisterRoutes(router fiber.Router) error {
-	router.Post(CreateShiftTemplateRoute, c.authMiddleware, c.createShiftTemplate)
-	router.Get(GetShiftTemplateRoute, c.authMiddleware, c.getShiftTemplate)
-	router.Get(GetAllShiftTemplatesRoute, c.authMiddleware, c.getAllShiftTemplates)
-	router.Put(UpdateShiftTemplateRoute, c.authMiddleware, c.updateShiftTemplate)
-	router.Delete(DeleteShiftTemplateRoute, c.authMiddleware, c.deleteShiftTemplate)
+	router.Post(CreateShiftTemplateRoute, c.authMiddleware, requirePlatoonAdmin, c.createShiftTemplate)
+	router.Get(GetShiftTemplateRoute, c.authMiddleware, requireViewer, c.getShiftTemplate)
+	router.Get(GetAllShiftTemplatesRoute, c.authMiddleware, requireViewer, c.getAllShiftTemplates)
+	router.Put(UpdateShiftTemplateRoute, c.authMiddleware, requirePlatoonAdmin, c.updateShiftTemplate)
+	router.Delete(DeleteShiftTemplateRoute, c.authMiddleware, requirePlatoonAdmin, c.deleteShiftTemplate)
+	router.Post(RestoreShiftTemplateRoute, c.authMiddleware, requirePlatoonAdmin, c.restoreShiftTemplate)
+	router.Post(GenerateShiftsFromTemplateRoute, c.authMiddleware, requireSquadCommander, c.generateShifts)
 	return nil
 }
 
@@ -39,17 +64,27 @@ func (c *ShiftTemplateController) createShiftTemplate(ctx *fiber.Ctx) error {
 		logging.Debug("Could not parse shift template creation request body", []logging.LogProp{{"error", err.Error()}})
 		return ctx.SendStatus(fiber.StatusBadRequest)
 	}
+	shiftTemplate.ID = utils.NewEntityID()
 
-	if err := c.shiftTemplateStore.CreateNewShiftTemplate(shiftTemplate); err != nil {
+	if err := c.shiftTemplateStore.CreateNewShiftTemplate(ctx.UserContext(), shiftTemplate); err != nil {
 		logging.Warning(err, "error on creating new shift template", nil)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
-	return ctx.SendStatus(fiber.StatusCreated)
+	shiftTemplates, err := c.shiftTemplateStore.FindShiftTemplateByID(ctx.UserContext(), shiftTemplate.ID)
+	if err != nil {
+		logging.Warning(err, "could not query for created shift template", []logging.LogProp{{"shiftTemplateID", shiftTemplate.ID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if len(shiftTemplates) == 0 {
+		logging.Trace("created shift template was deleted already", []logging.LogProp{{"shiftTemplateID", shiftTemplate.ID}})
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	setETag(ctx, shiftTemplates[0].Version)
+	return sendCreated(ctx, shiftTemplate.ID, shiftTemplates[0])
 }
 
 func (c *ShiftTemplateController) getShiftTemplate(ctx *fiber.Ctx) error {
 	shiftTemplateID := ctx.Params("id")
-	shiftTemplates, err := c.shiftTemplateStore.FindShiftTemplateByID(shiftTemplateID)
+	shiftTemplates, err := c.shiftTemplateStore.FindShiftTemplateByID(ctx.UserContext(), shiftTemplateID)
 	if err != nil {
 		logging.Warning(err, "Could not query for shift template", []logging.LogProp{{"shiftTemplateID", shiftTemplateID}})
 		return ctx.SendStatus(fiber.StatusInternalServerError)
@@ -57,11 +92,12 @@ func (c *ShiftTemplateController) getShiftTemplate(ctx *fiber.Ctx) error {
 		logging.Trace("shift template not found", []logging.LogProp{{"shiftTemplateID", shiftTemplateID}})
 		return ctx.SendStatus(fiber.StatusNotFound)
 	}
+	setETag(ctx, shiftTemplates[0].Version)
 	return ctx.JSON(shiftTemplates[0])
 }
 
 func (c *ShiftTemplateController) getAllShiftTemplates(ctx *fiber.Ctx) error {
-	shiftTemplates, err := c.shiftTemplateStore.FindAllShiftsTemplate()
+	shiftTemplates, err := c.shiftTemplateStore.FindAllShiftsTemplate(ctx.UserContext())
 	if err != nil {
 		logging.Warning(err, "error on fetching all shift templates", nil)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
@@ -71,6 +107,10 @@ func (c *ShiftTemplateController) getAllShiftTemplates(ctx *fiber.Ctx) error {
 
 func (c *ShiftTemplateController) updateShiftTemplate(ctx *fiber.Ctx) error {
 	shiftTemplateID := ctx.Params("id")
+	version, status, ok := ifMatchVersion(ctx)
+	if !ok {
+		return ctx.SendStatus(status)
+	}
 	shiftTemplate := models.ShiftTemplate{}
 	if err := ctx.BodyParser(&shiftTemplate); err != nil {
 		logging.Info("Could not parse shift template update request body", []logging.LogProp{{"error", err.Error()}})
@@ -78,18 +118,138 @@ func (c *ShiftTemplateController) updateShiftTemplate(ctx *fiber.Ctx) error {
 	}
 
 	shiftTemplate.ID = shiftTemplateID
-	if err := c.shiftTemplateStore.UpdateShiftTemplate(shiftTemplate); err != nil {
+	shiftTemplate.Version = version
+	if err := c.shiftTemplateStore.UpdateShiftTemplate(ctx.UserContext(), shiftTemplate); errors.Is(err, store.ErrVersionConflict) {
+		logging.Debug("shift template was modified since it was read",
+			[]logging.LogProp{{"shiftTemplateID", shiftTemplateID}, {"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusPreconditionFailed)
+	} else if err != nil {
 		logging.Warning(err, "error on updating shift template", []logging.LogProp{{"shiftTemplateID", shiftTemplateID}})
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
+	setETag(ctx, version+1)
 	return ctx.SendStatus(fiber.StatusOK)
 }
 
 func (c *ShiftTemplateController) deleteShiftTemplate(ctx *fiber.Ctx) error {
 	shiftTemplateID := ctx.Params("id")
-	if err := c.shiftTemplateStore.DeleteShiftTemplate(shiftTemplateID); err != nil {
+	version, status, ok := ifMatchVersion(ctx)
+	if !ok {
+		return ctx.SendStatus(status)
+	}
+	if err := c.shiftTemplateStore.DeleteShiftTemplate(ctx.UserContext(), shiftTemplateID, version); errors.Is(err, store.ErrVersionConflict) {
+		logging.Debug("shift template was modified since it was read",
+			[]logging.LogProp{{"shiftTemplateID", shiftTemplateID}, {"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusPreconditionFailed)
+	} else if err != nil {
 		logging.Warning(err, "error on deleting shift template", []logging.LogProp{{"shiftTemplateID", shiftTemplateID}})
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
 	return ctx.SendStatus(fiber.StatusOK)
 }
+
+// restoreShiftTemplate undoes the deletion of a shift template that was not purged yet, and returns the restored shift template
+func (c *ShiftTemplateController) restoreShiftTemplate(ctx *fiber.Ctx) error {
+	shiftTemplateID := ctx.Params("id")
+	if err := c.shiftTemplateStore.RestoreShiftTemplate(ctx.UserContext(), shiftTemplateID); errors.Is(err, store.ErrNotDeleted) {
+		logging.Trace("no deleted shift template to restore", []logging.LogProp{{"shiftTemplateID", shiftTemplateID}})
+		return ctx.SendStatus(fiber.StatusNotFound)
+	} else if err != nil {
+		logging.Warning(err, "error on restoring shift template", []logging.LogProp{{"shiftTemplateID", shiftTemplateID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	shiftTemplates, err := c.shiftTemplateStore.FindShiftTemplateByID(ctx.UserContext(), shiftTemplateID)
+	if err != nil {
+		logging.Warning(err, "could not query for restored shift template", []logging.LogProp{{"shiftTemplateID", shiftTemplateID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if len(shiftTemplates) == 0 {
+		logging.Trace("restored shift template was deleted again", []logging.LogProp{{"shiftTemplateID", shiftTemplateID}})
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	setETag(ctx, shiftTemplates[0].Version)
+	return ctx.JSON(shiftTemplates[0])
+}
+
+func (c *ShiftTemplateController) generateShifts(ctx *fiber.Ctx) error {
+	shiftTemplateID := ctx.Params("id")
+	from, err := time.Parse("2006-01-02", ctx.Query("from"))
+	if err != nil {
+		logging.Debug("Invalid from date format", []logging.LogProp{{"from", ctx.Query("from")}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	to, err := time.Parse("2006-01-02", ctx.Query("to"))
+	if err != nil {
+		logging.Debug("Invalid to date format", []logging.LogProp{{"to", ctx.Query("to")}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	shiftTemplates, err := c.shiftTemplateStore.FindShiftTemplateByID(ctx.UserContext(), shiftTemplateID)
+	if err != nil {
+		logging.Warning(err, "Could not query for shift template", []logging.LogProp{{"shiftTemplateID", shiftTemplateID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if len(shiftTemplates) == 0 {
+		logging.Trace("shift template not found", []logging.LogProp{{"shiftTemplateID", shiftTemplateID}})
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+
+	// The shifts and the day schedules they are attached to are written together - either all of them or none
+	var shifts []models.Shift
+	err = c.transactor.WithinTx(ctx.UserContext(), func(stores store.TxStores) error {
+		existingShifts, err := stores.Shifts.FindAllShifts(ctx.UserContext())
+		if err != nil {
+			return fmt.Errorf("could not fetch existing shifts: %w", err)
+		}
+		shifts, err = scheduling.GenerateShifts(shiftTemplates[0], from, to, existingShifts)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errGenerationFailed, err)
+		}
+		for _, shift := range shifts {
+			if err := stores.Shifts.CreateNewShift(ctx.UserContext(), shift); err != nil {
+				return fmt.Errorf("could not create generated shift %s: %w", shift.ID, err)
+			}
+		}
+		return attachToDaySchedules(ctx.UserContext(), stores.Days, shifts)
+	})
+	if errors.Is(err, errGenerationFailed) {
+		logging.Debug("Could not generate shifts out of shift template",
+			[]logging.LogProp{{"shiftTemplateID", shiftTemplateID}, {"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	} else if err != nil {
+		logging.Warning(err, "error on saving generated shifts", []logging.LogProp{{"shiftTemplateID", shiftTemplateID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(shifts)
+}
+
+// attachToDaySchedules references the shifts from the day schedules of the dates they start on, creating missing day
+// schedules. The shifts themselves stay in the shift store alone, so their later changes show in the day schedules too.
+func attachToDaySchedules(ctx context.Context, dayStore store.IDayStore, shifts []models.Shift) error {
+	shiftIDsByDate := make(map[time.Time][]string)
+	dates := make([]time.Time, 0)
+	for _, shift := range shifts {
+		start := shift.StartTime.UTC()
+		date := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		if _, exists := shiftIDsByDate[date]; !exists {
+			dates = append(dates, date)
+		}
+		shiftIDsByDate[date] = append(shiftIDsByDate[date], shift.ID)
+	}
+
+	for _, date := range dates {
+		daySchedules, err := dayStore.FindDaySchedule(ctx, date)
+		if err != nil {
+			return fmt.Errorf("could not fetch day schedule of %s: %w", date.Format("2006-01-02"), err)
+		}
+		if len(daySchedules) == 0 {
+			err = dayStore.CreateNewDaySchedule(ctx, models.DaySchedule{Date: date, ShiftIDs: shiftIDsByDate[date]})
+		} else {
+			daySchedule := daySchedules[0]
+			daySchedule.ShiftIDs = append(slices.Clone(daySchedule.ShiftIDs), shiftIDsByDate[date]...)
+			err = dayStore.UpdateDaySchedule(ctx, daySchedule)
+		}
+		if err != nil {
+			return fmt.Errorf("could not attach shifts to day schedule of %s: %w", date.Format("2006-01-02"), err)
+		}
+	}
+	return nil
+}
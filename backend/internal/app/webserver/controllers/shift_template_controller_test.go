@@ -4,8 +4,12 @@ import (
 	"brothers_in_batash/internal/app/webserver/controllers"
 	"brothers_in_batash/internal/pkg/mocks"
 	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"brothers_in_batash/internal/pkg/store"
 	"brothers_in_batash/internal/pkg/test_utils"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http/httptest"
 	"testing"
@@ -13,6 +17,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,11 +40,31 @@ var testShiftTemplate = models.ShiftTemplate{
 			},
 		},
 	},
+	Version: 1,
 }
 
 func TestShiftTemplateController_NewShiftTemplateController__error_on_nil_store(t *testing.T) {
 	// Act
-	controller, err := controllers.NewShiftTemplateController(nil, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftTemplateController(nil, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, controller)
+}
+
+func TestShiftTemplateController_NewShiftTemplateController__error_on_nil_shift_store(t *testing.T) {
+	// Act
+	controller, err := controllers.NewShiftTemplateController(new(mocks.MockIShiftTemplateStore), nil, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, controller)
+}
+
+func TestShiftTemplateController_NewShiftTemplateController__error_on_nil_transactor(t *testing.T) {
+	// Act
+	controller, err := controllers.NewShiftTemplateController(new(mocks.MockIShiftTemplateStore), &mocks.MockIShiftStore{}, nil,
+		test_utils.AlwaysAllowedJWTMiddleware)
 
 	// Assert
 	assert.Error(t, err)
@@ -51,7 +76,7 @@ func TestShiftTemplateController_NewShiftTemplateController__error_on_nil_middle
 	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
 
 	// Act
-	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, nil)
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, nil)
 
 	// Assert
 	assert.Error(t, err)
@@ -63,7 +88,7 @@ func TestShiftTemplateController_NewShiftTemplateController__success(t *testing.
 	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
 
 	// Act
-	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
 
 	// Assert
 	assert.NoError(t, err)
@@ -74,12 +99,12 @@ func TestShiftTemplateController_GetShiftTemplate__not_found(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
-	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 
-	shiftTemplateStore.On("FindShiftTemplateByID", shiftTemplateID).Return([]models.ShiftTemplate{}, nil)
+	shiftTemplateStore.On("FindShiftTemplateByID", mock.Anything, shiftTemplateID).Return([]models.ShiftTemplate{}, nil)
 
 	req := httptest.NewRequest(fiber.MethodGet, fmt.Sprintf("/shift-templates/%s", shiftTemplateID), nil)
 
@@ -96,12 +121,12 @@ func TestShiftTemplateController_GetShiftTemplate__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
-	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 
-	shiftTemplateStore.On("FindShiftTemplateByID", shiftTemplateID).Return([]models.ShiftTemplate{testShiftTemplate}, nil)
+	shiftTemplateStore.On("FindShiftTemplateByID", mock.Anything, shiftTemplateID).Return([]models.ShiftTemplate{testShiftTemplate}, nil)
 
 	req := httptest.NewRequest(fiber.MethodGet, fmt.Sprintf("/shift-templates/%s", shiftTemplateID), nil)
 
@@ -122,7 +147,7 @@ func TestShiftTemplateController_GetAllShiftTemplates__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
-	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -131,7 +156,7 @@ func TestShiftTemplateController_GetAllShiftTemplates__success(t *testing.T) {
 	anotherShiftTemplate.ID = "124"
 	shiftTemplates := []models.ShiftTemplate{testShiftTemplate, anotherShiftTemplate}
 
-	shiftTemplateStore.On("FindAllShiftsTemplate").Return(shiftTemplates, nil)
+	shiftTemplateStore.On("FindAllShiftsTemplate", mock.Anything).Return(shiftTemplates, nil)
 
 	req := httptest.NewRequest(fiber.MethodGet, controllers.GetAllShiftTemplatesRoute, nil)
 
@@ -152,14 +177,15 @@ func TestShiftTemplateController_DeleteShiftTemplate__not_found(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
-	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 
-	shiftTemplateStore.On("DeleteShiftTemplate", shiftTemplateID).Return(fmt.Errorf("shift template not found"))
+	shiftTemplateStore.On("DeleteShiftTemplate", mock.Anything, shiftTemplateID, 1).Return(fmt.Errorf("shift template not found"))
 
 	req := httptest.NewRequest(fiber.MethodDelete, fmt.Sprintf("/shift-templates/%s", shiftTemplateID), nil)
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
 
 	// Act
 	resp, err := app.Test(req, test_utils.TestTimeout)
@@ -174,14 +200,15 @@ func TestShiftTemplateController_DeleteShiftTemplate__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
-	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 
-	shiftTemplateStore.On("DeleteShiftTemplate", shiftTemplateID).Return(nil)
+	shiftTemplateStore.On("DeleteShiftTemplate", mock.Anything, shiftTemplateID, 1).Return(nil)
 
 	req := httptest.NewRequest(fiber.MethodDelete, fmt.Sprintf("/shift-templates/%s", shiftTemplateID), nil)
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
 
 	// Act
 	resp, err := app.Test(req, test_utils.TestTimeout)
@@ -196,7 +223,7 @@ func TestShiftTemplateController_CreateShiftTemplate__invalid_request_body(t *te
 	// Arrange
 	app := fiber.New()
 	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
-	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -216,12 +243,21 @@ func TestShiftTemplateController_CreateShiftTemplate__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
-	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 
-	shiftTemplateStore.On("CreateNewShiftTemplate", testShiftTemplate).Return(nil)
+	storedShiftTemplates := []models.ShiftTemplate{testShiftTemplate}
+	storedShiftTemplates[0].Version = 1
+	shiftTemplateStore.On("CreateNewShiftTemplate", mock.Anything, mock.MatchedBy(func(arg models.ShiftTemplate) bool {
+		return arg.ID != "" && arg.ID != testShiftTemplate.ID && arg.Name == testShiftTemplate.Name
+	})).Run(func(args mock.Arguments) {
+		storedShiftTemplates[0].ID = args.Get(1).(models.ShiftTemplate).ID
+	}).Return(nil)
+	shiftTemplateStore.On("FindShiftTemplateByID", mock.Anything, mock.MatchedBy(func(id string) bool {
+		return id == storedShiftTemplates[0].ID
+	})).Return(storedShiftTemplates, nil)
 
 	req := httptest.NewRequest(fiber.MethodPost, controllers.CreateShiftTemplateRoute, test_utils.WrapStructWithReader(t, testShiftTemplate))
 	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
@@ -232,6 +268,12 @@ func TestShiftTemplateController_CreateShiftTemplate__success(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Equal(t, controllers.CreateShiftTemplateRoute+"/"+storedShiftTemplates[0].ID, resp.Header.Get(fiber.HeaderLocation))
+	assert.Equal(t, `"1"`, resp.Header.Get(fiber.HeaderETag))
+	var createdShiftTemplate models.ShiftTemplate
+	err = json.NewDecoder(resp.Body).Decode(&createdShiftTemplate)
+	require.NoError(t, err)
+	assert.Equal(t, storedShiftTemplates[0], createdShiftTemplate)
 	shiftTemplateStore.AssertExpectations(t)
 }
 
@@ -239,13 +281,14 @@ func TestShiftTemplateController_UpdateShiftTemplate__invalid_request_body(t *te
 	// Arrange
 	app := fiber.New()
 	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
-	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 
 	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/shift-templates/%s", shiftTemplateID),
 		test_utils.WrapStructWithReader(t, "invalid"))
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
 	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
 
 	// Act
@@ -260,15 +303,16 @@ func TestShiftTemplateController_UpdateShiftTemplate__not_found(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
-	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 
-	shiftTemplateStore.On("UpdateShiftTemplate", testShiftTemplate).Return(fmt.Errorf("shift template not found"))
+	shiftTemplateStore.On("UpdateShiftTemplate", mock.Anything, testShiftTemplate).Return(fmt.Errorf("shift template not found"))
 
 	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/shift-templates/%s", shiftTemplateID),
 		test_utils.WrapStructWithReader(t, testShiftTemplate))
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
 	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
 
 	// Act
@@ -284,7 +328,7 @@ func TestShiftTemplateController_UpdateShiftTemplate__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
-	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -292,10 +336,11 @@ func TestShiftTemplateController_UpdateShiftTemplate__success(t *testing.T) {
 	updatedShiftTemplate := testShiftTemplate
 	updatedShiftTemplate.Name = "Updated Shift Template"
 
-	shiftTemplateStore.On("UpdateShiftTemplate", updatedShiftTemplate).Return(nil)
+	shiftTemplateStore.On("UpdateShiftTemplate", mock.Anything, updatedShiftTemplate).Return(nil)
 
 	req := httptest.NewRequest(fiber.MethodPut, fmt.Sprintf("/shift-templates/%s", shiftTemplateID),
 		test_utils.WrapStructWithReader(t, updatedShiftTemplate))
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
 	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
 
 	// Act
@@ -306,3 +351,235 @@ func TestShiftTemplateController_UpdateShiftTemplate__success(t *testing.T) {
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 	shiftTemplateStore.AssertExpectations(t)
 }
+
+func TestShiftTemplateController_GenerateShifts__invalid_dates(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{name: "missing dates", query: ""},
+		{name: "invalid from date", query: "?from=2025-13-01&to=2025-04-20"},
+		{name: "invalid to date", query: "?from=2025-04-14&to=tomorrow"},
+		{name: "end before start", query: "?from=2025-04-20&to=2025-04-14"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			app := fiber.New()
+			shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
+			shiftTemplateStore.On("FindShiftTemplateByID", mock.Anything, shiftTemplateID).Return([]models.ShiftTemplate{testShiftTemplate}, nil)
+			shiftStore := new(mocks.MockIShiftStore)
+			shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+			controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, shiftStore,
+				&mocks.MockITransactor{Stores: store.TxStores{Shifts: shiftStore}}, test_utils.AlwaysAllowedJWTMiddleware)
+			require.NoError(t, err)
+			err = controller.RegisterRoutes(app)
+			require.NoError(t, err)
+			req := httptest.NewRequest(fiber.MethodPost,
+				fmt.Sprintf("/shift-templates/%s/generate%s", shiftTemplateID, testCase.query), nil)
+
+			// Act
+			resp, err := app.Test(req, test_utils.TestTimeout)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+			shiftStore.AssertNotCalled(t, "CreateNewShift", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestShiftTemplateController_GenerateShifts__template_not_found(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
+	shiftTemplateStore.On("FindShiftTemplateByID", mock.Anything, shiftTemplateID).Return([]models.ShiftTemplate{}, nil)
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodPost,
+		fmt.Sprintf("/shift-templates/%s/generate?from=2025-04-14&to=2025-04-20", shiftTemplateID), nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	shiftTemplateStore.AssertExpectations(t)
+}
+
+func TestShiftTemplateController_GenerateShifts__success(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
+	shiftTemplateStore.On("FindShiftTemplateByID", mock.Anything, shiftTemplateID).Return([]models.ShiftTemplate{testShiftTemplate}, nil)
+	existingShift := models.Shift{
+		ID:              "existing",
+		Name:            testShiftTemplate.Name,
+		StartTime:       time.Date(2025, time.April, 14, 0, 0, 0, 0, time.UTC),
+		EndTime:         time.Date(2025, time.April, 14, 1, 0, 0, 0, time.UTC),
+		ShiftTemplateID: shiftTemplateID,
+	}
+	shiftStore := new(mocks.MockIShiftStore)
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{existingShift}, nil)
+	shiftStore.On("CreateNewShift", mock.Anything, mock.MatchedBy(func(arg models.Shift) bool {
+		return arg.ShiftTemplateID == shiftTemplateID
+	})).Return(nil)
+	dayStore := new(mocks.MockIDayStore)
+	generatedShiftDate := time.Date(2025, time.April, 21, 0, 0, 0, 0, time.UTC)
+	dayStore.On("FindDaySchedule", mock.Anything, generatedShiftDate).Return([]models.DaySchedule{}, nil)
+	dayStore.On("CreateNewDaySchedule", mock.Anything, mock.MatchedBy(func(arg models.DaySchedule) bool {
+		return arg.Date.Equal(generatedShiftDate) && len(arg.Shifts) == 0 && len(arg.ShiftIDs) == 1
+	})).Return(nil)
+	transactor := &mocks.MockITransactor{Stores: store.TxStores{Shifts: shiftStore, Days: dayStore}}
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, shiftStore, transactor,
+		test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	// 2025-04-14 and 2025-04-21 are both Mondays
+	req := httptest.NewRequest(fiber.MethodPost,
+		fmt.Sprintf("/shift-templates/%s/generate?from=2025-04-14&to=2025-04-21", shiftTemplateID), nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	var respShifts []models.Shift
+	err = json.NewDecoder(resp.Body).Decode(&respShifts)
+	assert.NoError(t, err)
+	require.Len(t, respShifts, 1)
+	assert.Equal(t, time.Date(2025, time.April, 21, 0, 0, 0, 0, time.UTC), respShifts[0].StartTime)
+	shiftStore.AssertNumberOfCalls(t, "CreateNewShift", 1)
+	dayStore.AssertExpectations(t)
+}
+
+func TestShiftTemplateController_GenerateShifts__day_schedule_failure(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
+	shiftTemplateStore.On("FindShiftTemplateByID", mock.Anything, shiftTemplateID).Return([]models.ShiftTemplate{testShiftTemplate}, nil)
+	shiftStore := new(mocks.MockIShiftStore)
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+	shiftStore.On("CreateNewShift", mock.Anything, mock.Anything).Return(nil)
+	dayStore := new(mocks.MockIDayStore)
+	dayStore.On("FindDaySchedule", mock.Anything, mock.Anything).Return([]models.DaySchedule{}, errors.New("db error"))
+	transactor := &mocks.MockITransactor{Stores: store.TxStores{Shifts: shiftStore, Days: dayStore}}
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, shiftStore, transactor,
+		test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodPost,
+		fmt.Sprintf("/shift-templates/%s/generate?from=2025-04-14&to=2025-04-14", shiftTemplateID), nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestShiftTemplateController_RestoreShiftTemplate__success(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	shiftTemplateStore := new(mocks.MockIShiftTemplateStore)
+	controller, err := controllers.NewShiftTemplateController(shiftTemplateStore, &mocks.MockIShiftStore{}, &mocks.MockITransactor{}, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+
+	shiftTemplateStore.On("RestoreShiftTemplate", mock.Anything, shiftTemplateID).Return(nil)
+	shiftTemplateStore.On("FindShiftTemplateByID", mock.Anything, shiftTemplateID).Return([]models.ShiftTemplate{testShiftTemplate}, nil)
+
+	req := httptest.NewRequest(fiber.MethodPost, fmt.Sprintf("/shift-templates/%s/restore", shiftTemplateID), nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	shiftTemplateStore.AssertExpectations(t)
+}
+
+func TestShiftTemplateController_GenerateShifts__deleted_shift_leaves_conflicts_and_load(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	shiftTemplateStore, err := store.NewShiftTemplateStore()
+	require.NoError(t, err)
+	require.NoError(t, shiftTemplateStore.CreateNewShiftTemplate(ctx, testShiftTemplate))
+	soldierStore, err := store.NewSoldierStore()
+	require.NoError(t, err)
+	require.NoError(t, soldierStore.CreateNewSoldier(ctx, testCommander))
+	inMemShiftStore, err := store.NewShiftStore()
+	require.NoError(t, err)
+	inMemDayStore, err := store.NewInMemDaySchedStore()
+	require.NoError(t, err)
+	dayStore, err := store.NewResolvingDaySchedStore(inMemDayStore, inMemShiftStore, soldierStore)
+	require.NoError(t, err)
+	shiftStore, err := store.NewResolvingShiftStore(inMemShiftStore, soldierStore)
+	require.NoError(t, err)
+	transactor := &mocks.MockITransactor{Stores: store.TxStores{Shifts: shiftStore, Days: dayStore}}
+	app := fiber.New()
+	templateController, err := controllers.NewShiftTemplateController(shiftTemplateStore, shiftStore, transactor,
+		test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	require.NoError(t, templateController.RegisterRoutes(app))
+	conflictController, err := controllers.NewConflictController(shiftStore, dayStore, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	require.NoError(t, conflictController.RegisterRoutes(app))
+	loadController, err := controllers.NewLoadController(shiftStore, dayStore, soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	require.NoError(t, loadController.RegisterRoutes(app))
+
+	// 2025-04-21 is a Monday
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost,
+		fmt.Sprintf("/shift-templates/%s/generate?from=2025-04-21&to=2025-04-21", shiftTemplateID), nil), test_utils.TestTimeout)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	var generatedShifts []models.Shift
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&generatedShifts))
+	require.Len(t, generatedShifts, 1)
+	staffedShift := generatedShifts[0]
+	staffedShift.Commander = models.Soldier{ID: testCommander.ID}
+	staffedShift.Version = 1
+	require.NoError(t, shiftStore.UpdateShift(ctx, staffedShift))
+	require.NoError(t, shiftStore.CreateNewShift(ctx, models.Shift{
+		ID:        "standalone",
+		Name:      testShiftName,
+		StartTime: staffedShift.StartTime.Add(30 * time.Minute),
+		EndTime:   staffedShift.EndTime.Add(30 * time.Minute),
+		Commander: models.Soldier{ID: testCommander.ID},
+	}))
+
+	// Act
+	err = shiftStore.DeleteShift(ctx, staffedShift.ID, 2)
+
+	// Assert
+	require.NoError(t, err)
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/conflicts?from=2025-04-21&to=2025-04-21", nil),
+		test_utils.TestTimeout)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var overlaps []scheduling.Overlap
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&overlaps))
+	assert.Empty(t, overlaps)
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/reports/load?from=2025-04-21&to=2025-04-21", nil),
+		test_utils.TestTimeout)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var loads []scheduling.SoldierLoad
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&loads))
+	require.Len(t, loads, 1)
+	assert.Equal(t, 1, loads[0].Shifts)
+	daySchedules, err := dayStore.FindDaySchedule(ctx, time.Date(2025, time.April, 21, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	require.Len(t, daySchedules, 1)
+	assert.Empty(t, daySchedules[0].Shifts)
+}
@@ -1,37 +1,55 @@
 package controllers
 
 import (
+	"brothers_in_batash/internal/app/webserver/api"
 	"brothers_in_batash/internal/pkg/logging"
 	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
 	"brothers_in_batash/internal/pkg/store"
+	"brothers_in_batash/internal/pkg/utils"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // TODO - Implement a concrete type for API requests and responses bodies(currently using the actual models)
 
+var errSoldierStaffed = errors.New("soldier is staffed in upcoming shifts")
+
 type SoldierController struct {
 	soldierStore   store.ISoldierStore
+	transactor     store.ITransactor
+	deletePolicy   scheduling.SoldierDeletePolicy
 	authMiddleware fiber.Handler
 }
 
-func NewSoldierController(soldierStore store.ISoldierStore, authMiddleware fiber.Handler) (*SoldierController, error) {
+func NewSoldierController(soldierStore store.ISoldierStore, transactor store.ITransactor,
+	deletePolicy scheduling.SoldierDeletePolicy, authMiddleware fiber.Handler) (*SoldierController, error) {
 	if soldierStore == nil {
 		return nil, errors.New("soldierStore is nil")
 	}
+	if transactor == nil {
+		return nil, errors.New("transactor is nil")
+	}
+	if !deletePolicy.IsValid() {
+		return nil, fmt.Errorf("invalid soldier delete policy %q", deletePolicy)
+	}
 	if authMiddleware == nil {
 		return nil, errors.New("authMiddleware is nil")
 	}
-	return &SoldierController{soldierStore: soldierStore, authMiddleware: authMiddleware}, nil
+	return &SoldierController{soldierStore: soldierStore, transactor: transactor, deletePolicy: deletePolicy,
+		authMiddleware: authMiddleware}, nil
 }
 
 func (c *SoldierController) RegisterRoutes(router fiber.Router) error {
-	router.Post(CreateSoldierRoute, c.createSoldier)
-	router.Get(GetSoldierRoute, c.getSoldier)
-	router.Get(GetAllSoldiersRoute, c.getAllSoldiers)
-	router.Put(UpdateSoldierRoute, c.updateSoldier)
-	router.Delete(DeleteSoldierRoute, c.deleteSoldier)
+	router.Post(CreateSoldierRoute, c.authMiddleware, requirePlatoonAdmin, c.createSoldier)
+	router.Get(GetSoldierRoute, c.authMiddleware, requireViewer, c.getSoldier)
+	router.Get(GetAllSoldiersRoute, c.authMiddleware, requireViewer, c.getAllSoldiers)
+	router.Put(UpdateSoldierRoute, c.authMiddleware, requirePlatoonAdmin, c.updateSoldier)
+	router.Delete(DeleteSoldierRoute, c.authMiddleware, requirePlatoonAdmin, c.deleteSoldier)
+	router.Post(RestoreSoldierRoute, c.authMiddleware, requirePlatoonAdmin, c.restoreSoldier)
 	return nil
 }
 
@@ -41,17 +59,27 @@ func (c *SoldierController) createSoldier(ctx *fiber.Ctx) error {
 		logging.Debug("Could not parse soldier creation request body", []logging.LogProp{{"error", err.Error()}})
 		return ctx.SendStatus(fiber.StatusBadRequest)
 	}
+	soldier.ID = utils.NewEntityID()
 
-	if err := c.soldierStore.CreateNewSoldier(soldier); err != nil {
+	if err := c.soldierStore.CreateNewSoldier(ctx.UserContext(), soldier); err != nil {
 		logging.Warning(err, "error on creating new soldier", nil)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
-	return ctx.SendStatus(fiber.StatusCreated)
+	soldiers, err := c.soldierStore.FindSoldierByID(ctx.UserContext(), soldier.ID)
+	if err != nil {
+		logging.Warning(err, "could not query for created soldier", []logging.LogProp{{"soldierID", soldier.ID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if len(soldiers) == 0 {
+		logging.Trace("created soldier was deleted already", []logging.LogProp{{"soldierID", soldier.ID}})
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	setETag(ctx, soldiers[0].Version)
+	return sendCreated(ctx, soldier.ID, soldiers[0])
 }
 
 func (c *SoldierController) getSoldier(ctx *fiber.Ctx) error {
 	soldierID := ctx.Params("id")
-	soldiers, err := c.soldierStore.FindSoldierByID(soldierID)
+	soldiers, err := c.soldierStore.FindSoldierByID(ctx.UserContext(), soldierID)
 	if err != nil {
 		logging.Warning(err, "could not query for soldier", []logging.LogProp{{"soldierID", soldierID}})
 		return ctx.SendStatus(fiber.StatusInternalServerError)
@@ -59,11 +87,12 @@ func (c *SoldierController) getSoldier(ctx *fiber.Ctx) error {
 		logging.Trace("Soldier not found", []logging.LogProp{{"soldierID", soldierID}})
 		return ctx.SendStatus(fiber.StatusNotFound)
 	}
+	setETag(ctx, soldiers[0].Version)
 	return ctx.JSON(soldiers[0])
 }
 
 func (c *SoldierController) getAllSoldiers(ctx *fiber.Ctx) error {
-	soldiers, err := c.soldierStore.FindAllSoldiers()
+	soldiers, err := c.soldierStore.FindAllSoldiers(ctx.UserContext())
 	if err != nil {
 		logging.Warning(err, "error on fetching all soldiers", nil)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
@@ -73,6 +102,10 @@ func (c *SoldierController) getAllSoldiers(ctx *fiber.Ctx) error {
 
 func (c *SoldierController) updateSoldier(ctx *fiber.Ctx) error {
 	soldierID := ctx.Params("id")
+	version, status, ok := ifMatchVersion(ctx)
+	if !ok {
+		return ctx.SendStatus(status)
+	}
 	soldier := models.Soldier{}
 	if err := ctx.BodyParser(&soldier); err != nil {
 		logging.Info("Could not parse soldier update request body", []logging.LogProp{{"error", err.Error()}})
@@ -80,18 +113,106 @@ func (c *SoldierController) updateSoldier(ctx *fiber.Ctx) error {
 	}
 
 	soldier.ID = soldierID
-	if err := c.soldierStore.UpdateSoldier(soldier); err != nil {
+	soldier.Version = version
+	if err := c.soldierStore.UpdateSoldier(ctx.UserContext(), soldier); errors.Is(err, store.ErrVersionConflict) {
+		logging.Debug("soldier was modified since it was read", []logging.LogProp{{"soldierID", soldierID}, {"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusPreconditionFailed)
+	} else if err != nil {
 		logging.Warning(err, "error on updating soldier", []logging.LogProp{{"soldierID", soldierID}})
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
+	setETag(ctx, version+1)
 	return ctx.SendStatus(fiber.StatusOK)
 }
 
 func (c *SoldierController) deleteSoldier(ctx *fiber.Ctx) error {
 	soldierID := ctx.Params("id")
-	if err := c.soldierStore.DeleteSoldier(soldierID); err != nil {
+	version, status, ok := ifMatchVersion(ctx)
+	if !ok {
+		return ctx.SendStatus(status)
+	}
+
+	// The soldier is unassigned from their upcoming shifts, whether they are stored on their own or as part of a day
+	// schedule, along with being deleted - either all of it happens or none
+	var upcomingShifts []models.Shift
+	err := c.transactor.WithinTx(ctx.UserContext(), func(stores store.TxStores) error {
+		shifts, err := stores.Shifts.FindAllShifts(ctx.UserContext())
+		if err != nil {
+			return fmt.Errorf("could not fetch shifts: %w", err)
+		}
+		daySchedules, err := stores.Days.FindAllDaySchedules(ctx.UserContext())
+		if err != nil {
+			return fmt.Errorf("could not fetch day schedules: %w", err)
+		}
+		now := time.Now()
+		standaloneShifts := scheduling.UpcomingShifts(shifts, soldierID, now)
+		staffedDaySchedules := make([]models.DaySchedule, 0)
+		upcomingShifts = standaloneShifts
+		for _, daySchedule := range daySchedules {
+			upcomingShifts = mergeShifts(upcomingShifts, scheduling.UpcomingShifts(daySchedule.Shifts, soldierID, now))
+			// The shifts the day schedule references are standalone shifts, so they are unassigned through the shift store
+			if len(scheduling.UpcomingShifts(daySchedule.WithShiftRefs().Shifts, soldierID, now)) > 0 {
+				staffedDaySchedules = append(staffedDaySchedules, daySchedule)
+			}
+		}
+		upcomingShifts = scheduling.UpcomingShifts(upcomingShifts, soldierID, now)
+		if len(upcomingShifts) > 0 && c.deletePolicy == scheduling.BlockSoldierDeletePolicy {
+			return errSoldierStaffed
+		}
+		for _, shift := range standaloneShifts {
+			if err := stores.Shifts.UpdateShift(ctx.UserContext(), shift.WithoutSoldier(soldierID)); err != nil {
+				return fmt.Errorf("could not unassign soldier from shift %s: %w", shift.ID, err)
+			}
+		}
+		for _, daySchedule := range staffedDaySchedules {
+			if err := stores.Days.UpdateDaySchedule(ctx.UserContext(), daySchedule.WithoutSoldier(soldierID, now)); err != nil {
+				return fmt.Errorf("could not unassign soldier from day schedule %s: %w", daySchedule.Date.Format("2006-01-02"), err)
+			}
+		}
+		return stores.Soldiers.DeleteSoldier(ctx.UserContext(), soldierID, version)
+	})
+	if errors.Is(err, errSoldierStaffed) {
+		logging.Debug("soldier to delete is staffed in upcoming shifts", []logging.LogProp{{"soldierID", soldierID}})
+		return ctx.Status(fiber.StatusConflict).JSON(soldierShiftsRespBody(upcomingShifts))
+	} else if errors.Is(err, store.ErrVersionConflict) {
+		logging.Debug("soldier was modified since it was read", []logging.LogProp{{"soldierID", soldierID}, {"error", err.Error()}})
+		return ctx.SendStatus(fiber.StatusPreconditionFailed)
+	} else if err != nil {
 		logging.Warning(err, "error on deleting soldier", []logging.LogProp{{"soldierID", soldierID}})
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
+	if len(upcomingShifts) > 0 {
+		return ctx.Status(fiber.StatusOK).JSON(soldierShiftsRespBody(upcomingShifts))
+	}
 	return ctx.SendStatus(fiber.StatusOK)
 }
+
+func soldierShiftsRespBody(shifts []models.Shift) api.SoldierShiftsRespBody {
+	body := api.SoldierShiftsRespBody{ShiftIDs: make([]string, 0, len(shifts))}
+	for _, shift := range shifts {
+		body.ShiftIDs = append(body.ShiftIDs, shift.ID)
+	}
+	return body
+}
+
+// restoreSoldier undoes the deletion of a soldier that was not purged yet, and returns the restored soldier
+func (c *SoldierController) restoreSoldier(ctx *fiber.Ctx) error {
+	soldierID := ctx.Params("id")
+	if err := c.soldierStore.RestoreSoldier(ctx.UserContext(), soldierID); errors.Is(err, store.ErrNotDeleted) {
+		logging.Trace("no deleted soldier to restore", []logging.LogProp{{"soldierID", soldierID}})
+		return ctx.SendStatus(fiber.StatusNotFound)
+	} else if err != nil {
+		logging.Warning(err, "error on restoring soldier", []logging.LogProp{{"soldierID", soldierID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	soldiers, err := c.soldierStore.FindSoldierByID(ctx.UserContext(), soldierID)
+	if err != nil {
+		logging.Warning(err, "could not query for restored soldier", []logging.LogProp{{"soldierID", soldierID}})
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	} else if len(soldiers) == 0 {
+		logging.Trace("restored soldier was deleted again", []logging.LogProp{{"soldierID", soldierID}})
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	setETag(ctx, soldiers[0].Version)
+	return ctx.JSON(soldiers[0])
+}
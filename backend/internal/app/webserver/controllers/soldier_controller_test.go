@@ -1,13 +1,18 @@
 package controllers_test
 
 import (
+	"brothers_in_batash/internal/app/webserver/api"
 	"brothers_in_batash/internal/app/webserver/controllers"
 	"brothers_in_batash/internal/pkg/mocks"
 	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"brothers_in_batash/internal/pkg/store"
 	"brothers_in_batash/internal/pkg/test_utils"
 	"encoding/json"
+	"fmt"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
@@ -15,9 +20,17 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// newSoldierTransactor runs units of work against soldierStore, and against a shift store holding shifts
+func newSoldierTransactor(soldierStore *mocks.MockISoldierStore, shifts ...models.Shift) *mocks.MockITransactor {
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return(shifts, nil)
+	return &mocks.MockITransactor{Stores: store.TxStores{Soldiers: soldierStore, Shifts: shiftStore, Days: newEmptyDayStore()}}
+}
+
 func TestSoldierController_NewSoldierController__error_on_nil_store(t *testing.T) {
 	// Act
-	controller, err := controllers.NewSoldierController(nil, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewSoldierController(nil, &mocks.MockITransactor{}, scheduling.BlockSoldierDeletePolicy,
+		test_utils.AlwaysAllowedJWTMiddleware)
 
 	// Assert
 	assert.Error(t, err)
@@ -26,7 +39,17 @@ func TestSoldierController_NewSoldierController__error_on_nil_store(t *testing.T
 
 func TestSoldierController_NewSoldierController__error_on_nil_auth_middleware(t *testing.T) {
 	// Act
-	controller, err := controllers.NewSoldierController(&mocks.MockISoldierStore{}, nil)
+	controller, err := controllers.NewSoldierController(&mocks.MockISoldierStore{}, &mocks.MockITransactor{}, scheduling.BlockSoldierDeletePolicy, nil)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, controller)
+}
+
+func TestSoldierController_NewSoldierController__error_on_invalid_delete_policy(t *testing.T) {
+	// Act
+	controller, err := controllers.NewSoldierController(&mocks.MockISoldierStore{}, &mocks.MockITransactor{}, "ignore",
+		test_utils.AlwaysAllowedJWTMiddleware)
 
 	// Assert
 	assert.Error(t, err)
@@ -38,7 +61,8 @@ func TestSoldierController_NewSoldierController__success(t *testing.T) {
 	soldierStore := &mocks.MockISoldierStore{}
 
 	// Act
-	controller, err := controllers.NewSoldierController(soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewSoldierController(soldierStore, newSoldierTransactor(soldierStore),
+		scheduling.BlockSoldierDeletePolicy, test_utils.AlwaysAllowedJWTMiddleware)
 
 	// Assert
 	assert.NoError(t, err)
@@ -49,7 +73,8 @@ func TestSoldierController_CreateSoldier__invalid_request_body(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	soldierStore := &mocks.MockISoldierStore{}
-	controller, err := controllers.NewSoldierController(soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewSoldierController(soldierStore, newSoldierTransactor(soldierStore),
+		scheduling.BlockSoldierDeletePolicy, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -69,12 +94,22 @@ func TestSoldierController_CreateSoldier__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	soldierStore := &mocks.MockISoldierStore{}
-	controller, err := controllers.NewSoldierController(soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewSoldierController(soldierStore, newSoldierTransactor(soldierStore),
+		scheduling.BlockSoldierDeletePolicy, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 	soldier := models.Soldier{ID: "1", FirstName: "John", LastName: "Doe"}
-	soldierStore.On("CreateNewSoldier", soldier).Return(nil)
+	storedSoldiers := []models.Soldier{soldier}
+	storedSoldiers[0].Version = 1
+	soldierStore.On("CreateNewSoldier", mock.Anything, mock.MatchedBy(func(arg models.Soldier) bool {
+		return arg.ID != "" && arg.ID != soldier.ID && arg.FirstName == soldier.FirstName
+	})).Run(func(args mock.Arguments) {
+		storedSoldiers[0].ID = args.Get(1).(models.Soldier).ID
+	}).Return(nil)
+	soldierStore.On("FindSoldierByID", mock.Anything, mock.MatchedBy(func(id string) bool {
+		return id == storedSoldiers[0].ID
+	})).Return(storedSoldiers, nil)
 	req := httptest.NewRequest(fiber.MethodPost, controllers.CreateSoldierRoute, test_utils.WrapStructWithReader(t, soldier))
 	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
 
@@ -84,6 +119,12 @@ func TestSoldierController_CreateSoldier__success(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Equal(t, controllers.CreateSoldierRoute+"/"+storedSoldiers[0].ID, resp.Header.Get(fiber.HeaderLocation))
+	assert.Equal(t, `"1"`, resp.Header.Get(fiber.HeaderETag))
+	var createdSoldier models.Soldier
+	err = json.NewDecoder(resp.Body).Decode(&createdSoldier)
+	require.NoError(t, err)
+	assert.Equal(t, storedSoldiers[0], createdSoldier)
 	soldierStore.AssertExpectations(t)
 }
 
@@ -91,12 +132,13 @@ func TestSoldierController_GetSoldier__not_found(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	soldierStore := &mocks.MockISoldierStore{}
-	controller, err := controllers.NewSoldierController(soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewSoldierController(soldierStore, newSoldierTransactor(soldierStore),
+		scheduling.BlockSoldierDeletePolicy, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 	soldierID := "1"
-	soldierStore.On("FindSoldierByID", soldierID).Return([]models.Soldier{}, nil)
+	soldierStore.On("FindSoldierByID", mock.Anything, soldierID).Return([]models.Soldier{}, nil)
 	req := httptest.NewRequest(fiber.MethodGet, "/soldiers/"+soldierID, nil)
 
 	// Act
@@ -112,13 +154,14 @@ func TestSoldierController_GetSoldier__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	soldierStore := &mocks.MockISoldierStore{}
-	controller, err := controllers.NewSoldierController(soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewSoldierController(soldierStore, newSoldierTransactor(soldierStore),
+		scheduling.BlockSoldierDeletePolicy, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 	soldierID := "1"
 	soldier := models.Soldier{ID: soldierID, FirstName: "John", LastName: "Doe"}
-	soldierStore.On("FindSoldierByID", soldierID).Return([]models.Soldier{soldier}, nil)
+	soldierStore.On("FindSoldierByID", mock.Anything, soldierID).Return([]models.Soldier{soldier}, nil)
 	req := httptest.NewRequest(fiber.MethodGet, "/soldiers/"+soldierID, nil)
 
 	// Act
@@ -138,7 +181,8 @@ func TestSoldierController_GetAllSoldiers__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	soldierStore := &mocks.MockISoldierStore{}
-	controller, err := controllers.NewSoldierController(soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewSoldierController(soldierStore, newSoldierTransactor(soldierStore),
+		scheduling.BlockSoldierDeletePolicy, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
@@ -146,7 +190,7 @@ func TestSoldierController_GetAllSoldiers__success(t *testing.T) {
 		{ID: "1", FirstName: "John", LastName: "Doe"},
 		{ID: "2", FirstName: "Jane", LastName: "Smith"},
 	}
-	soldierStore.On("FindAllSoldiers").Return(soldiers, nil)
+	soldierStore.On("FindAllSoldiers", mock.Anything).Return(soldiers, nil)
 	req := httptest.NewRequest(fiber.MethodGet, controllers.GetAllSoldiersRoute, nil)
 
 	// Act
@@ -166,12 +210,14 @@ func TestSoldierController_UpdateSoldier__invalid_request_body(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	soldierStore := &mocks.MockISoldierStore{}
-	controller, err := controllers.NewSoldierController(soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewSoldierController(soldierStore, newSoldierTransactor(soldierStore),
+		scheduling.BlockSoldierDeletePolicy, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 	soldierID := "1"
 	req := httptest.NewRequest(fiber.MethodPut, "/soldiers/"+soldierID, test_utils.WrapStructWithReader(t, "invalid"))
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
 	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
 
 	// Act
@@ -187,14 +233,16 @@ func TestSoldierController_UpdateSoldier__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	soldierStore := &mocks.MockISoldierStore{}
-	controller, err := controllers.NewSoldierController(soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewSoldierController(soldierStore, newSoldierTransactor(soldierStore),
+		scheduling.BlockSoldierDeletePolicy, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 	soldierID := "1"
 	soldier := models.Soldier{ID: soldierID, FirstName: "John", LastName: "Doe"}
-	soldierStore.On("UpdateSoldier", mock.AnythingOfType("models.Soldier")).Return(nil)
+	soldierStore.On("UpdateSoldier", mock.Anything, mock.AnythingOfType("models.Soldier")).Return(nil)
 	req := httptest.NewRequest(fiber.MethodPut, "/soldiers/"+soldierID, test_utils.WrapStructWithReader(t, soldier))
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
 	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
 
 	// Act
@@ -210,13 +258,214 @@ func TestSoldierController_DeleteSoldier__success(t *testing.T) {
 	// Arrange
 	app := fiber.New()
 	soldierStore := &mocks.MockISoldierStore{}
-	controller, err := controllers.NewSoldierController(soldierStore, test_utils.AlwaysAllowedJWTMiddleware)
+	controller, err := controllers.NewSoldierController(soldierStore, newSoldierTransactor(soldierStore),
+		scheduling.BlockSoldierDeletePolicy, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	soldierID := "1"
+	soldierStore.On("DeleteSoldier", mock.Anything, soldierID, 1).Return(nil)
+	req := httptest.NewRequest(fiber.MethodDelete, "/soldiers/"+soldierID, nil)
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	soldierStore.AssertExpectations(t)
+}
+
+func TestSoldierController_DeleteSoldier__missing_if_match(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	soldierStore := &mocks.MockISoldierStore{}
+	controller, err := controllers.NewSoldierController(soldierStore, newSoldierTransactor(soldierStore),
+		scheduling.BlockSoldierDeletePolicy, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodDelete, "/soldiers/1", nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusPreconditionRequired, resp.StatusCode)
+	soldierStore.AssertNotCalled(t, "DeleteSoldier", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSoldierController_DeleteSoldier__stale_version(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	soldierStore := &mocks.MockISoldierStore{}
+	controller, err := controllers.NewSoldierController(soldierStore, newSoldierTransactor(soldierStore),
+		scheduling.BlockSoldierDeletePolicy, test_utils.AlwaysAllowedJWTMiddleware)
 	require.NoError(t, err)
 	err = controller.RegisterRoutes(app)
 	require.NoError(t, err)
 	soldierID := "1"
-	soldierStore.On("DeleteSoldier", soldierID).Return(nil)
+	soldierStore.On("DeleteSoldier", mock.Anything, soldierID, 1).Return(fmt.Errorf("soldier 1 is at version 2: %w", store.ErrVersionConflict))
 	req := httptest.NewRequest(fiber.MethodDelete, "/soldiers/"+soldierID, nil)
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusPreconditionFailed, resp.StatusCode)
+	soldierStore.AssertExpectations(t)
+}
+
+func TestSoldierController_RestoreSoldier__not_deleted(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	soldierStore := &mocks.MockISoldierStore{}
+	controller, err := controllers.NewSoldierController(soldierStore, newSoldierTransactor(soldierStore),
+		scheduling.BlockSoldierDeletePolicy, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	soldierID := "1"
+	soldierStore.On("RestoreSoldier", mock.Anything, soldierID).Return(fmt.Errorf("soldier %s: %w", soldierID, store.ErrNotDeleted))
+	req := httptest.NewRequest(fiber.MethodPost, "/soldiers/"+soldierID+"/restore", nil)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	soldierStore.AssertExpectations(t)
+}
+
+func TestSoldierController_DeleteSoldier__blocked_by_upcoming_shifts(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	soldierStore := &mocks.MockISoldierStore{}
+	pastShift := models.Shift{ID: "past", StartTime: time.Now().Add(-48 * time.Hour), Commander: models.Soldier{ID: "1"}}
+	upcomingShift := models.Shift{ID: "upcoming", StartTime: time.Now().Add(48 * time.Hour), Commander: models.Soldier{ID: "1"}}
+	controller, err := controllers.NewSoldierController(soldierStore, newSoldierTransactor(soldierStore, pastShift, upcomingShift),
+		scheduling.BlockSoldierDeletePolicy, test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodDelete, "/soldiers/1", nil)
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	var respBody api.SoldierShiftsRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"upcoming"}, respBody.ShiftIDs)
+	soldierStore.AssertNotCalled(t, "DeleteSoldier", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSoldierController_DeleteSoldier__cascades_to_upcoming_shifts(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	soldierStore := &mocks.MockISoldierStore{}
+	soldierStore.On("DeleteSoldier", mock.Anything, "1", 1).Return(nil)
+	upcomingShift := models.Shift{
+		ID:                 "upcoming",
+		StartTime:          time.Now().Add(48 * time.Hour),
+		Commander:          models.Soldier{ID: "2"},
+		AdditionalSoldiers: []models.Soldier{{ID: "1"}, {ID: "3"}},
+	}
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{upcomingShift}, nil)
+	shiftStore.On("UpdateShift", mock.Anything, upcomingShift.WithoutSoldier("1")).Return(nil)
+	transactor := &mocks.MockITransactor{Stores: store.TxStores{Soldiers: soldierStore, Shifts: shiftStore, Days: newEmptyDayStore()}}
+	controller, err := controllers.NewSoldierController(soldierStore, transactor, scheduling.CascadeSoldierDeletePolicy,
+		test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodDelete, "/soldiers/1", nil)
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var respBody api.SoldierShiftsRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"upcoming"}, respBody.ShiftIDs)
+	shiftStore.AssertExpectations(t)
+	soldierStore.AssertExpectations(t)
+}
+
+func TestSoldierController_DeleteSoldier__blocked_by_day_schedule_shifts(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	soldierStore := &mocks.MockISoldierStore{}
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+	upcomingShift := models.Shift{ID: "upcoming", StartTime: time.Now().Add(48 * time.Hour), Commander: models.Soldier{ID: "1"}}
+	dayStore := &mocks.MockIDayStore{}
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{{Date: upcomingShift.StartTime,
+		Shifts: []models.Shift{upcomingShift}}}, nil)
+	transactor := &mocks.MockITransactor{Stores: store.TxStores{Soldiers: soldierStore, Shifts: shiftStore, Days: dayStore}}
+	controller, err := controllers.NewSoldierController(soldierStore, transactor, scheduling.BlockSoldierDeletePolicy,
+		test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodDelete, "/soldiers/1", nil)
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	var respBody api.SoldierShiftsRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"upcoming"}, respBody.ShiftIDs)
+	soldierStore.AssertNotCalled(t, "DeleteSoldier", mock.Anything, mock.Anything, mock.Anything)
+	dayStore.AssertNotCalled(t, "UpdateDaySchedule", mock.Anything, mock.Anything)
+}
+
+func TestSoldierController_DeleteSoldier__cascades_to_day_schedule_shifts(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	soldierStore := &mocks.MockISoldierStore{}
+	soldierStore.On("DeleteSoldier", mock.Anything, "1", 1).Return(nil)
+	shiftStore := &mocks.MockIShiftStore{}
+	shiftStore.On("FindAllShifts", mock.Anything).Return([]models.Shift{}, nil)
+	pastShift := models.Shift{ID: "past", StartTime: time.Now().Add(-time.Hour), Commander: models.Soldier{ID: "1"}}
+	upcomingShift := models.Shift{
+		ID:                 "upcoming",
+		StartTime:          time.Now().Add(time.Hour),
+		Commander:          models.Soldier{ID: "2"},
+		AdditionalSoldiers: []models.Soldier{{ID: "1"}, {ID: "3"}},
+	}
+	daySchedule := models.DaySchedule{Date: time.Now(), Shifts: []models.Shift{pastShift, upcomingShift}, Version: 1}
+	dayStore := &mocks.MockIDayStore{}
+	dayStore.On("FindAllDaySchedules", mock.Anything).Return([]models.DaySchedule{daySchedule}, nil)
+	dayStore.On("UpdateDaySchedule", mock.Anything, models.DaySchedule{Date: daySchedule.Date,
+		Shifts: []models.Shift{pastShift, upcomingShift.WithoutSoldier("1")}, Version: 1}).Return(nil)
+	transactor := &mocks.MockITransactor{Stores: store.TxStores{Soldiers: soldierStore, Shifts: shiftStore, Days: dayStore}}
+	controller, err := controllers.NewSoldierController(soldierStore, transactor, scheduling.CascadeSoldierDeletePolicy,
+		test_utils.AlwaysAllowedJWTMiddleware)
+	require.NoError(t, err)
+	err = controller.RegisterRoutes(app)
+	require.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodDelete, "/soldiers/1", nil)
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
 
 	// Act
 	resp, err := app.Test(req, test_utils.TestTimeout)
@@ -224,5 +473,10 @@ func TestSoldierController_DeleteSoldier__success(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var respBody api.SoldierShiftsRespBody
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"upcoming"}, respBody.ShiftIDs)
+	dayStore.AssertExpectations(t)
 	soldierStore.AssertExpectations(t)
 }
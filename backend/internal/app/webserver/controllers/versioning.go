@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"brothers_in_batash/internal/pkg/logging"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setETag exposes the version of the returned entity. Clients send it back in the If-Match header when they update or
+// delete the entity.
+func setETag(ctx *fiber.Ctx, version int) {
+	ctx.Set(fiber.HeaderETag, strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatchVersion parses the entity version the client has last read out of the If-Match header. If it is missing or
+// malformed, the returned status is the one to respond with.
+func ifMatchVersion(ctx *fiber.Ctx) (version int, status int, ok bool) {
+	ifMatch := ctx.Get(fiber.HeaderIfMatch)
+	if ifMatch == "" {
+		logging.Debug("Missing If-Match header", []logging.LogProp{{"path", ctx.Path()}})
+		return 0, fiber.StatusPreconditionRequired, false
+	}
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`))
+	if err != nil {
+		logging.Debug("Invalid If-Match header", []logging.LogProp{{"ifMatch", ifMatch}})
+		return 0, fiber.StatusBadRequest, false
+	}
+	return version, fiber.StatusOK, true
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DevelopmentMode = "development"
+	// ProductionMode refuses to start with the default JWT secret
+	ProductionMode = "production"
+)
+
+// DefaultJWTSecret is only good for development - every deployment that keeps it can forge the tokens of the others
+const DefaultJWTSecret = "secret"
+
+// MinProductionJWTSecretLength is the least number of bytes a production JWT secret is made of
+const MinProductionJWTSecretLength = 32
+
+// ConfigFileEnvVar names the environment variable holding the path of the optional YAML config file
+const ConfigFileEnvVar = "BIB_CONFIG_FILE"
+
+// Config is the configuration that differs between deployments. It is loaded once on startup - from the defaults,
+// overridden by the YAML config file if there is one, overridden by environment variables.
+type Config struct {
+	Mode          string `yaml:"mode" validate:"oneof=development production"`
+	ListenAddress string `yaml:"listenAddress" validate:"hostname_port"`
+	LogLevel      string `yaml:"logLevel" validate:"oneof=trace debug info warn error"`
+
+	JWTSecret            string        `yaml:"jwtSecret" validate:"required"`
+	AccessTokenLifetime  time.Duration `yaml:"accessTokenLifetime" validate:"gt=0"`
+	RefreshTokenLifetime time.Duration `yaml:"refreshTokenLifetime" validate:"gtfield=AccessTokenLifetime"`
+
+	// MinRestAfterShift is the minimum time off a soldier gets between any two shifts
+	MinRestAfterShift time.Duration `yaml:"minRestAfterShift" validate:"gt=0"`
+	// MinRestAfterNightShift is the minimum time off a soldier gets after a shift that takes place during the night
+	MinRestAfterNightShift time.Duration `yaml:"minRestAfterNightShift" validate:"gt=0"`
+
+	// StaffingMode decides what happens to shifts that do not cover their template's personnel requirement - "strict"
+	// rejects them, "warn" saves them flagged as understaffed
+	StaffingMode string `yaml:"staffingMode" validate:"oneof=warn strict"`
+	// SoldierDeletePolicy decides what happens when deleting a soldier who is staffed in upcoming shifts - "block"
+	// refuses to delete them, "cascade" unassigns them from these shifts
+	SoldierDeletePolicy string `yaml:"soldierDeletePolicy" validate:"oneof=block cascade"`
+
+	// HourLoadWeight is how much every hour of duty adds to a soldier's load score
+	HourLoadWeight float64 `yaml:"hourLoadWeight" validate:"gte=0"`
+	// NightHourLoadWeight is how much every hour of night duty adds to a soldier's load score, on top of HourLoadWeight
+	NightHourLoadWeight float64 `yaml:"nightHourLoadWeight" validate:"gte=0"`
+	// ShabbatShiftLoadWeight is how much every Shabbat shift adds to a soldier's load score
+	ShabbatShiftLoadWeight float64 `yaml:"shabbatShiftLoadWeight" validate:"gte=0"`
+	// HolidayShiftLoadWeight is how much every holiday shift adds to a soldier's load score
+	HolidayShiftLoadWeight float64 `yaml:"holidayShiftLoadWeight" validate:"gte=0"`
+	// Holidays are dates, formatted as 2006-01-02, whose shifts weigh HolidayShiftLoadWeight on top of the Jewish
+	// holidays, which are computed from the Hebrew calendar
+	Holidays []string `yaml:"holidays" validate:"dive,datetime=2006-01-02"`
+
+	// StoreBackend decides where data is kept - "sqlite" persists it to SQLitePath, "memory" loses it on restart
+	StoreBackend string `yaml:"storeBackend" validate:"oneof=memory sqlite"`
+	// SQLitePath is the path of the SQLite database file, relative to the working directory
+	SQLitePath string `yaml:"sqlitePath" validate:"required_if=StoreBackend sqlite"`
+	// SnapshotPath is the file the "memory" backend snapshots its data to, and loads it from on startup. Leave empty to
+	// disable snapshots.
+	SnapshotPath string `yaml:"snapshotPath"`
+
+	// CORSOrigins are the origins of the browser apps allowed to call the API from another origin. Leave empty when
+	// the frontend is served from the same origin as the API.
+	CORSOrigins []string `yaml:"corsOrigins" validate:"dive,http_url"`
+
+	// AdminUsername and AdminPassword are the credentials of the initial platoon admin, who is created on startup
+	// unless a user with that username already exists
+	AdminUsername string `yaml:"adminUsername"`
+	AdminPassword string `yaml:"adminPassword"`
+}
+
+// Default is the configuration of a local development environment
+func Default() Config {
+	return Config{
+		Mode:                   DevelopmentMode,
+		ListenAddress:          ":3000",
+		LogLevel:               "debug",
+		JWTSecret:              DefaultJWTSecret,
+		AccessTokenLifetime:    6 * time.Hour,
+		RefreshTokenLifetime:   7 * 24 * time.Hour,
+		MinRestAfterShift:      4 * time.Hour,
+		MinRestAfterNightShift: 8 * time.Hour,
+		StaffingMode:           "warn",
+		SoldierDeletePolicy:    "block",
+		HourLoadWeight:         1,
+		NightHourLoadWeight:    1,
+		ShabbatShiftLoadWeight: 12,
+		HolidayShiftLoadWeight: 12,
+		StoreBackend:           "sqlite",
+		SQLitePath:             "brothers_in_batash.db",
+		SnapshotPath:           "brothers_in_batash.snapshot.json",
+	}
+}
+
+// envBindings are the environment variables that override the Config fields, each parsing its value into its field
+var envBindings = []struct {
+	name string
+	set  func(c *Config, value string) error
+}{
+	{"BIB_MODE", func(c *Config, value string) error { c.Mode = value; return nil }},
+	{"BIB_LISTEN_ADDRESS", func(c *Config, value string) error { c.ListenAddress = value; return nil }},
+	{"BIB_LOG_LEVEL", func(c *Config, value string) error { c.LogLevel = value; return nil }},
+	{"BIB_JWT_SECRET", func(c *Config, value string) error { c.JWTSecret = value; return nil }},
+	{"BIB_ACCESS_TOKEN_LIFETIME", func(c *Config, value string) error { return parseDuration(value, &c.AccessTokenLifetime) }},
+	{"BIB_REFRESH_TOKEN_LIFETIME", func(c *Config, value string) error { return parseDuration(value, &c.RefreshTokenLifetime) }},
+	{"BIB_MIN_REST_AFTER_SHIFT", func(c *Config, value string) error { return parseDuration(value, &c.MinRestAfterShift) }},
+	{"BIB_MIN_REST_AFTER_NIGHT_SHIFT", func(c *Config, value string) error {
+		return parseDuration(value, &c.MinRestAfterNightShift)
+	}},
+	{"BIB_STAFFING_MODE", func(c *Config, value string) error { c.StaffingMode = value; return nil }},
+	{"BIB_SOLDIER_DELETE_POLICY", func(c *Config, value string) error { c.SoldierDeletePolicy = value; return nil }},
+	{"BIB_HOUR_LOAD_WEIGHT", func(c *Config, value string) error { return parseFloat(value, &c.HourLoadWeight) }},
+	{"BIB_NIGHT_HOUR_LOAD_WEIGHT", func(c *Config, value string) error { return parseFloat(value, &c.NightHourLoadWeight) }},
+	{"BIB_SHABBAT_SHIFT_LOAD_WEIGHT", func(c *Config, value string) error {
+		return parseFloat(value, &c.ShabbatShiftLoadWeight)
+	}},
+	{"BIB_HOLIDAY_SHIFT_LOAD_WEIGHT", func(c *Config, value string) error {
+		return parseFloat(value, &c.HolidayShiftLoadWeight)
+	}},
+	{"BIB_HOLIDAYS", func(c *Config, value string) error { c.Holidays = splitList(value); return nil }},
+	{"BIB_STORE_BACKEND", func(c *Config, value string) error { c.StoreBackend = value; return nil }},
+	{"BIB_SQLITE_PATH", func(c *Config, value string) error { c.SQLitePath = value; return nil }},
+	{"BIB_SNAPSHOT_PATH", func(c *Config, value string) error { c.SnapshotPath = value; return nil }},
+	{"BIB_CORS_ORIGINS", func(c *Config, value string) error { c.CORSOrigins = splitList(value); return nil }},
+	{"BIB_ADMIN_USERNAME", func(c *Config, value string) error { c.AdminUsername = value; return nil }},
+	{"BIB_ADMIN_PASSWORD", func(c *Config, value string) error { c.AdminPassword = value; return nil }},
+}
+
+// Load loads the configuration of the running process, from the config file ConfigFileEnvVar names and from the
+// environment
+func Load() (Config, error) {
+	return LoadFrom(os.Getenv(ConfigFileEnvVar), os.LookupEnv)
+}
+
+// LoadFrom loads the configuration from the YAML file at path, if path is not empty, and from the environment variables
+// lookupEnv finds. The configuration is validated before it is returned.
+func LoadFrom(path string, lookupEnv func(name string) (string, bool)) (Config, error) {
+	cfg := Default()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, errors.Wrap(err, "could not read config file")
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		// Misspelled keys would otherwise be silently ignored, leaving their fields at the defaults
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return Config{}, errors.Wrap(err, "could not parse config file")
+		}
+	}
+	for _, binding := range envBindings {
+		if value, ok := lookupEnv(binding.name); ok {
+			if err := binding.set(&cfg, value); err != nil {
+				return Config{}, errors.Wrapf(err, "invalid %s", binding.name)
+			}
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate checks the configuration, refusing the development JWT secret in production
+func (c Config) Validate() error {
+	if err := validator.New().Struct(c); err != nil {
+		return errors.Wrap(err, "config validation failed")
+	}
+	if c.Mode == ProductionMode {
+		if c.JWTSecret == DefaultJWTSecret {
+			return errors.New("the default JWT secret can not be used in production")
+		}
+		if len(c.JWTSecret) < MinProductionJWTSecretLength {
+			return errors.Errorf("the JWT secret must be at least %d bytes long in production", MinProductionJWTSecretLength)
+		}
+	}
+	return nil
+}
+
+// parseDuration parses value into duration, leaving duration as is if value is not a valid duration
+func parseDuration(value string, duration *time.Duration) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*duration = parsed
+	return nil
+}
+
+// parseFloat parses value into number, leaving number as is if value is not a valid number
+func parseFloat(value string, number *float64) error {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return err
+	}
+	*number = parsed
+	return nil
+}
+
+// splitList splits a comma separated list, dropping empty items
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,168 @@
+package config_test
+
+import (
+	"brothers_in_batash/internal/pkg/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const productionJWTSecret = "0123456789abcdef0123456789abcdef"
+
+func envOf(env map[string]string) func(name string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFrom__defaults(t *testing.T) {
+	// Act
+	cfg, err := config.LoadFrom("", envOf(nil))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, config.Default(), cfg)
+}
+
+func TestLoadFrom__config_file(t *testing.T) {
+	// Arrange
+	path := writeConfigFile(t, strings.Join([]string{
+		"listenAddress: 0.0.0.0:8080",
+		"accessTokenLifetime: 15m",
+		"minRestAfterNightShift: 10h",
+		"staffingMode: strict",
+		"storeBackend: memory",
+		"corsOrigins:",
+		"  - https://example.com",
+	}, "\n"))
+
+	// Act
+	cfg, err := config.LoadFrom(path, envOf(nil))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "0.0.0.0:8080", cfg.ListenAddress)
+	assert.Equal(t, 15*time.Minute, cfg.AccessTokenLifetime)
+	assert.Equal(t, 10*time.Hour, cfg.MinRestAfterNightShift)
+	assert.Equal(t, "strict", cfg.StaffingMode)
+	assert.Equal(t, "memory", cfg.StoreBackend)
+	assert.Equal(t, []string{"https://example.com"}, cfg.CORSOrigins)
+	assert.Equal(t, config.Default().RefreshTokenLifetime, cfg.RefreshTokenLifetime)
+}
+
+func TestLoadFrom__env_overrides_config_file(t *testing.T) {
+	// Arrange
+	path := writeConfigFile(t, "logLevel: warn\naccessTokenLifetime: 15m\n")
+	env := map[string]string{
+		"BIB_LOG_LEVEL":                 "error",
+		"BIB_ACCESS_TOKEN_LIFETIME":     "30m",
+		"BIB_MIN_REST_AFTER_SHIFT":      "6h",
+		"BIB_SHABBAT_SHIFT_LOAD_WEIGHT": "20.5",
+		"BIB_SOLDIER_DELETE_POLICY":     "cascade",
+		"BIB_HOLIDAYS":                  "2026-04-22,2027-05-12",
+		"BIB_CORS_ORIGINS":              "https://a.example.com, ,https://b.example.com",
+	}
+
+	// Act
+	cfg, err := config.LoadFrom(path, envOf(env))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "error", cfg.LogLevel)
+	assert.Equal(t, 30*time.Minute, cfg.AccessTokenLifetime)
+	assert.Equal(t, 6*time.Hour, cfg.MinRestAfterShift)
+	assert.Equal(t, 20.5, cfg.ShabbatShiftLoadWeight)
+	assert.Equal(t, "cascade", cfg.SoldierDeletePolicy)
+	assert.Equal(t, []string{"2026-04-22", "2027-05-12"}, cfg.Holidays)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, cfg.CORSOrigins)
+}
+
+func TestLoadFrom__production_secret(t *testing.T) {
+	testCases := map[string]struct {
+		secret  string
+		wantErr bool
+	}{
+		"default secret": {secret: config.DefaultJWTSecret, wantErr: true},
+		"short secret":   {secret: "short", wantErr: true},
+		"long secret":    {secret: productionJWTSecret, wantErr: false},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			env := map[string]string{"BIB_MODE": config.ProductionMode, "BIB_JWT_SECRET": testCase.secret}
+
+			// Act
+			cfg, err := config.LoadFrom("", envOf(env))
+
+			// Assert
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testCase.secret, cfg.JWTSecret)
+			}
+		})
+	}
+}
+
+func TestLoadFrom__sad_flows(t *testing.T) {
+	testCases := map[string]struct {
+		fileContent string
+		env         map[string]string
+	}{
+		"unknown config file key": {fileContent: "jwtSecrt: misspelled\n"},
+		"invalid config file":     {fileContent: "mode: [development\n"},
+		"invalid duration env":    {env: map[string]string{"BIB_ACCESS_TOKEN_LIFETIME": "forever"}},
+		"unknown mode":            {env: map[string]string{"BIB_MODE": "staging"}},
+		"unknown store backend":   {env: map[string]string{"BIB_STORE_BACKEND": "postgres"}},
+		"invalid listen address":  {env: map[string]string{"BIB_LISTEN_ADDRESS": "localhost"}},
+		"empty jwt secret":        {env: map[string]string{"BIB_JWT_SECRET": ""}},
+		"invalid cors origin":     {env: map[string]string{"BIB_CORS_ORIGINS": "example.com"}},
+		"unknown staffing mode":   {env: map[string]string{"BIB_STAFFING_MODE": "lenient"}},
+		"unknown delete policy":   {env: map[string]string{"BIB_SOLDIER_DELETE_POLICY": "purge"}},
+		"negative load weight":    {env: map[string]string{"BIB_NIGHT_HOUR_LOAD_WEIGHT": "-1"}},
+		"invalid load weight":     {env: map[string]string{"BIB_HOUR_LOAD_WEIGHT": "heavy"}},
+		"invalid holiday":         {env: map[string]string{"BIB_HOLIDAYS": "2026-13-01"}},
+		"zero min rest":           {env: map[string]string{"BIB_MIN_REST_AFTER_SHIFT": "0s"}},
+		"negative night min rest": {env: map[string]string{"BIB_MIN_REST_AFTER_NIGHT_SHIFT": "-1h"}},
+		"refresh shorter than access": {env: map[string]string{
+			"BIB_ACCESS_TOKEN_LIFETIME":  "2h",
+			"BIB_REFRESH_TOKEN_LIFETIME": "1h",
+		}},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			path := ""
+			if testCase.fileContent != "" {
+				path = writeConfigFile(t, testCase.fileContent)
+			}
+
+			// Act
+			_, err := config.LoadFrom(path, envOf(testCase.env))
+
+			// Assert
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadFrom__missing_config_file(t *testing.T) {
+	// Act
+	_, err := config.LoadFrom(filepath.Join(t.TempDir(), "missing.yaml"), envOf(nil))
+
+	// Assert
+	assert.Error(t, err)
+}
@@ -1,3 +1,22 @@
 package config
 
-const JWTSecret = "secret"
+import "time"
+
+// RequestTimeout is the deadline every request gets, including its reads and writes to the stores
+const RequestTimeout = 30 * time.Second
+
+// SnapshotInterval is how often the "memory" backend snapshots its data
+const SnapshotInterval = time.Minute
+
+const (
+	// DeletedRetention is how long deleted shifts, soldiers and shift templates can be restored before they are purged
+	DeletedRetention = 30 * 24 * time.Hour
+	// PurgeInterval is how often the deleted entities whose DeletedRetention has passed are purged
+	PurgeInterval = time.Hour
+)
+
+// RevokedTokenPruneInterval is how often the revoked tokens that have expired anyway are forgotten
+const RevokedTokenPruneInterval = time.Hour
+
+// ShutdownTimeout is how long in-flight requests get to complete once the webserver is asked to stop
+const ShutdownTimeout = 10 * time.Second
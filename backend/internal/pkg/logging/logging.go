@@ -66,6 +66,18 @@ func Panic(err error, msg string, props []LogProp) {
 	applyProps(logger.Panic(), props).Msg(msg)
 }
 
+// SetLevel drops the logs less severe than level - one of trace, debug, info, warn and error. It is meant to be called
+// once on startup, before anything logs concurrently.
+func SetLevel(level string) error {
+	parsedLevel, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	instance := logger.Level(parsedLevel)
+	logger = &instance
+	return nil
+}
+
 func applyProps(logEvent *zerolog.Event, props []LogProp) *zerolog.Event {
 	if props == nil {
 		return logEvent
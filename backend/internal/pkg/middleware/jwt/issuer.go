@@ -0,0 +1,78 @@
+package jwt
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/utils"
+	"time"
+
+	jtoken "github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+)
+
+// TokenIssuer signs the tokens of the webserver, and verifies them when they come back
+type TokenIssuer struct {
+	secret               []byte
+	accessTokenLifetime  time.Duration
+	refreshTokenLifetime time.Duration
+}
+
+func NewTokenIssuer(secret string, accessTokenLifetime, refreshTokenLifetime time.Duration) (*TokenIssuer, error) {
+	if secret == "" {
+		return nil, errors.New("secret is empty")
+	}
+	if accessTokenLifetime <= 0 || refreshTokenLifetime <= 0 {
+		return nil, errors.New("token lifetimes must be positive")
+	}
+	return &TokenIssuer{
+		secret:               []byte(secret),
+		accessTokenLifetime:  accessTokenLifetime,
+		refreshTokenLifetime: refreshTokenLifetime,
+	}, nil
+}
+
+// Lifetime is how long tokens of tokenType are valid for
+func (i *TokenIssuer) Lifetime(tokenType TokenType) time.Duration {
+	if tokenType == RefreshTokenType {
+		return i.refreshTokenLifetime
+	}
+	return i.accessTokenLifetime
+}
+
+// GenerateToken signs a token of tokenType that identifies user, and carries the user's role and soldier ID. Every
+// token gets a unique ID, by which it can be revoked. familyID ties together the tokens descending from the same login,
+// so that they can all be revoked at once.
+func (i *TokenIssuer) GenerateToken(user models.User, tokenType TokenType, familyID string) (string, error) {
+	if familyID == "" {
+		return "", errors.New("token family ID is empty")
+	}
+	claims := jtoken.MapClaims{
+		IDClaimField:          user.Username,
+		TokenIDClaimField:     utils.NewEntityID(),
+		TokenTypeClaimField:   string(tokenType),
+		TokenFamilyClaimField: familyID,
+		ExpiryClaimField:      time.Now().Add(i.Lifetime(tokenType)).Unix(),
+		RoleClaimField:        string(user.Role),
+		SoldierIDClaimField:   user.SoldierID,
+	}
+	token := jtoken.NewWithClaims(jtoken.SigningMethodHS256, claims)
+	signedToken, err := token.SignedString(i.secret)
+	if err != nil {
+		return "", errors.Wrap(err, "could not sign JWT token")
+	}
+	return signedToken, nil
+}
+
+// ParseToken verifies a token the issuer signed, and that has not expired yet, and returns its claims
+func (i *TokenIssuer) ParseToken(signedToken string) (jtoken.MapClaims, error) {
+	token, err := jtoken.Parse(signedToken, func(token *jtoken.Token) (interface{}, error) {
+		return i.secret, nil
+	}, jtoken.WithValidMethods([]string{jtoken.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(jtoken.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
@@ -0,0 +1,79 @@
+package jwt_test
+
+import (
+	jwtmw "brothers_in_batash/internal/pkg/middleware/jwt"
+	"brothers_in_batash/internal/pkg/models"
+	"testing"
+	"time"
+
+	jtoken "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTokenIssuer__sad_flows(t *testing.T) {
+	// Act
+	emptySecretIssuer, emptySecretErr := jwtmw.NewTokenIssuer("", time.Hour, 24*time.Hour)
+	zeroLifetimeIssuer, zeroLifetimeErr := jwtmw.NewTokenIssuer(testJWTSecret, 0, 24*time.Hour)
+
+	// Assert
+	assert.Error(t, emptySecretErr)
+	assert.Nil(t, emptySecretIssuer)
+	assert.Error(t, zeroLifetimeErr)
+	assert.Nil(t, zeroLifetimeIssuer)
+}
+
+func TestTokenIssuer_ParseToken(t *testing.T) {
+	// Arrange
+	tokenIssuer := newTestTokenIssuer(t)
+	before := time.Now()
+	refreshToken, err := tokenIssuer.GenerateToken(models.User{Username: "user", Role: models.SoldierUserRole},
+		jwtmw.RefreshTokenType, "family")
+	require.NoError(t, err)
+
+	// Act
+	claims, err := tokenIssuer.ParseToken(refreshToken)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "user", claims[jwtmw.IDClaimField])
+	assert.Equal(t, string(jwtmw.RefreshTokenType), claims[jwtmw.TokenTypeClaimField])
+	assert.Equal(t, "family", claims[jwtmw.TokenFamilyClaimField])
+	expiresAt, err := claims.GetExpirationTime()
+	require.NoError(t, err)
+	assert.WithinDuration(t, before.Add(24*time.Hour), expiresAt.Time, time.Minute)
+}
+
+func TestTokenIssuer_ParseToken__rejected_tokens(t *testing.T) {
+	tokenIssuer := newTestTokenIssuer(t)
+	otherIssuer, err := jwtmw.NewTokenIssuer("other secret", time.Hour, 24*time.Hour)
+	require.NoError(t, err)
+	otherSecretToken, err := otherIssuer.GenerateToken(models.User{Username: "user"}, jwtmw.AccessTokenType, "family")
+	require.NoError(t, err)
+	expiredToken, err := jtoken.NewWithClaims(jtoken.SigningMethodHS256, jtoken.MapClaims{
+		jwtmw.IDClaimField:     "user",
+		jwtmw.ExpiryClaimField: time.Now().Add(-time.Minute).Unix(),
+	}).SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+	otherAlgorithmToken, err := jtoken.NewWithClaims(jtoken.SigningMethodHS512, jtoken.MapClaims{
+		jwtmw.IDClaimField:     "user",
+		jwtmw.ExpiryClaimField: time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+	testCases := map[string]string{
+		"other secret":    otherSecretToken,
+		"expired":         expiredToken,
+		"other algorithm": otherAlgorithmToken,
+		"malformed":       "not a token",
+	}
+	for name, token := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			claims, err := tokenIssuer.ParseToken(token)
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, claims)
+		})
+	}
+}
@@ -1,45 +1,135 @@
 package jwt
 
 import (
-	"time"
+	"brothers_in_batash/internal/pkg/logging"
+	"brothers_in_batash/internal/pkg/middleware/requestctx"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
 
 	"github.com/gofiber/fiber/v2"
 	jwtware "github.com/gofiber/jwt/v4"
 	jtoken "github.com/golang-jwt/jwt/v5"
-	"github.com/pkg/errors"
 )
 
 const (
-	SigningSecret          = "secret"
-	ContextKey             = "token"
-	TokenExpiration        = time.Hour * 6      // 6 hours
-	RefreshTokenExpiration = time.Hour * 24 * 7 // 7 days
-	IDClaimField           = "ID"
-	ExpiryClaimField       = "exp"
-	RoleClaimField         = "role"
+	ContextKey            = "token"
+	IDClaimField          = "ID"
+	TokenIDClaimField     = "jti"
+	TokenTypeClaimField   = "type"
+	TokenFamilyClaimField = "family"
+	ExpiryClaimField      = "exp"
+	RoleClaimField        = "role"
+	SoldierIDClaimField   = "soldierId"
+	// RoleLocalsKey and SoldierIDLocalsKey hold the authenticated user's role and soldier ID in the request's locals
+	RoleLocalsKey      = "role"
+	SoldierIDLocalsKey = "soldierId"
 )
 
-func NewAuthMiddleware(secret string) fiber.Handler {
+// TokenType tells apart the tokens that authenticate requests from the ones that only obtain new tokens
+type TokenType string
+
+const (
+	AccessTokenType  TokenType = "access"
+	RefreshTokenType TokenType = "refresh"
+)
+
+// NewAuthMiddleware authenticates requests by their access token, rejecting the tokens revokedTokenStore holds, either
+// by their own ID or by their family's
+func NewAuthMiddleware(tokenIssuer *TokenIssuer, revokedTokenStore store.IRevokedTokenStore) fiber.Handler {
 	return jwtware.New(jwtware.Config{
 		SigningKey: jwtware.SigningKey{
 			JWTAlg: jwtware.HS256,
-			Key:    []byte(secret),
+			Key:    tokenIssuer.secret,
 		},
-		ContextKey: ContextKey,
+		ContextKey:     ContextKey,
+		SuccessHandler: newRevocationSuccessHandler(revokedTokenStore),
 	})
 }
 
-func GenerateToken(username string, expiration time.Duration) (string, error) {
-	claims := jtoken.MapClaims{
-		IDClaimField:     username,
-		ExpiryClaimField: time.Now().Add(expiration).Unix(),
-		//TODO - implement user roles functionality
-		RoleClaimField: "admin",
+// newRevocationSuccessHandler responds with 401 to requests whose token is not an access token, or was revoked. The
+// rest go on to userContextSuccessHandler.
+func newRevocationSuccessHandler(revokedTokenStore store.IRevokedTokenStore) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var claims jtoken.MapClaims
+		if token, ok := ctx.Locals(ContextKey).(*jtoken.Token); ok {
+			claims, _ = token.Claims.(jtoken.MapClaims)
+		}
+		if tokenType, _ := claims[TokenTypeClaimField].(string); TokenType(tokenType) != AccessTokenType {
+			logging.Debug("Token is not an access token", []logging.LogProp{{"type", tokenType}, {"path", ctx.Path()}})
+			return ctx.SendStatus(fiber.StatusUnauthorized)
+		}
+		revoked, err := isTokenRevoked(ctx.UserContext(), revokedTokenStore, claims)
+		if err != nil {
+			logging.Warning(err, "Failed checking if token is revoked", nil)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+		if revoked {
+			logging.Debug("Revoked token used", []logging.LogProp{{"path", ctx.Path()}})
+			return ctx.SendStatus(fiber.StatusUnauthorized)
+		}
+		return userContextSuccessHandler(ctx)
+	}
+}
+
+// userContextSuccessHandler hands the authenticated username down to the stores, through the request's user context.
+// The user's role and soldier ID are kept in the request's locals, for RequireRole and RequireRoleOrSoldier.
+func userContextSuccessHandler(ctx *fiber.Ctx) error {
+	if token, ok := ctx.Locals(ContextKey).(*jtoken.Token); ok {
+		if claims, ok := token.Claims.(jtoken.MapClaims); ok {
+			if username, ok := claims[IDClaimField].(string); ok {
+				ctx.SetUserContext(requestctx.WithUsername(ctx.UserContext(), username))
+			}
+			if role, ok := claims[RoleClaimField].(string); ok {
+				ctx.Locals(RoleLocalsKey, models.UserRole(role))
+			}
+			if soldierID, ok := claims[SoldierIDClaimField].(string); ok {
+				ctx.Locals(SoldierIDLocalsKey, soldierID)
+			}
+		}
+	}
+	return ctx.Next()
+}
+
+// RequireRole lets through the requests of users whose role includes role, and forbids the rest. It must come after the
+// auth middleware, which sets the user's role.
+func RequireRole(role models.UserRole) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if userRole, _ := ctx.Locals(RoleLocalsKey).(models.UserRole); !userRole.Includes(role) {
+			logging.Debug("User role is not allowed to access route", []logging.LogProp{{"role", string(userRole)},
+				{"requiredRole", string(role)}, {"path", ctx.Path()}})
+			return ctx.SendStatus(fiber.StatusForbidden)
+		}
+		return ctx.Next()
+	}
+}
+
+// RequireRoleOrSoldier is the same as RequireRole, but also lets soldiers through to the resources of their own soldier,
+// whose ID is in the soldierIDParam route param
+func RequireRoleOrSoldier(role models.UserRole, soldierIDParam string) fiber.Handler {
+	requireRole := RequireRole(role)
+	return func(ctx *fiber.Ctx) error {
+		userRole, _ := ctx.Locals(RoleLocalsKey).(models.UserRole)
+		soldierID, _ := ctx.Locals(SoldierIDLocalsKey).(string)
+		if userRole.Includes(models.SoldierUserRole) && soldierID != "" && soldierID == ctx.Params(soldierIDParam) {
+			return ctx.Next()
+		}
+		return requireRole(ctx)
+	}
+}
+
+// isTokenRevoked tells whether the token of claims was revoked, either by its own ID or by its family's. Tokens without
+// either ID can not be told apart from the revoked ones, so they count as revoked too.
+func isTokenRevoked(ctx context.Context, revokedTokenStore store.IRevokedTokenStore, claims jtoken.MapClaims) (bool, error) {
+	tokenID, _ := claims[TokenIDClaimField].(string)
+	familyID, _ := claims[TokenFamilyClaimField].(string)
+	if tokenID == "" || familyID == "" {
+		return true, nil
 	}
-	token := jtoken.NewWithClaims(jtoken.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(SigningSecret))
-	if err != nil {
-		return "", errors.Wrap(err, "could not sign JWT token")
+	for _, id := range []string{tokenID, familyID} {
+		if revoked, err := revokedTokenStore.IsTokenRevoked(ctx, id); err != nil || revoked {
+			return revoked, err
+		}
 	}
-	return signedToken, nil
+	return false, nil
 }
@@ -0,0 +1,178 @@
+package jwt_test
+
+import (
+	jwtmw "brothers_in_batash/internal/pkg/middleware/jwt"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"brothers_in_batash/internal/pkg/test_utils"
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	jtoken "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireRole(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userRole       models.UserRole
+		expectedStatus int
+	}{
+		{name: "higher role", userRole: models.PlatoonAdminUserRole, expectedStatus: fiber.StatusOK},
+		{name: "same role", userRole: models.SquadCommanderUserRole, expectedStatus: fiber.StatusOK},
+		{name: "lower role", userRole: models.SoldierUserRole, expectedStatus: fiber.StatusForbidden},
+		{name: "unknown role", userRole: "admin", expectedStatus: fiber.StatusForbidden},
+		{name: "no role", userRole: "", expectedStatus: fiber.StatusForbidden},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			app := fiber.New()
+			app.Get("/", test_utils.NewRoleJWTMiddleware(testCase.userRole, ""),
+				jwtmw.RequireRole(models.SquadCommanderUserRole), func(ctx *fiber.Ctx) error {
+					return ctx.SendStatus(fiber.StatusOK)
+				})
+
+			// Act
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), test_utils.TestTimeout)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestRequireRoleOrSoldier(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userRole       models.UserRole
+		userSoldierID  string
+		expectedStatus int
+	}{
+		{name: "own soldier", userRole: models.SoldierUserRole, userSoldierID: "7", expectedStatus: fiber.StatusOK},
+		{name: "other soldier", userRole: models.SoldierUserRole, userSoldierID: "8", expectedStatus: fiber.StatusForbidden},
+		{name: "user without a soldier", userRole: models.SoldierUserRole, userSoldierID: "", expectedStatus: fiber.StatusForbidden},
+		{name: "viewer of own soldier", userRole: models.ViewerUserRole, userSoldierID: "7", expectedStatus: fiber.StatusForbidden},
+		{name: "required role", userRole: models.SquadCommanderUserRole, userSoldierID: "", expectedStatus: fiber.StatusOK},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			app := fiber.New()
+			app.Post("/soldiers/:id", test_utils.NewRoleJWTMiddleware(testCase.userRole, testCase.userSoldierID),
+				jwtmw.RequireRoleOrSoldier(models.SquadCommanderUserRole, "id"), func(ctx *fiber.Ctx) error {
+					return ctx.SendStatus(fiber.StatusOK)
+				})
+
+			// Act
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/soldiers/7", nil), test_utils.TestTimeout)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestNewAuthMiddleware__exposes_token_role(t *testing.T) {
+	// Arrange
+	tokenIssuer := newTestTokenIssuer(t)
+	token, err := tokenIssuer.GenerateToken(models.User{Username: "user", Role: models.SoldierUserRole, SoldierID: "7"},
+		jwtmw.AccessTokenType, "family")
+	require.NoError(t, err)
+	app := fiber.New()
+	var role models.UserRole
+	var soldierID string
+	app.Get("/", jwtmw.NewAuthMiddleware(tokenIssuer, newTestRevokedTokenStore(t)), func(ctx *fiber.Ctx) error {
+		role, _ = ctx.Locals(jwtmw.RoleLocalsKey).(models.UserRole)
+		soldierID, _ = ctx.Locals(jwtmw.SoldierIDLocalsKey).(string)
+		return ctx.SendStatus(fiber.StatusOK)
+	})
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, models.SoldierUserRole, role)
+	assert.Equal(t, "7", soldierID)
+}
+
+func TestNewAuthMiddleware__rejected_tokens(t *testing.T) {
+	// Arrange
+	tokenIssuer := newTestTokenIssuer(t)
+	user := models.User{Username: "user", Role: models.SoldierUserRole}
+	validToken, err := tokenIssuer.GenerateToken(user, jwtmw.AccessTokenType, "family")
+	require.NoError(t, err)
+	revokedToken, err := tokenIssuer.GenerateToken(user, jwtmw.AccessTokenType, "family")
+	require.NoError(t, err)
+	revokedFamilyToken, err := tokenIssuer.GenerateToken(user, jwtmw.AccessTokenType, "revoked family")
+	require.NoError(t, err)
+	refreshToken, err := tokenIssuer.GenerateToken(user, jwtmw.RefreshTokenType, "family")
+	require.NoError(t, err)
+	noIDToken, err := jtoken.NewWithClaims(jtoken.SigningMethodHS256, jtoken.MapClaims{
+		jwtmw.IDClaimField:        user.Username,
+		jwtmw.TokenTypeClaimField: string(jwtmw.AccessTokenType),
+		jwtmw.ExpiryClaimField:    time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+	revokedTokenStore := newTestRevokedTokenStore(t)
+	parsedRevokedToken, _, err := jtoken.NewParser().ParseUnverified(revokedToken, jtoken.MapClaims{})
+	require.NoError(t, err)
+	revokedTokenID, _ := parsedRevokedToken.Claims.(jtoken.MapClaims)[jwtmw.TokenIDClaimField].(string)
+	for _, revokedID := range []string{revokedTokenID, "revoked family"} {
+		_, err := revokedTokenStore.RevokeToken(context.Background(),
+			models.RevokedToken{ID: revokedID, ExpiresAt: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+	}
+	app := fiber.New()
+	app.Get("/", jwtmw.NewAuthMiddleware(tokenIssuer, revokedTokenStore), func(ctx *fiber.Ctx) error {
+		return ctx.SendStatus(fiber.StatusOK)
+	})
+	testCases := []struct {
+		name           string
+		token          string
+		expectedStatus int
+	}{
+		{name: "valid token", token: validToken, expectedStatus: fiber.StatusOK},
+		{name: "revoked token", token: revokedToken, expectedStatus: fiber.StatusUnauthorized},
+		{name: "revoked family", token: revokedFamilyToken, expectedStatus: fiber.StatusUnauthorized},
+		{name: "refresh token", token: refreshToken, expectedStatus: fiber.StatusUnauthorized},
+		{name: "token without ID", token: noIDToken, expectedStatus: fiber.StatusUnauthorized},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+testCase.token)
+
+			// Act
+			resp, err := app.Test(req, test_utils.TestTimeout)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+const testJWTSecret = "test secret"
+
+func newTestTokenIssuer(t *testing.T) *jwtmw.TokenIssuer {
+	tokenIssuer, err := jwtmw.NewTokenIssuer(testJWTSecret, time.Hour, 24*time.Hour)
+	require.NoError(t, err)
+	return tokenIssuer
+}
+
+func newTestRevokedTokenStore(t *testing.T) *store.InMemRevokedTokenStore {
+	revokedTokenStore, err := store.NewRevokedTokenStore()
+	require.NoError(t, err)
+	return revokedTokenStore
+}
@@ -0,0 +1,55 @@
+package requestctx
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	usernameKey
+)
+
+// New returns a middleware that sets up the request's user context - the context handed down to the stores. It carries
+// the request ID, taken from the RequestIDHeader or generated, and a deadline of timeout from now.
+func New(timeout time.Duration) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		requestID := ctx.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		ctx.Set(RequestIDHeader, requestID)
+
+		userCtx, cancel := context.WithTimeout(WithRequestID(ctx.UserContext(), requestID), timeout)
+		defer cancel()
+		ctx.SetUserContext(userCtx)
+		return ctx.Next()
+	}
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the ID of the request ctx belongs to, or an empty string if there is none
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+func WithUsername(ctx context.Context, username string) context.Context {
+	return context.WithValue(ctx, usernameKey, username)
+}
+
+// Username returns the authenticated user making the request ctx belongs to, or an empty string if there is none
+func Username(ctx context.Context) string {
+	username, _ := ctx.Value(usernameKey).(string)
+	return username
+}
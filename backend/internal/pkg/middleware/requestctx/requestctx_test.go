@@ -0,0 +1,55 @@
+package requestctx_test
+
+import (
+	"brothers_in_batash/internal/pkg/middleware/requestctx"
+	"brothers_in_batash/internal/pkg/test_utils"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestCtx_New__propagates_request_id_and_deadline(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	app.Use(requestctx.New(time.Minute))
+	var requestID string
+	var hasDeadline bool
+	app.Get("/", func(ctx *fiber.Ctx) error {
+		requestID = requestctx.RequestID(ctx.UserContext())
+		_, hasDeadline = ctx.UserContext().Deadline()
+		return ctx.SendStatus(fiber.StatusOK)
+	})
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(requestctx.RequestIDHeader, "request-id")
+
+	// Act
+	resp, err := app.Test(req, test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "request-id", requestID)
+	assert.Equal(t, "request-id", resp.Header.Get(requestctx.RequestIDHeader))
+	assert.True(t, hasDeadline)
+}
+
+func TestRequestCtx_New__generates_missing_request_id(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	app.Use(requestctx.New(time.Minute))
+	var requestID string
+	app.Get("/", func(ctx *fiber.Ctx) error {
+		requestID = requestctx.RequestID(ctx.UserContext())
+		return ctx.SendStatus(fiber.StatusOK)
+	})
+
+	// Act
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), test_utils.TestTimeout)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, requestID)
+	assert.Equal(t, requestID, resp.Header.Get(requestctx.RequestIDHeader))
+}
@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIAuditStore struct {
+	mock.Mock
+}
+
+func (m *MockIAuditStore) AddAuditEntry(ctx context.Context, entry models.AuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockIAuditStore) FindAuditEntries(ctx context.Context, entityType models.AuditEntityType, entityID string) ([]models.AuditEntry, error) {
+	args := m.Called(ctx, entityType, entityID)
+	return args.Get(0).([]models.AuditEntry), args.Error(1)
+}
@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
 	"time"
 
 	"github.com/stretchr/testify/mock"
@@ -11,27 +13,32 @@ type MockIDayStore struct {
 	mock.Mock
 }
 
-func (m *MockIDayStore) CreateNewDaySchedule(day models.DaySchedule) error {
-	args := m.Called(day)
+func (m *MockIDayStore) CreateNewDaySchedule(ctx context.Context, day models.DaySchedule) error {
+	args := m.Called(ctx, day)
 	return args.Error(0)
 }
 
-func (m *MockIDayStore) FindDaySchedule(date time.Time) ([]models.DaySchedule, error) {
-	args := m.Called(date)
+func (m *MockIDayStore) FindDaySchedule(ctx context.Context, date time.Time) ([]models.DaySchedule, error) {
+	args := m.Called(ctx, date)
 	return args.Get(0).([]models.DaySchedule), args.Error(1)
 }
 
-func (m *MockIDayStore) FindAllDaySchedules() ([]models.DaySchedule, error) {
-	args := m.Called()
+func (m *MockIDayStore) FindAllDaySchedules(ctx context.Context) ([]models.DaySchedule, error) {
+	args := m.Called(ctx)
 	return args.Get(0).([]models.DaySchedule), args.Error(1)
 }
 
-func (m *MockIDayStore) UpdateDaySchedule(day models.DaySchedule) error {
-	args := m.Called(day)
+func (m *MockIDayStore) FindDaySchedules(ctx context.Context, filter store.DayScheduleFilter) ([]models.DaySchedule, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.DaySchedule), args.Error(1)
+}
+
+func (m *MockIDayStore) UpdateDaySchedule(ctx context.Context, day models.DaySchedule) error {
+	args := m.Called(ctx, day)
 	return args.Error(0)
 }
 
-func (m *MockIDayStore) DeleteDaySchedule(date time.Time) error {
-	args := m.Called(date)
+func (m *MockIDayStore) DeleteDaySchedule(ctx context.Context, date time.Time, version int) error {
+	args := m.Called(ctx, date, version)
 	return args.Error(0)
 }
@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockILeaveStore is a mock of ILeaveStore interface.
+type MockILeaveStore struct {
+	mock.Mock
+}
+
+// CreateNewLeave mocks base method.
+func (m *MockILeaveStore) CreateNewLeave(ctx context.Context, leave models.Leave) error {
+	args := m.Called(ctx, leave)
+	return args.Error(0)
+}
+
+// FindLeaveByID mocks base method.
+func (m *MockILeaveStore) FindLeaveByID(ctx context.Context, id string) ([]models.Leave, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]models.Leave), args.Error(1)
+}
+
+// FindLeavesBySoldierID mocks base method.
+func (m *MockILeaveStore) FindLeavesBySoldierID(ctx context.Context, soldierID string) ([]models.Leave, error) {
+	args := m.Called(ctx, soldierID)
+	return args.Get(0).([]models.Leave), args.Error(1)
+}
+
+// FindAllLeaves mocks base method.
+func (m *MockILeaveStore) FindAllLeaves(ctx context.Context) ([]models.Leave, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Leave), args.Error(1)
+}
+
+// UpdateLeave mocks base method.
+func (m *MockILeaveStore) UpdateLeave(ctx context.Context, leave models.Leave) error {
+	args := m.Called(ctx, leave)
+	return args.Error(0)
+}
+
+// DeleteLeave mocks base method.
+func (m *MockILeaveStore) DeleteLeave(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockIRotationStore is a mock of IRotationStore interface.
+type MockIRotationStore struct {
+	mock.Mock
+}
+
+// CreateNewRotation mocks base method.
+func (m *MockIRotationStore) CreateNewRotation(ctx context.Context, rotation models.Rotation) error {
+	args := m.Called(ctx, rotation)
+	return args.Error(0)
+}
+
+// FindRotationByID mocks base method.
+func (m *MockIRotationStore) FindRotationByID(ctx context.Context, id string) ([]models.Rotation, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]models.Rotation), args.Error(1)
+}
+
+// FindRotationsBySoldierID mocks base method.
+func (m *MockIRotationStore) FindRotationsBySoldierID(ctx context.Context, soldierID string) ([]models.Rotation, error) {
+	args := m.Called(ctx, soldierID)
+	return args.Get(0).([]models.Rotation), args.Error(1)
+}
+
+// FindAllRotations mocks base method.
+func (m *MockIRotationStore) FindAllRotations(ctx context.Context) ([]models.Rotation, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Rotation), args.Error(1)
+}
+
+// UpdateRotation mocks base method.
+func (m *MockIRotationStore) UpdateRotation(ctx context.Context, rotation models.Rotation) error {
+	args := m.Called(ctx, rotation)
+	return args.Error(0)
+}
+
+// DeleteRotation mocks base method.
+func (m *MockIRotationStore) DeleteRotation(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
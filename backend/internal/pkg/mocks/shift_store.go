@@ -2,6 +2,9 @@ package mocks
 
 import (
 	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -10,27 +13,42 @@ type MockIShiftStore struct {
 	mock.Mock
 }
 
-func (m *MockIShiftStore) CreateNewShift(shift models.Shift) error {
-	args := m.Called(shift)
+func (m *MockIShiftStore) CreateNewShift(ctx context.Context, shift models.Shift) error {
+	args := m.Called(ctx, shift)
 	return args.Error(0)
 }
 
-func (m *MockIShiftStore) FindShiftByID(id string) ([]models.Shift, error) {
-	args := m.Called(id)
+func (m *MockIShiftStore) FindShiftByID(ctx context.Context, id string) ([]models.Shift, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).([]models.Shift), args.Error(1)
 }
 
-func (m *MockIShiftStore) FindAllShifts() ([]models.Shift, error) {
-	args := m.Called()
+func (m *MockIShiftStore) FindAllShifts(ctx context.Context) ([]models.Shift, error) {
+	args := m.Called(ctx)
 	return args.Get(0).([]models.Shift), args.Error(1)
 }
 
-func (m *MockIShiftStore) UpdateShift(shift models.Shift) error {
-	args := m.Called(shift)
+func (m *MockIShiftStore) FindShifts(ctx context.Context, filter store.ShiftFilter) ([]models.Shift, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.Shift), args.Error(1)
+}
+
+func (m *MockIShiftStore) UpdateShift(ctx context.Context, shift models.Shift) error {
+	args := m.Called(ctx, shift)
+	return args.Error(0)
+}
+
+func (m *MockIShiftStore) DeleteShift(ctx context.Context, id string, version int) error {
+	args := m.Called(ctx, id, version)
 	return args.Error(0)
 }
 
-func (m *MockIShiftStore) DeleteShift(id string) error {
-	args := m.Called(id)
+func (m *MockIShiftStore) RestoreShift(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
+
+func (m *MockIShiftStore) PurgeDeletedShifts(ctx context.Context, deletedBefore time.Time) (int, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Int(0), args.Error(1)
+}
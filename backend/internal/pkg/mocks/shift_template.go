@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -12,31 +14,41 @@ type MockIShiftTemplateStore struct {
 }
 
 // CreateNewShiftTemplate mocks base method.
-func (m *MockIShiftTemplateStore) CreateNewShiftTemplate(template models.ShiftTemplate) error {
-	args := m.Called(template)
+func (m *MockIShiftTemplateStore) CreateNewShiftTemplate(ctx context.Context, template models.ShiftTemplate) error {
+	args := m.Called(ctx, template)
 	return args.Error(0)
 }
 
 // FindShiftTemplateByID mocks base method.
-func (m *MockIShiftTemplateStore) FindShiftTemplateByID(id string) ([]models.ShiftTemplate, error) {
-	args := m.Called(id)
+func (m *MockIShiftTemplateStore) FindShiftTemplateByID(ctx context.Context, id string) ([]models.ShiftTemplate, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).([]models.ShiftTemplate), args.Error(1)
 }
 
 // FindAllShiftsTemplate mocks base method.
-func (m *MockIShiftTemplateStore) FindAllShiftsTemplate() ([]models.ShiftTemplate, error) {
-	args := m.Called()
+func (m *MockIShiftTemplateStore) FindAllShiftsTemplate(ctx context.Context) ([]models.ShiftTemplate, error) {
+	args := m.Called(ctx)
 	return args.Get(0).([]models.ShiftTemplate), args.Error(1)
 }
 
 // UpdateShiftTemplate mocks base method.
-func (m *MockIShiftTemplateStore) UpdateShiftTemplate(template models.ShiftTemplate) error {
-	args := m.Called(template)
+func (m *MockIShiftTemplateStore) UpdateShiftTemplate(ctx context.Context, template models.ShiftTemplate) error {
+	args := m.Called(ctx, template)
 	return args.Error(0)
 }
 
 // DeleteShiftTemplate mocks base method.
-func (m *MockIShiftTemplateStore) DeleteShiftTemplate(id string) error {
-	args := m.Called(id)
+func (m *MockIShiftTemplateStore) DeleteShiftTemplate(ctx context.Context, id string, version int) error {
+	args := m.Called(ctx, id, version)
 	return args.Error(0)
 }
+
+func (m *MockIShiftTemplateStore) RestoreShiftTemplate(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockIShiftTemplateStore) PurgeDeletedShiftTemplates(ctx context.Context, deletedBefore time.Time) (int, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Int(0), args.Error(1)
+}
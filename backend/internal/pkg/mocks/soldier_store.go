@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -10,27 +12,37 @@ type MockISoldierStore struct {
 	mock.Mock
 }
 
-func (m *MockISoldierStore) CreateNewSoldier(soldier models.Soldier) error {
-	args := m.Called(soldier)
+func (m *MockISoldierStore) CreateNewSoldier(ctx context.Context, soldier models.Soldier) error {
+	args := m.Called(ctx, soldier)
 	return args.Error(0)
 }
 
-func (m *MockISoldierStore) FindSoldierByID(id string) ([]models.Soldier, error) {
-	args := m.Called(id)
+func (m *MockISoldierStore) FindSoldierByID(ctx context.Context, id string) ([]models.Soldier, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).([]models.Soldier), args.Error(1)
 }
 
-func (m *MockISoldierStore) FindAllSoldiers() ([]models.Soldier, error) {
-	args := m.Called()
+func (m *MockISoldierStore) FindAllSoldiers(ctx context.Context) ([]models.Soldier, error) {
+	args := m.Called(ctx)
 	return args.Get(0).([]models.Soldier), args.Error(1)
 }
 
-func (m *MockISoldierStore) UpdateSoldier(soldier models.Soldier) error {
-	args := m.Called(soldier)
+func (m *MockISoldierStore) UpdateSoldier(ctx context.Context, soldier models.Soldier) error {
+	args := m.Called(ctx, soldier)
 	return args.Error(0)
 }
 
-func (m *MockISoldierStore) DeleteSoldier(id string) error {
-	args := m.Called(id)
+func (m *MockISoldierStore) DeleteSoldier(ctx context.Context, id string, version int) error {
+	args := m.Called(ctx, id, version)
 	return args.Error(0)
 }
+
+func (m *MockISoldierStore) RestoreSoldier(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockISoldierStore) PurgeDeletedSoldiers(ctx context.Context, deletedBefore time.Time) (int, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,16 @@
+package mocks
+
+import (
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+)
+
+// MockITransactor runs units of work directly against Stores, which are usually mocks themselves. Writes are not
+// rolled back when a unit of work fails.
+type MockITransactor struct {
+	Stores store.TxStores
+}
+
+func (m *MockITransactor) WithinTx(ctx context.Context, work func(stores store.TxStores) error) error {
+	return work(m.Stores)
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntityType names the kinds of entities whose changes are audited
+type AuditEntityType string
+
+const (
+	ShiftAuditEntity         AuditEntityType = "shift"
+	SoldierAuditEntity       AuditEntityType = "soldier"
+	ShiftTemplateAuditEntity AuditEntityType = "shift-template"
+	DayScheduleAuditEntity   AuditEntityType = "day-schedule"
+)
+
+func (t AuditEntityType) IsValid() bool {
+	switch t {
+	case ShiftAuditEntity, SoldierAuditEntity, ShiftTemplateAuditEntity, DayScheduleAuditEntity:
+		return true
+	default:
+		return false
+	}
+}
+
+type AuditAction string
+
+const (
+	CreateAuditAction AuditAction = "create"
+	UpdateAuditAction AuditAction = "update"
+	DeleteAuditAction AuditAction = "delete"
+	// RestoreAuditAction undoes a soft delete. Its entries have no Before, since deleted entities can not be found.
+	RestoreAuditAction AuditAction = "restore"
+)
+
+// AuditEntry records a single change of an entity - who made it, when, and what the entity looked like before and after
+type AuditEntry struct {
+	ID         string          `json:"id"`
+	EntityType AuditEntityType `json:"entityType"`
+	// EntityID is the ID of the changed entity, or the date of a DaySchedule, formatted as 2006-01-02
+	EntityID  string      `json:"entityId"`
+	Action    AuditAction `json:"action"`
+	Username  string      `json:"username"`
+	RequestID string      `json:"requestId"`
+	Timestamp time.Time   `json:"timestamp"`
+	// Before is empty for a creation, and After is empty for a deletion
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+	// ChangedFields lists the top level fields whose values differ between Before and After
+	ChangedFields []string `json:"changedFields"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+)
+
+type LeaveType int
+
+const (
+	HomeLeaveType LeaveType = iota
+	SickLeaveType
+	CourseLeaveType
+	// PartialDayLeaveType is a few hours of unavailability, e.g. a medical appointment
+	PartialDayLeaveType
+)
+
+// Leave is a period of time in which a soldier is unavailable for duty
+type Leave struct {
+	ID          string    `json:"id" validate:"required"`
+	SoldierID   string    `json:"soldierId" validate:"required"`
+	Type        LeaveType `json:"type" validate:"min=0,max=3"`
+	StartTime   time.Time `json:"startTime" validate:"required"`
+	EndTime     time.Time `json:"endTime" validate:"required,gtfield=StartTime"`
+	Description string    `json:"description" validate:"omitempty,max=255"`
+}
+
+func (l Leave) IsValid() error {
+	if err := validator.New().Struct(l); err != nil {
+		return errors.Wrap(err, "leave failed validation")
+	}
+	return nil
+}
+
+// Overlaps indicates whether any part of the [start, end) time range takes place during the leave
+func (l Leave) Overlaps(start, end time.Time) bool {
+	return l.StartTime.Before(end) && start.Before(l.EndTime)
+}
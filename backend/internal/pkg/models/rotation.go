@@ -0,0 +1,55 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+)
+
+// Rotation is a base/home cycle, e.g. 11/3 - soldiers spend OnBaseDays on base, then HomeDays at home, and so on.
+// The first cycle begins on StartDate. A squad's rotation simply lists all of the squad's soldiers.
+type Rotation struct {
+	ID         string    `json:"id" validate:"required"`
+	Name       string    `json:"name" validate:"required,min=1,max=64"`
+	SoldierIDs []string  `json:"soldierIds" validate:"min=1,dive,required"`
+	OnBaseDays int       `json:"onBaseDays" validate:"min=1"`
+	HomeDays   int       `json:"homeDays" validate:"min=1"`
+	StartDate  time.Time `json:"startDate" validate:"required"`
+}
+
+func (r Rotation) IsValid() error {
+	if err := validator.New().Struct(r); err != nil {
+		return errors.Wrap(err, "rotation failed validation")
+	}
+	return nil
+}
+
+// CycleLength is the number of days in a single base/home cycle
+func (r Rotation) CycleLength() int {
+	return r.OnBaseDays + r.HomeDays
+}
+
+// IsOnBase indicates whether the rotation's soldiers are on base on date. Before StartDate the rotation is not in
+// effect, so soldiers are considered on base.
+func (r Rotation) IsOnBase(date time.Time) bool {
+	day := r.dayOfCycle(date)
+	return day < 0 || day%r.CycleLength() < r.OnBaseDays
+}
+
+// HasSoldier indicates whether the soldier takes part in the rotation
+func (r Rotation) HasSoldier(soldierID string) bool {
+	for _, id := range r.SoldierIDs {
+		if id == soldierID {
+			return true
+		}
+	}
+	return false
+}
+
+// dayOfCycle returns the number of days passed between StartDate and date
+func (r Rotation) dayOfCycle(date time.Time) int {
+	start := time.Date(r.StartDate.Year(), r.StartDate.Month(), r.StartDate.Day(), 0, 0, 0, 0, r.StartDate.Location())
+	return int(math.Floor(date.Sub(start).Hours() / 24))
+}
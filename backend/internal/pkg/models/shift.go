@@ -17,6 +17,10 @@ const (
 	DailyDutyShiftType
 )
 
+func (t ShiftType) IsValid() bool {
+	return t >= MotorizedPatrolShiftType && t <= DailyDutyShiftType
+}
+
 type TimeOfDay struct {
 	Hour   int `json:"hour" validate:"min=0,max=23"`
 	Minute int `json:"minute" validate:"min=0,max=59"`
@@ -29,7 +33,7 @@ type ShiftTime struct {
 
 // PersonnelRequirement is a container for any personnel requirements/ constraints for a specific shift
 type PersonnelRequirement struct {
-	// SoldierRoleToCount maps between specific SoldierRole and the minimum number of soldiers with that Role that are required for the shift
+	// SoldierRoleToCount maps between a SoldierRole name and the minimum number of soldiers with that Role that are required for the shift
 	SoldierRoleToCount map[string]int
 }
 
@@ -38,42 +42,189 @@ type ShiftTemplate struct {
 	ID                   string               `json:"id" validate:"required"`
 	Name                 string               `json:"name" validate:"required"`
 	Description          string               `json:"description" validate:"required"`
+	ShiftType            ShiftType            `json:"shiftType" validate:"min=0"`
 	PersonnelRequirement PersonnelRequirement `json:"personnelRequirement" validate:"required"`
 	// DaysOfOccurrences maps from a weekday to start and end times of a shift.
 	//An empty slice would indicate this shift will not happen on that weekday.
 	DaysOfOccurrences map[time.Weekday][]ShiftTime `json:"dayOfWeek" validate:"required"`
+	// Version is bumped by the store on every update, and is used to detect concurrent modifications
+	Version int `json:"version"`
+	// DeletedAt is set by the store when the template is deleted. Deleted templates are hidden, but can be restored until
+	// they are purged.
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
 
 // Shift describes a specific shift, in a specific time and date.
 // Shift could be created based on a ShiftTemplate(will provide constraints), or out of scratch.
+// A Shift without a Commander is considered unstaffed(e.g. a shift that was just generated out of a ShiftTemplate).
 type Shift struct {
-	ID                 string    `json:"id" validate:"required"`
-	StartTime          time.Time `json:"startTime" validate:"required"`
-	EndTime            time.Time `json:"endTime" validate:"required"`
-	Name               string    `json:"name" validate:"required"`
-	Type               ShiftType `json:"type" validate:"min=0"`
-	Commander          Soldier   `json:"commander" validate:"required"`
-	AdditionalSoldiers []Soldier `json:"additionalSoldiers" validate:"dive"`
+	ID        string    `json:"id" validate:"required"`
+	StartTime time.Time `json:"startTime" validate:"required"`
+	EndTime   time.Time `json:"endTime" validate:"required"`
+	Name      string    `json:"name" validate:"required"`
+	Type      ShiftType `json:"type" validate:"min=0"`
+	// Commander and AdditionalSoldiers reference stored soldiers by their IDs. Only the IDs are stored - the rest of the
+	// soldiers' fields are resolved from the soldier store whenever the shift is read, so they are never stale.
+	Commander          Soldier   `json:"commander" validate:"-"`
+	AdditionalSoldiers []Soldier `json:"additionalSoldiers" validate:"-"`
 	Description        string    `json:"description" validate:"omitempty,min=1,max=255"`
 	ShiftTemplateID    string    `json:"shiftTemplateId" validate:"omitempty"`
+	// Understaffed flags a shift that was saved although its soldiers do not cover its template's PersonnelRequirement,
+	// or some of them are on leave during it
+	Understaffed bool       `json:"understaffed"`
+	Version      int        `json:"version"`
+	DeletedAt    *time.Time `json:"deletedAt,omitempty"`
 }
 
 type DaySchedule struct {
 	Date   time.Time `json:"date" validate:"required"`
-	Shifts []Shift   `json:"shifts" validate:"required,min=1"`
+	Shifts []Shift   `json:"shifts"`
+	// ShiftIDs reference the shifts generated out of shift templates into the day schedule. Only the IDs are stored -
+	// the shifts themselves are kept in the shift store, and resolved into Shifts whenever the day schedule is read.
+	ShiftIDs []string `json:"shiftIds,omitempty"`
+	Version  int      `json:"version"`
 }
 
 func (s Shift) IsValid() error {
 	if err := validator.New().Struct(s); err != nil {
 		return errors.Wrap(err, "shift failed validation")
 	}
+	for _, soldier := range s.AdditionalSoldiers {
+		if soldier.ID == "" {
+			return errors.New("shift failed validation: additional soldier has no ID")
+		}
+	}
 	return nil
 }
 
+func (s Shift) IsStaffed() bool {
+	return s.Commander.ID != ""
+}
+
+// Soldiers returns all the soldiers staffed in the shift, commander included
+func (s Shift) Soldiers() []Soldier {
+	if !s.IsStaffed() {
+		return append([]Soldier{}, s.AdditionalSoldiers...)
+	}
+	return append([]Soldier{s.Commander}, s.AdditionalSoldiers...)
+}
+
+func (s Shift) HasSoldier(soldierID string) bool {
+	for _, soldier := range s.Soldiers() {
+		if soldier.ID == soldierID {
+			return true
+		}
+	}
+	return false
+}
+
+// WithSoldierRefs returns a copy of the shift whose soldiers are reduced to their IDs, the way a stored shift references
+// them
+func (s Shift) WithSoldierRefs() Shift {
+	if s.IsStaffed() {
+		s.Commander = Soldier{ID: s.Commander.ID}
+	}
+	if s.AdditionalSoldiers == nil {
+		return s
+	}
+	refs := make([]Soldier, 0, len(s.AdditionalSoldiers))
+	for _, soldier := range s.AdditionalSoldiers {
+		refs = append(refs, Soldier{ID: soldier.ID})
+	}
+	s.AdditionalSoldiers = refs
+	return s
+}
+
+// WithResolvedSoldiers returns a copy of the shift whose soldier references are replaced by the soldiers they reference.
+// References to soldiers missing from soldiersByID are kept as they are.
+func (s Shift) WithResolvedSoldiers(soldiersByID map[string]Soldier) Shift {
+	if soldier, found := soldiersByID[s.Commander.ID]; found && s.IsStaffed() {
+		s.Commander = soldier
+	}
+	if s.AdditionalSoldiers == nil {
+		return s
+	}
+	resolved := make([]Soldier, 0, len(s.AdditionalSoldiers))
+	for _, soldier := range s.AdditionalSoldiers {
+		if stored, found := soldiersByID[soldier.ID]; found {
+			soldier = stored
+		}
+		resolved = append(resolved, soldier)
+	}
+	s.AdditionalSoldiers = resolved
+	return s
+}
+
+// WithoutSoldier returns a copy of the shift that soldierID is not staffed in. A shift that loses its commander is left
+// unstaffed.
+func (s Shift) WithoutSoldier(soldierID string) Shift {
+	if s.Commander.ID == soldierID {
+		s.Commander = Soldier{}
+	}
+	if s.AdditionalSoldiers == nil {
+		return s
+	}
+	remaining := make([]Soldier, 0, len(s.AdditionalSoldiers))
+	for _, soldier := range s.AdditionalSoldiers {
+		if soldier.ID != soldierID {
+			remaining = append(remaining, soldier)
+		}
+	}
+	s.AdditionalSoldiers = remaining
+	return s
+}
+
+// WithoutSoldier returns a copy of the day schedule that soldierID is not staffed in any of its shifts starting after
+// from. The shifts that already started keep their soldiers.
+func (d DaySchedule) WithoutSoldier(soldierID string, from time.Time) DaySchedule {
+	shifts := make([]Shift, 0, len(d.Shifts))
+	for _, shift := range d.Shifts {
+		if shift.StartTime.After(from) {
+			shift = shift.WithoutSoldier(soldierID)
+		}
+		shifts = append(shifts, shift)
+	}
+	d.Shifts = shifts
+	return d
+}
+
+// WithSoldierRefs returns a copy of the day schedule whose shifts reference their soldiers by ID, the same way as
+// Shift.WithSoldierRefs
+func (d DaySchedule) WithSoldierRefs() DaySchedule {
+	shifts := make([]Shift, 0, len(d.Shifts))
+	for _, shift := range d.Shifts {
+		shifts = append(shifts, shift.WithSoldierRefs())
+	}
+	d.Shifts = shifts
+	return d
+}
+
+// WithShiftRefs returns a copy of the day schedule that holds only the shifts it does not reference by ShiftIDs
+func (d DaySchedule) WithShiftRefs() DaySchedule {
+	if len(d.ShiftIDs) == 0 {
+		return d
+	}
+	referenced := make(map[string]bool, len(d.ShiftIDs))
+	for _, id := range d.ShiftIDs {
+		referenced[id] = true
+	}
+	shifts := make([]Shift, 0, len(d.Shifts))
+	for _, shift := range d.Shifts {
+		if !referenced[shift.ID] {
+			shifts = append(shifts, shift)
+		}
+	}
+	d.Shifts = shifts
+	return d
+}
+
 func (d DaySchedule) IsValid() error {
 	if err := validator.New().Struct(d); err != nil {
 		return errors.Wrap(err, "day schedule failed validation")
 	}
+	if len(d.Shifts) == 0 && len(d.ShiftIDs) == 0 {
+		return errors.New("day schedule has no shifts")
+	}
 	for _, shift := range d.Shifts {
 		if err := shift.IsValid(); err != nil {
 			return errors.Wrap(err, "day schedule member failed validation")
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type Soldier struct {
 	ID             string          `json:"id" validate:"required"`
 	FirstName      string          `json:"firstName" validate:"required,alpha"`
@@ -8,6 +10,24 @@ type Soldier struct {
 	PersonalNumber string          `json:"personalNumber" validate:"required,numeric,len=7"`
 	Position       SoldierPosition `json:"position" validate:"required"`
 	Roles          []SoldierRole   `json:"roles" validate:"min=1,dive"`
+	// Version is managed by the store, the same way as ShiftTemplate.Version
+	Version int `json:"version"`
+	// DeletedAt is managed by the store, the same way as ShiftTemplate.DeletedAt
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+func (s Soldier) HasRole(roleName string) bool {
+	for _, role := range s.Roles {
+		if role.Name == roleName {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCommanding indicates whether the soldier's position allows commanding a shift
+func (s Soldier) IsCommanding() bool {
+	return s.Position != RegularSoldierPosition
 }
 
 // SoldierRole is the "Pakal"s of the soldier. Admins will be able to edit and add roles.
@@ -1,7 +1,49 @@
 package models
 
+import "time"
+
 type User struct {
-	Username       string `validate:"ascii,min=4,max=100"`
-	HashedPassword []byte `validate:"ascii,min=4,max=100"`
-	SoldierID      string `validate:"omitempty"`
+	Username       string   `validate:"ascii,min=4,max=100"`
+	HashedPassword []byte   `validate:"ascii,min=4,max=100"`
+	SoldierID      string   `validate:"omitempty"`
+	Role           UserRole `validate:"oneof=platoon-admin squad-commander soldier viewer"`
+}
+
+// UserRole is the access level of a user. Every role is allowed whatever the roles ranked below it are allowed.
+type UserRole string
+
+const (
+	// PlatoonAdminUserRole manages the roster and the shift templates, on top of everything else
+	PlatoonAdminUserRole UserRole = "platoon-admin"
+	// SquadCommanderUserRole schedules shifts, leaves and rotations
+	SquadCommanderUserRole UserRole = "squad-commander"
+	// SoldierUserRole views the roster and the schedule, and manages the leaves of the user's own soldier
+	SoldierUserRole UserRole = "soldier"
+	// ViewerUserRole only views the roster and the schedule
+	ViewerUserRole UserRole = "viewer"
+)
+
+var userRoleRanks = map[UserRole]int{
+	ViewerUserRole:         1,
+	SoldierUserRole:        2,
+	SquadCommanderUserRole: 3,
+	PlatoonAdminUserRole:   4,
+}
+
+func (r UserRole) IsValid() bool {
+	_, ok := userRoleRanks[r]
+	return ok
+}
+
+// Includes indicates whether r is allowed whatever other is allowed
+func (r UserRole) Includes(other UserRole) bool {
+	return r.IsValid() && userRoleRanks[r] >= userRoleRanks[other]
+}
+
+// RevokedToken is a token that was revoked before it expired, such as on logout. It is rejected until it expires, and
+// then forgotten.
+type RevokedToken struct {
+	// ID is the token's jti claim
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expiresAt"`
 }
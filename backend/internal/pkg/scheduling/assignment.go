@@ -0,0 +1,228 @@
+package scheduling
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	NoAvailableCommanderReason = "no available commander"
+	UncoveredRolesReason       = "required roles could not be covered"
+)
+
+// CommanderSlot is the SlotChoice.Slot of a shift's commander. Other slots are named after the role they cover.
+const CommanderSlot = "commander"
+
+// ShiftAssignment is the staffing the Solver proposes for a single shift
+type ShiftAssignment struct {
+	ShiftID            string           `json:"shiftId"`
+	Commander          models.Soldier   `json:"commander"`
+	AdditionalSoldiers []models.Soldier `json:"additionalSoldiers"`
+	Choices            []SlotChoice     `json:"choices"`
+}
+
+// SlotChoice explains why a soldier was chosen for a slot in a shift - out of all the eligible candidates,
+// the soldier had the lowest load score
+type SlotChoice struct {
+	Slot       string         `json:"slot"`
+	SoldierID  string         `json:"soldierId"`
+	Score      ScoreBreakdown `json:"score"`
+	Candidates int            `json:"candidates"`
+}
+
+// UnassignedShift is a shift the Solver could not staff, along with the reason
+type UnassignedShift struct {
+	ShiftID    string          `json:"shiftId"`
+	Reason     string          `json:"reason"`
+	Shortfalls []RoleShortfall `json:"shortfalls,omitempty"`
+}
+
+type AssignmentResult struct {
+	Assignments []ShiftAssignment `json:"assignments"`
+	Unassigned  []UnassignedShift `json:"unassigned"`
+}
+
+// Solver staffs unstaffed shifts out of a pool of soldiers. A soldier is assigned to a shift only if all the
+// Solver's constraints allow it, and out of the allowed soldiers the one with the lowest load score is preferred.
+type Solver struct {
+	scorer      *Scorer
+	constraints []Constraint
+}
+
+func NewSolver(scorer *Scorer, constraints ...Constraint) (*Solver, error) {
+	if scorer == nil {
+		return nil, errors.New("scorer is nil")
+	}
+	for _, constraint := range constraints {
+		if constraint == nil {
+			return nil, errors.New("constraint is nil")
+		}
+	}
+	return &Solver{scorer: scorer, constraints: constraints}, nil
+}
+
+// WithConstraints returns a copy of the Solver which also applies constraints. It suits constraints that depend on
+// data which changes between runs, such as soldiers' leaves.
+func (s *Solver) WithConstraints(constraints ...Constraint) (*Solver, error) {
+	for _, constraint := range constraints {
+		if constraint == nil {
+			return nil, errors.New("constraint is nil")
+		}
+	}
+	combined := append(append(make([]Constraint, 0, len(s.constraints)+len(constraints)), s.constraints...), constraints...)
+	return &Solver{scorer: s.scorer, constraints: combined}, nil
+}
+
+// Solve proposes staffing for every unstaffed shift in shifts. existingShifts are the shifts soldiers are already
+// booked to, and templates are used for looking up the personnel requirements of template based shifts.
+// Shifts are staffed in chronological order, so earlier shifts get the first pick of soldiers.
+func (s *Solver) Solve(shifts []models.Shift, existingShifts []models.Shift, soldiers []models.Soldier,
+	templates []models.ShiftTemplate) AssignmentResult {
+	bookings := newBookings(existingShifts)
+	requirements := make(map[string]models.PersonnelRequirement, len(templates))
+	for _, template := range templates {
+		requirements[template.ID] = template.PersonnelRequirement
+	}
+	soldiers = sortedSoldiers(soldiers)
+
+	result := AssignmentResult{Assignments: make([]ShiftAssignment, 0), Unassigned: make([]UnassignedShift, 0)}
+	for _, shift := range sortedUnstaffedShifts(shifts) {
+		assignment, unassigned := s.staffShift(shift, requirements[shift.ShiftTemplateID], soldiers, bookings)
+		if unassigned != nil {
+			result.Unassigned = append(result.Unassigned, *unassigned)
+			continue
+		}
+		bookings.book(assignment.ApplyTo(shift))
+		result.Assignments = append(result.Assignments, assignment)
+	}
+	return result
+}
+
+// ApplyTo returns shift staffed according to the assignment
+func (a ShiftAssignment) ApplyTo(shift models.Shift) models.Shift {
+	shift.Commander = a.Commander
+	shift.AdditionalSoldiers = a.AdditionalSoldiers
+	shift.Understaffed = false
+	return shift
+}
+
+func (s *Solver) staffShift(shift models.Shift, requirement models.PersonnelRequirement, soldiers []models.Soldier,
+	bookings bookings) (ShiftAssignment, *UnassignedShift) {
+	assigned := make(map[string]bool)
+	eligible := func(soldier models.Soldier) bool {
+		return !assigned[soldier.ID] && s.allows(soldier, shift, bookings[soldier.ID])
+	}
+
+	commander, choice, found := s.pickSoldier(CommanderSlot, soldiers, bookings, func(soldier models.Soldier) bool {
+		return soldier.IsCommanding() && eligible(soldier)
+	})
+	if !found {
+		return ShiftAssignment{}, &UnassignedShift{ShiftID: shift.ID, Reason: NoAvailableCommanderReason}
+	}
+	assigned[commander.ID] = true
+	assignment := ShiftAssignment{
+		ShiftID:            shift.ID,
+		Commander:          commander,
+		AdditionalSoldiers: make([]models.Soldier, 0),
+		Choices:            []SlotChoice{choice},
+	}
+
+	for _, shortfall := range RoleShortfalls(requirement, assignment.soldiers()) {
+		for missing := shortfall.Required - shortfall.Assigned; missing > 0; missing-- {
+			soldier, choice, found := s.pickSoldier(shortfall.Role, soldiers, bookings, func(soldier models.Soldier) bool {
+				return soldier.HasRole(shortfall.Role) && eligible(soldier)
+			})
+			if !found {
+				break
+			}
+			assigned[soldier.ID] = true
+			assignment.AdditionalSoldiers = append(assignment.AdditionalSoldiers, soldier)
+			assignment.Choices = append(assignment.Choices, choice)
+		}
+	}
+	if shortfalls := RoleShortfalls(requirement, assignment.soldiers()); len(shortfalls) > 0 {
+		return ShiftAssignment{}, &UnassignedShift{ShiftID: shift.ID, Reason: UncoveredRolesReason, Shortfalls: shortfalls}
+	}
+	return assignment, nil
+}
+
+func (s *Solver) allows(soldier models.Soldier, shift models.Shift, bookedShifts []models.Shift) bool {
+	for _, constraint := range s.constraints {
+		if !constraint(soldier, shift, bookedShifts) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a ShiftAssignment) soldiers() []models.Soldier {
+	return append([]models.Soldier{a.Commander}, a.AdditionalSoldiers...)
+}
+
+// bookings maps between a soldier ID and the shifts the soldier is staffed in
+type bookings map[string][]models.Shift
+
+func newBookings(shifts []models.Shift) bookings {
+	b := make(bookings)
+	for _, shift := range shifts {
+		b.book(shift)
+	}
+	return b
+}
+
+func (b bookings) book(shift models.Shift) {
+	for _, soldier := range shift.Soldiers() {
+		b[soldier.ID] = append(b[soldier.ID], shift)
+	}
+}
+
+// pickSoldier returns the soldier with the lowest load score out of the soldiers matching predicate.
+// Soldiers are expected to be sorted, so ties are broken by soldier ID.
+func (s *Solver) pickSoldier(slot string, soldiers []models.Soldier, bookings bookings,
+	predicate func(models.Soldier) bool) (models.Soldier, SlotChoice, bool) {
+	var picked models.Soldier
+	var choice SlotChoice
+	for _, soldier := range soldiers {
+		if !predicate(soldier) {
+			continue
+		}
+		choice.Candidates++
+		score := s.scorer.Score(bookings[soldier.ID])
+		if choice.Candidates == 1 || score.Total < choice.Score.Total {
+			picked = soldier
+			choice.Score = score
+		}
+	}
+	if choice.Candidates == 0 {
+		return models.Soldier{}, SlotChoice{}, false
+	}
+	choice.Slot = slot
+	choice.SoldierID = picked.ID
+	return picked, choice, true
+}
+
+func sortedSoldiers(soldiers []models.Soldier) []models.Soldier {
+	sorted := append([]models.Soldier{}, soldiers...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
+
+func sortedUnstaffedShifts(shifts []models.Shift) []models.Shift {
+	unstaffed := make([]models.Shift, 0, len(shifts))
+	for _, shift := range shifts {
+		if !shift.IsStaffed() {
+			unstaffed = append(unstaffed, shift)
+		}
+	}
+	sort.Slice(unstaffed, func(i, j int) bool {
+		if unstaffed[i].StartTime.Equal(unstaffed[j].StartTime) {
+			return unstaffed[i].ID < unstaffed[j].ID
+		}
+		return unstaffed[i].StartTime.Before(unstaffed[j].StartTime)
+	})
+	return unstaffed
+}
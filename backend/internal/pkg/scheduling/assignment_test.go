@@ -0,0 +1,249 @@
+package scheduling_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	driverRole = models.SoldierRole{ID: "r1", Name: "Driver"}
+	medicRole  = models.SoldierRole{ID: "r2", Name: "Medic"}
+	rifleRole  = models.SoldierRole{ID: "r3", Name: "Rifleman"}
+
+	testSquadCommander = newTestSoldier("1", models.SquadCommanderPosition, rifleRole)
+	testDriver         = newTestSoldier("2", models.RegularSoldierPosition, driverRole)
+	testMedic          = newTestSoldier("3", models.RegularSoldierPosition, medicRole)
+	testDriverMedic    = newTestSoldier("4", models.RegularSoldierPosition, driverRole, medicRole)
+
+	testPatrolTemplate = models.ShiftTemplate{
+		ID:        "patrol",
+		Name:      "Patrol",
+		ShiftType: models.MotorizedPatrolShiftType,
+		PersonnelRequirement: models.PersonnelRequirement{
+			SoldierRoleToCount: map[string]int{"Driver": 1, "Medic": 1},
+		},
+	}
+)
+
+func newTestSoldier(id string, position models.SoldierPosition, roles ...models.SoldierRole) models.Soldier {
+	return models.Soldier{
+		ID:             id,
+		FirstName:      "Soldier",
+		LastName:       "Number" + id,
+		PersonalNumber: "123456" + id,
+		Position:       position,
+		Roles:          roles,
+	}
+}
+
+func newTestShift(id string, start time.Time, duration time.Duration, templateID string) models.Shift {
+	return models.Shift{
+		ID:              id,
+		Name:            "Shift " + id,
+		StartTime:       start,
+		EndTime:         start.Add(duration),
+		ShiftTemplateID: templateID,
+	}
+}
+
+func TestRoleShortfalls(t *testing.T) {
+	testCases := []struct {
+		name     string
+		soldiers []models.Soldier
+		expected []scheduling.RoleShortfall
+	}{
+		{
+			name:     "all roles covered",
+			soldiers: []models.Soldier{testDriver, testMedic},
+			expected: []scheduling.RoleShortfall{},
+		},
+		{
+			name:     "single soldier covers several roles",
+			soldiers: []models.Soldier{testDriverMedic},
+			expected: []scheduling.RoleShortfall{},
+		},
+		{
+			name:     "missing medic",
+			soldiers: []models.Soldier{testSquadCommander, testDriver},
+			expected: []scheduling.RoleShortfall{{Role: "Medic", Required: 1, Assigned: 0}},
+		},
+		{
+			name:     "no soldiers",
+			soldiers: nil,
+			expected: []scheduling.RoleShortfall{
+				{Role: "Driver", Required: 1, Assigned: 0},
+				{Role: "Medic", Required: 1, Assigned: 0},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Act
+			shortfalls := scheduling.RoleShortfalls(testPatrolTemplate.PersonnelRequirement, testCase.soldiers)
+
+			// Assert
+			assert.Equal(t, testCase.expected, shortfalls)
+		})
+	}
+}
+
+func TestNewSolver__nil_scorer(t *testing.T) {
+	// Act
+	solver, err := scheduling.NewSolver(nil, scheduling.NoOverlapConstraint)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, solver)
+}
+
+func TestNewSolver__nil_constraint(t *testing.T) {
+	// Act
+	solver, err := scheduling.NewSolver(newTestScorer(t), scheduling.NoOverlapConstraint, nil)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, solver)
+}
+
+func TestSolver_Solve__staffs_template_requirements(t *testing.T) {
+	// Arrange
+	solver, err := scheduling.NewSolver(newTestScorer(t), scheduling.NoOverlapConstraint)
+	require.NoError(t, err)
+	shift := newTestShift("s1", time.Date(2025, time.April, 9, 6, 0, 0, 0, time.UTC), 4*time.Hour, testPatrolTemplate.ID)
+
+	// Act
+	result := solver.Solve([]models.Shift{shift}, []models.Shift{shift},
+		[]models.Soldier{testMedic, testDriver, testSquadCommander}, []models.ShiftTemplate{testPatrolTemplate})
+
+	// Assert
+	assert.Empty(t, result.Unassigned)
+	require.Len(t, result.Assignments, 1)
+	assignment := result.Assignments[0]
+	assert.Equal(t, shift.ID, assignment.ShiftID)
+	assert.Equal(t, testSquadCommander, assignment.Commander)
+	assert.ElementsMatch(t, []models.Soldier{testDriver, testMedic}, assignment.AdditionalSoldiers)
+}
+
+func TestSolver_Solve__never_double_books(t *testing.T) {
+	// Arrange
+	solver, err := scheduling.NewSolver(newTestScorer(t), scheduling.NoOverlapConstraint)
+	require.NoError(t, err)
+	start := time.Date(2025, time.April, 9, 6, 0, 0, 0, time.UTC)
+	firstShift := newTestShift("s1", start, 4*time.Hour, "")
+	overlappingShift := newTestShift("s2", start.Add(2*time.Hour), 4*time.Hour, "")
+	anotherCommander := newTestSoldier("5", models.CommanderPosition, rifleRole)
+
+	// Act
+	result := solver.Solve([]models.Shift{overlappingShift, firstShift}, nil,
+		[]models.Soldier{testSquadCommander, anotherCommander}, nil)
+
+	// Assert
+	assert.Empty(t, result.Unassigned)
+	require.Len(t, result.Assignments, 2)
+	assert.Equal(t, firstShift.ID, result.Assignments[0].ShiftID)
+	assert.Equal(t, testSquadCommander, result.Assignments[0].Commander)
+	assert.Equal(t, overlappingShift.ID, result.Assignments[1].ShiftID)
+	assert.Equal(t, anotherCommander, result.Assignments[1].Commander)
+}
+
+func TestSolver_Solve__respects_existing_bookings(t *testing.T) {
+	// Arrange
+	solver, err := scheduling.NewSolver(newTestScorer(t), scheduling.NoOverlapConstraint)
+	require.NoError(t, err)
+	start := time.Date(2025, time.April, 9, 6, 0, 0, 0, time.UTC)
+	staffedShift := newTestShift("staffed", start, 4*time.Hour, "")
+	staffedShift.Commander = testSquadCommander
+	shift := newTestShift("s1", start.Add(time.Hour), time.Hour, "")
+
+	// Act
+	result := solver.Solve([]models.Shift{shift}, []models.Shift{staffedShift, shift},
+		[]models.Soldier{testSquadCommander}, nil)
+
+	// Assert
+	assert.Empty(t, result.Assignments)
+	require.Len(t, result.Unassigned, 1)
+	assert.Equal(t, scheduling.NoAvailableCommanderReason, result.Unassigned[0].Reason)
+}
+
+func TestSolver_Solve__uncovered_roles(t *testing.T) {
+	// Arrange
+	solver, err := scheduling.NewSolver(newTestScorer(t), scheduling.NoOverlapConstraint)
+	require.NoError(t, err)
+	shift := newTestShift("s1", time.Date(2025, time.April, 9, 6, 0, 0, 0, time.UTC), 4*time.Hour, testPatrolTemplate.ID)
+
+	// Act
+	result := solver.Solve([]models.Shift{shift}, nil,
+		[]models.Soldier{testSquadCommander, testDriver}, []models.ShiftTemplate{testPatrolTemplate})
+
+	// Assert
+	assert.Empty(t, result.Assignments)
+	require.Len(t, result.Unassigned, 1)
+	assert.Equal(t, scheduling.UncoveredRolesReason, result.Unassigned[0].Reason)
+	assert.Equal(t, []scheduling.RoleShortfall{{Role: "Medic", Required: 1, Assigned: 0}}, result.Unassigned[0].Shortfalls)
+}
+
+func TestSolver_Solve__skips_staffed_shifts(t *testing.T) {
+	// Arrange
+	solver, err := scheduling.NewSolver(newTestScorer(t), scheduling.NoOverlapConstraint)
+	require.NoError(t, err)
+	shift := newTestShift("s1", time.Date(2025, time.April, 9, 6, 0, 0, 0, time.UTC), 4*time.Hour, "")
+	shift.Commander = testSquadCommander
+
+	// Act
+	result := solver.Solve([]models.Shift{shift}, []models.Shift{shift}, []models.Soldier{testSquadCommander}, nil)
+
+	// Assert
+	assert.Empty(t, result.Assignments)
+	assert.Empty(t, result.Unassigned)
+}
+
+func TestSolver_Solve__prefers_lowest_load(t *testing.T) {
+	// Arrange
+	solver, err := scheduling.NewSolver(newTestScorer(t), scheduling.NoOverlapConstraint)
+	require.NoError(t, err)
+	start := time.Date(2025, time.April, 9, 6, 0, 0, 0, time.UTC)
+	pastNightShift := newTestShift("past-night", start.AddDate(0, 0, -2).Add(16*time.Hour), 8*time.Hour, "")
+	pastNightShift.Commander = testSquadCommander
+	pastDayShift := newTestShift("past-day", start.AddDate(0, 0, -2), 8*time.Hour, "")
+	pastDayShift.Commander = newTestSoldier("5", models.CommanderPosition)
+	shift := newTestShift("s1", start, 4*time.Hour, "")
+
+	// Act
+	result := solver.Solve([]models.Shift{shift}, []models.Shift{pastNightShift, pastDayShift, shift},
+		[]models.Soldier{testSquadCommander, pastDayShift.Commander}, nil)
+
+	// Assert
+	assert.Empty(t, result.Unassigned)
+	require.Len(t, result.Assignments, 1)
+	assert.Equal(t, pastDayShift.Commander, result.Assignments[0].Commander)
+	assert.Equal(t, []scheduling.SlotChoice{{
+		Slot:       scheduling.CommanderSlot,
+		SoldierID:  pastDayShift.Commander.ID,
+		Score:      scheduling.ScoreBreakdown{Hours: 8, Total: 8},
+		Candidates: 2,
+	}}, result.Assignments[0].Choices)
+}
+
+func TestSolver_Solve__spreads_load(t *testing.T) {
+	// Arrange
+	solver, err := scheduling.NewSolver(newTestScorer(t), scheduling.NoOverlapConstraint)
+	require.NoError(t, err)
+	start := time.Date(2025, time.April, 9, 6, 0, 0, 0, time.UTC)
+	firstShift := newTestShift("s1", start, 4*time.Hour, "")
+	secondShift := newTestShift("s2", start.Add(12*time.Hour), 4*time.Hour, "")
+	anotherCommander := newTestSoldier("5", models.CommanderPosition, rifleRole)
+
+	// Act
+	result := solver.Solve([]models.Shift{firstShift, secondShift}, nil,
+		[]models.Soldier{testSquadCommander, anotherCommander}, nil)
+
+	// Assert
+	require.Len(t, result.Assignments, 2)
+	assert.Equal(t, testSquadCommander, result.Assignments[0].Commander)
+	assert.Equal(t, anotherCommander, result.Assignments[1].Commander)
+}
@@ -0,0 +1,46 @@
+package scheduling
+
+import "brothers_in_batash/internal/pkg/models"
+
+// LeaveConflict describes a soldier staffed in a shift that takes place, even partially, during one of their leaves
+type LeaveConflict struct {
+	SoldierID string           `json:"soldierId"`
+	ShiftID   string           `json:"shiftId"`
+	LeaveID   string           `json:"leaveId"`
+	LeaveType models.LeaveType `json:"leaveType"`
+}
+
+// FindLeaveConflicts returns a LeaveConflict for every soldier staffed in shift who is on one of leaves during it
+func FindLeaveConflicts(shift models.Shift, leaves []models.Leave) []LeaveConflict {
+	conflicts := make([]LeaveConflict, 0)
+	for _, soldier := range shift.Soldiers() {
+		for _, leave := range leaves {
+			if leave.SoldierID == soldier.ID && leave.Overlaps(shift.StartTime, shift.EndTime) {
+				conflicts = append(conflicts, LeaveConflict{
+					SoldierID: soldier.ID,
+					ShiftID:   shift.ID,
+					LeaveID:   leave.ID,
+					LeaveType: leave.Type,
+				})
+			}
+		}
+	}
+	return conflicts
+}
+
+// AvailabilityConstraint returns a Constraint which never lets a soldier be booked into a shift taking place during
+// one of leaves
+func AvailabilityConstraint(leaves []models.Leave) Constraint {
+	leavesBySoldier := make(map[string][]models.Leave)
+	for _, leave := range leaves {
+		leavesBySoldier[leave.SoldierID] = append(leavesBySoldier[leave.SoldierID], leave)
+	}
+	return func(soldier models.Soldier, shift models.Shift, _ []models.Shift) bool {
+		for _, leave := range leavesBySoldier[soldier.ID] {
+			if leave.Overlaps(shift.StartTime, shift.EndTime) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,52 @@
+package scheduling_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindLeaveConflicts(t *testing.T) {
+	// Arrange
+	start := time.Date(2025, time.April, 9, 8, 0, 0, 0, time.UTC)
+	shift := newTestShift("s1", start, 4*time.Hour, "")
+	shift.Commander = testSquadCommander
+	shift.AdditionalSoldiers = []models.Soldier{testDriver}
+	leaves := []models.Leave{
+		{ID: "course", SoldierID: testDriver.ID, Type: models.CourseLeaveType,
+			StartTime: start.AddDate(0, 0, -1), EndTime: start.AddDate(0, 0, 3)},
+		{ID: "appointment", SoldierID: testSquadCommander.ID, Type: models.PartialDayLeaveType,
+			StartTime: start.Add(-2 * time.Hour), EndTime: start},
+		{ID: "other-soldier", SoldierID: testMedic.ID, Type: models.HomeLeaveType,
+			StartTime: start, EndTime: start.Add(time.Hour)},
+	}
+
+	// Act
+	conflicts := scheduling.FindLeaveConflicts(shift, leaves)
+
+	// Assert
+	assert.Equal(t, []scheduling.LeaveConflict{
+		{SoldierID: testDriver.ID, ShiftID: shift.ID, LeaveID: "course", LeaveType: models.CourseLeaveType},
+	}, conflicts)
+}
+
+func TestAvailabilityConstraint(t *testing.T) {
+	// Arrange
+	start := time.Date(2025, time.April, 9, 8, 0, 0, 0, time.UTC)
+	shift := newTestShift("s1", start, 4*time.Hour, "")
+	constraint := scheduling.AvailabilityConstraint([]models.Leave{
+		{ID: "leave", SoldierID: testDriver.ID, Type: models.HomeLeaveType,
+			StartTime: start.Add(3 * time.Hour), EndTime: start.AddDate(0, 0, 2)},
+	})
+
+	// Act
+	driverAllowed := constraint(testDriver, shift, nil)
+	medicAllowed := constraint(testMedic, shift, nil)
+
+	// Assert
+	assert.False(t, driverAllowed)
+	assert.True(t, medicAllowed)
+}
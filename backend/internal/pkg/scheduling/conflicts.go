@@ -0,0 +1,72 @@
+package scheduling
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"sort"
+	"time"
+)
+
+// Overlap describes a soldier that is staffed in two shifts whose times intersect
+type Overlap struct {
+	SoldierID string    `json:"soldierId"`
+	ShiftIDs  []string  `json:"shiftIds"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+}
+
+// FindOverlaps returns the overlaps between shift and the other shifts its soldiers are staffed in
+func FindOverlaps(shift models.Shift, existingShifts []models.Shift) []Overlap {
+	overlaps := make([]Overlap, 0)
+	for _, other := range existingShifts {
+		if other.ID == shift.ID || !Overlaps(shift, other) {
+			continue
+		}
+		overlaps = append(overlaps, sharedSoldiersOverlaps(shift, other)...)
+	}
+	return overlaps
+}
+
+// FindAllOverlaps returns every overlap between the given shifts, ordered by the overlap start time
+func FindAllOverlaps(shifts []models.Shift) []Overlap {
+	sorted := append([]models.Shift{}, shifts...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StartTime.Before(sorted[j].StartTime)
+	})
+	overlaps := make([]Overlap, 0)
+	for i, shift := range sorted {
+		for _, other := range sorted[i+1:] {
+			if !other.StartTime.Before(shift.EndTime) {
+				break
+			}
+			if other.ID != shift.ID && Overlaps(shift, other) {
+				overlaps = append(overlaps, sharedSoldiersOverlaps(shift, other)...)
+			}
+		}
+	}
+	sort.SliceStable(overlaps, func(i, j int) bool {
+		return overlaps[i].Start.Before(overlaps[j].Start)
+	})
+	return overlaps
+}
+
+func sharedSoldiersOverlaps(shift models.Shift, other models.Shift) []Overlap {
+	start, end := shift.StartTime, shift.EndTime
+	if other.StartTime.After(start) {
+		start = other.StartTime
+	}
+	if other.EndTime.Before(end) {
+		end = other.EndTime
+	}
+	overlaps := make([]Overlap, 0)
+	for _, soldier := range shift.Soldiers() {
+		if other.HasSoldier(soldier.ID) {
+			overlaps = append(overlaps, Overlap{
+				SoldierID: soldier.ID,
+				ShiftIDs:  []string{shift.ID, other.ID},
+				Start:     start,
+				End:       end,
+			})
+		}
+	}
+	return overlaps
+}
@@ -0,0 +1,65 @@
+package scheduling_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindOverlaps(t *testing.T) {
+	// Arrange
+	start := time.Date(2025, time.April, 9, 6, 0, 0, 0, time.UTC)
+	patrol := newTestShift("patrol", start, 4*time.Hour, "")
+	patrol.Commander = testSquadCommander
+	patrol.AdditionalSoldiers = []models.Soldier{testDriver}
+	staticPost := newTestShift("post", start.Add(2*time.Hour), 4*time.Hour, "")
+	staticPost.Commander = testDriver
+	backToBack := newTestShift("back-to-back", patrol.EndTime, 4*time.Hour, "")
+	backToBack.Commander = testSquadCommander
+
+	// Act
+	overlaps := scheduling.FindOverlaps(patrol, []models.Shift{patrol, staticPost, backToBack})
+
+	// Assert
+	assert.Equal(t, []scheduling.Overlap{{
+		SoldierID: testDriver.ID,
+		ShiftIDs:  []string{patrol.ID, staticPost.ID},
+		Start:     staticPost.StartTime,
+		End:       patrol.EndTime,
+	}}, overlaps)
+}
+
+func TestFindAllOverlaps(t *testing.T) {
+	// Arrange
+	start := time.Date(2025, time.April, 9, 6, 0, 0, 0, time.UTC)
+	longShift := newTestShift("long", start, 12*time.Hour, "")
+	longShift.Commander = testSquadCommander
+	morningShift := newTestShift("morning", start.Add(time.Hour), time.Hour, "")
+	morningShift.AdditionalSoldiers = []models.Soldier{testSquadCommander}
+	eveningShift := newTestShift("evening", start.Add(10*time.Hour), 4*time.Hour, "")
+	eveningShift.Commander = testSquadCommander
+	unrelatedShift := newTestShift("unrelated", start, 12*time.Hour, "")
+	unrelatedShift.Commander = testDriver
+
+	// Act
+	overlaps := scheduling.FindAllOverlaps([]models.Shift{eveningShift, unrelatedShift, morningShift, longShift})
+
+	// Assert
+	assert.Equal(t, []scheduling.Overlap{
+		{
+			SoldierID: testSquadCommander.ID,
+			ShiftIDs:  []string{longShift.ID, morningShift.ID},
+			Start:     morningShift.StartTime,
+			End:       morningShift.EndTime,
+		},
+		{
+			SoldierID: testSquadCommander.ID,
+			ShiftIDs:  []string{longShift.ID, eveningShift.ID},
+			Start:     eveningShift.StartTime,
+			End:       longShift.EndTime,
+		},
+	}, overlaps)
+}
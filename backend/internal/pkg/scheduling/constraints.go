@@ -0,0 +1,21 @@
+package scheduling
+
+import "brothers_in_batash/internal/pkg/models"
+
+// Constraint decides whether soldier may be assigned to shift, given the shifts soldier is already booked to
+type Constraint func(soldier models.Soldier, shift models.Shift, bookedShifts []models.Shift) bool
+
+// NoOverlapConstraint never lets a soldier be booked into two shifts that overlap in time
+func NoOverlapConstraint(_ models.Soldier, shift models.Shift, bookedShifts []models.Shift) bool {
+	for _, bookedShift := range bookedShifts {
+		if bookedShift.ID != shift.ID && Overlaps(shift, bookedShift) {
+			return false
+		}
+	}
+	return true
+}
+
+// Overlaps indicates whether the time ranges of both shifts intersect. Back-to-back shifts do not overlap.
+func Overlaps(a, b models.Shift) bool {
+	return a.StartTime.Before(b.EndTime) && b.StartTime.Before(a.EndTime)
+}
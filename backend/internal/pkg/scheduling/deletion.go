@@ -0,0 +1,38 @@
+package scheduling
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"sort"
+	"time"
+)
+
+// SoldierDeletePolicy decides what happens to the upcoming shifts of a soldier who is deleted
+type SoldierDeletePolicy string
+
+const (
+	// BlockSoldierDeletePolicy refuses to delete soldiers who are staffed in upcoming shifts
+	BlockSoldierDeletePolicy SoldierDeletePolicy = "block"
+	// CascadeSoldierDeletePolicy unassigns deleted soldiers from their upcoming shifts
+	CascadeSoldierDeletePolicy SoldierDeletePolicy = "cascade"
+)
+
+func (p SoldierDeletePolicy) IsValid() bool {
+	return p == BlockSoldierDeletePolicy || p == CascadeSoldierDeletePolicy
+}
+
+// UpcomingShifts returns the shifts soldierID is staffed in that start after now, ordered by their start time
+func UpcomingShifts(shifts []models.Shift, soldierID string, now time.Time) []models.Shift {
+	upcoming := make([]models.Shift, 0)
+	for _, shift := range shifts {
+		if shift.StartTime.After(now) && shift.HasSoldier(soldierID) {
+			upcoming = append(upcoming, shift)
+		}
+	}
+	sort.Slice(upcoming, func(i, j int) bool {
+		if upcoming[i].StartTime.Equal(upcoming[j].StartTime) {
+			return upcoming[i].ID < upcoming[j].ID
+		}
+		return upcoming[i].StartTime.Before(upcoming[j].StartTime)
+	})
+	return upcoming
+}
@@ -0,0 +1,32 @@
+package scheduling_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpcomingShifts(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, time.April, 9, 12, 0, 0, 0, time.UTC)
+	pastShift := newTestShift("past", now.Add(-4*time.Hour), 2*time.Hour, "")
+	pastShift.Commander = testDriver
+	ongoingShift := newTestShift("ongoing", now.Add(-time.Hour), 2*time.Hour, "")
+	ongoingShift.Commander = testDriver
+	laterShift := newTestShift("later", now.Add(6*time.Hour), 2*time.Hour, "")
+	laterShift.AdditionalSoldiers = []models.Soldier{testDriver}
+	soonerShift := newTestShift("sooner", now.Add(2*time.Hour), 2*time.Hour, "")
+	soonerShift.Commander = testDriver
+	otherSoldierShift := newTestShift("other", now.Add(2*time.Hour), 2*time.Hour, "")
+	otherSoldierShift.Commander = testSquadCommander
+
+	// Act
+	upcoming := scheduling.UpcomingShifts(
+		[]models.Shift{pastShift, ongoingShift, laterShift, soonerShift, otherSoldierShift}, testDriver.ID, now)
+
+	// Assert
+	assert.Equal(t, []models.Shift{soonerShift, laterShift}, upcoming)
+}
@@ -0,0 +1,80 @@
+package scheduling
+
+import "time"
+
+const (
+	// hebrewYearOffset is the difference between a Gregorian year and the Hebrew year starting in its autumn, minus one
+	hebrewYearOffset = 3760
+	// hebrewEpoch shifts the days elapsed since the Hebrew calendar epoch into days counted from 1 January of the
+	// Gregorian year 1
+	hebrewEpoch = -1373429
+)
+
+// Offsets of the Jewish holidays from Rosh Hashana, as they are observed in Israel. Pesach and Shavuot precede the Rosh
+// Hashana of the same Gregorian year - the months between Nisan and Tishrei have a fixed length, so are these offsets.
+var holidayOffsetsFromRoshHashana = []int{
+	-163, // Pesach
+	-157, // Shvi'i shel Pesach
+	-113, // Shavuot
+	0,    // Rosh Hashana
+	1,    // Rosh Hashana
+	9,    // Yom Kippur
+	14,   // Sukkot
+	21,   // Shmini Atzeret
+}
+
+// JewishHolidays returns the dates of the Jewish holidays, as they are observed in Israel, falling in the Gregorian year
+func JewishHolidays(year int) []time.Time {
+	roshHashana := roshHashanaDate(year + hebrewYearOffset + 1)
+	holidays := make([]time.Time, 0, len(holidayOffsetsFromRoshHashana))
+	for _, offset := range holidayOffsetsFromRoshHashana {
+		holidays = append(holidays, roshHashana.AddDate(0, 0, offset))
+	}
+	return holidays
+}
+
+// IsJewishHoliday indicates whether date, in its own location, is one of the JewishHolidays
+func IsJewishHoliday(date time.Time) bool {
+	for _, holiday := range JewishHolidays(date.Year()) {
+		if holiday.Month() == date.Month() && holiday.Day() == date.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// roshHashanaDate returns the Gregorian date of 1 Tishrei of the Hebrew year
+func roshHashanaDate(hebrewYear int) time.Time {
+	return time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, hebrewElapsedDays(hebrewYear)+hebrewEpoch)
+}
+
+// hebrewElapsedDays returns the number of days from the Hebrew calendar epoch to Rosh Hashana of the Hebrew year. Rosh
+// Hashana falls on the day of the molad (the mean new moon) of Tishrei, unless one of the postponement rules applies.
+func hebrewElapsedDays(hebrewYear int) int {
+	const (
+		partsPerHour = 1080
+		partsPerDay  = 24 * partsPerHour
+	)
+	monthsElapsed := 235*((hebrewYear-1)/19) + 12*((hebrewYear-1)%19) + (7*((hebrewYear-1)%19)+1)/19
+	partsElapsed := 204 + 793*(monthsElapsed%partsPerHour)
+	hoursElapsed := 5 + 12*monthsElapsed + 793*(monthsElapsed/partsPerHour) + partsElapsed/partsPerHour
+	moladDay := 1 + 29*monthsElapsed + hoursElapsed/24
+	moladParts := partsPerHour*(hoursElapsed%24) + partsElapsed%partsPerHour
+
+	day := moladDay
+	if moladParts >= partsPerDay*3/4 ||
+		(moladDay%7 == 2 && moladParts >= 9*partsPerHour+204 && !isHebrewLeapYear(hebrewYear)) ||
+		(moladDay%7 == 1 && moladParts >= 15*partsPerHour+589 && isHebrewLeapYear(hebrewYear-1)) {
+		day++
+	}
+	// Rosh Hashana never falls on a Sunday, a Wednesday or a Friday
+	if day%7 == 0 || day%7 == 3 || day%7 == 5 {
+		day++
+	}
+	return day
+}
+
+// isHebrewLeapYear indicates whether the Hebrew year has 13 months - 7 out of every 19 years do
+func isHebrewLeapYear(hebrewYear int) bool {
+	return (7*hebrewYear+1)%19 < 7
+}
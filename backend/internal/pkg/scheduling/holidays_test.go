@@ -0,0 +1,41 @@
+package scheduling_test
+
+import (
+	"brothers_in_batash/internal/pkg/scheduling"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJewishHolidays(t *testing.T) {
+	testCases := map[int][]string{
+		2024: {"2024-04-23", "2024-04-29", "2024-06-12", "2024-10-03", "2024-10-04", "2024-10-12", "2024-10-17", "2024-10-24"},
+		2025: {"2025-04-13", "2025-04-19", "2025-06-02", "2025-09-23", "2025-09-24", "2025-10-02", "2025-10-07", "2025-10-14"},
+		2026: {"2026-04-02", "2026-04-08", "2026-05-22", "2026-09-12", "2026-09-13", "2026-09-21", "2026-09-26", "2026-10-03"},
+		2027: {"2027-04-22", "2027-04-28", "2027-06-11", "2027-10-02", "2027-10-03", "2027-10-11", "2027-10-16", "2027-10-23"},
+	}
+	for year, expected := range testCases {
+		t.Run(strconv.Itoa(year), func(t *testing.T) {
+			// Act
+			holidays := scheduling.JewishHolidays(year)
+
+			// Assert
+			formatted := make([]string, 0, len(holidays))
+			for _, holiday := range holidays {
+				formatted = append(formatted, holiday.Format("2006-01-02"))
+			}
+			assert.Equal(t, expected, formatted)
+		})
+	}
+}
+
+func TestIsJewishHoliday(t *testing.T) {
+	israelZone := time.FixedZone("IDT", 3*60*60)
+
+	// Assert
+	assert.True(t, scheduling.IsJewishHoliday(time.Date(2026, time.September, 21, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, scheduling.IsJewishHoliday(time.Date(2026, time.September, 21, 23, 30, 0, 0, israelZone)))
+	assert.False(t, scheduling.IsJewishHoliday(time.Date(2026, time.September, 22, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,81 @@
+package scheduling
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"sort"
+	"time"
+)
+
+const (
+	// ShabbatStartHour is the hour on Friday from which a shift is considered a Shabbat shift
+	ShabbatStartHour = 16
+	// ShabbatEndHour is the hour on Saturday until which a shift is considered a Shabbat shift
+	ShabbatEndHour = 20
+)
+
+// SoldierLoad is the duty load a soldier accumulated over a set of shifts
+type SoldierLoad struct {
+	SoldierID     string                   `json:"soldierId"`
+	Shifts        int                      `json:"shifts"`
+	TotalHours    float64                  `json:"totalHours"`
+	NightHours    float64                  `json:"nightHours"`
+	ShabbatShifts int                      `json:"shabbatShifts"`
+	ShiftsPerType map[models.ShiftType]int `json:"shiftsPerType"`
+}
+
+func newSoldierLoad(soldierID string) SoldierLoad {
+	return SoldierLoad{SoldierID: soldierID, ShiftsPerType: make(map[models.ShiftType]int)}
+}
+
+func (l *SoldierLoad) add(shift models.Shift) {
+	l.Shifts++
+	l.TotalHours += shift.EndTime.Sub(shift.StartTime).Hours()
+	l.NightHours += NightDuration(shift.StartTime, shift.EndTime).Hours()
+	if IsShabbatShift(shift) {
+		l.ShabbatShifts++
+	}
+	l.ShiftsPerType[shift.Type]++
+}
+
+// ComputeLoads returns the load of every one of soldiers over shifts, sorted by soldier ID.
+// Soldiers who are not staffed in any of the shifts get an empty load.
+func ComputeLoads(soldiers []models.Soldier, shifts []models.Shift) []SoldierLoad {
+	loads := make(map[string]*SoldierLoad, len(soldiers))
+	for _, soldier := range soldiers {
+		load := newSoldierLoad(soldier.ID)
+		loads[soldier.ID] = &load
+	}
+	for _, shift := range shifts {
+		for _, soldier := range shift.Soldiers() {
+			if load, found := loads[soldier.ID]; found {
+				load.add(shift)
+			}
+		}
+	}
+
+	sorted := make([]SoldierLoad, 0, len(loads))
+	for _, load := range loads {
+		sorted = append(sorted, *load)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].SoldierID < sorted[j].SoldierID
+	})
+	return sorted
+}
+
+// IsShabbatShift indicates whether any part of shift takes place between ShabbatStartHour on Friday and
+// ShabbatEndHour on Saturday
+func IsShabbatShift(shift models.Shift) bool {
+	// A Shabbat starting on the day before start may still be running when start is reached
+	for day := truncateToDate(shift.StartTime).AddDate(0, 0, -1); day.Before(shift.EndTime); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Friday {
+			continue
+		}
+		shabbatStart := time.Date(day.Year(), day.Month(), day.Day(), ShabbatStartHour, 0, 0, 0, day.Location())
+		shabbatEnd := time.Date(day.Year(), day.Month(), day.Day()+1, ShabbatEndHour, 0, 0, 0, day.Location())
+		if intersection(shift.StartTime, shift.EndTime, shabbatStart, shabbatEnd) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package scheduling_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsShabbatShift(t *testing.T) {
+	friday := time.Date(2025, time.April, 11, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		start    time.Time
+		duration time.Duration
+		expected bool
+	}{
+		{name: "friday morning", start: friday.Add(8 * time.Hour), duration: 4 * time.Hour, expected: false},
+		{name: "friday afternoon into shabbat", start: friday.Add(14 * time.Hour), duration: 4 * time.Hour, expected: true},
+		{name: "saturday evening", start: friday.Add(42 * time.Hour), duration: 4 * time.Hour, expected: true},
+		{name: "after shabbat ends", start: friday.Add(44 * time.Hour), duration: 4 * time.Hour, expected: false},
+		{name: "wednesday", start: friday.AddDate(0, 0, -2), duration: 8 * time.Hour, expected: false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			shift := newTestShift("s1", testCase.start, testCase.duration, "")
+
+			// Act
+			isShabbat := scheduling.IsShabbatShift(shift)
+
+			// Assert
+			assert.Equal(t, testCase.expected, isShabbat)
+		})
+	}
+}
+
+func TestComputeLoads(t *testing.T) {
+	// Arrange
+	nightShift := newTestShift("night", time.Date(2025, time.April, 9, 22, 0, 0, 0, time.UTC), 8*time.Hour, "")
+	nightShift.Type = models.StaticPostShiftType
+	nightShift.Commander = testSquadCommander
+	nightShift.AdditionalSoldiers = []models.Soldier{testDriver}
+	shabbatShift := newTestShift("shabbat", time.Date(2025, time.April, 12, 8, 0, 0, 0, time.UTC), 4*time.Hour, "")
+	shabbatShift.Type = models.MotorizedPatrolShiftType
+	shabbatShift.Commander = testSquadCommander
+	unknownSoldierShift := newTestShift("unknown", time.Date(2025, time.April, 13, 8, 0, 0, 0, time.UTC), 4*time.Hour, "")
+	unknownSoldierShift.Commander = newTestSoldier("unknown", models.CommanderPosition)
+
+	// Act
+	loads := scheduling.ComputeLoads([]models.Soldier{testMedic, testDriver, testSquadCommander},
+		[]models.Shift{nightShift, shabbatShift, unknownSoldierShift})
+
+	// Assert
+	require.Len(t, loads, 3)
+	assert.Equal(t, scheduling.SoldierLoad{
+		SoldierID:     testSquadCommander.ID,
+		Shifts:        2,
+		TotalHours:    12,
+		NightHours:    8,
+		ShabbatShifts: 1,
+		ShiftsPerType: map[models.ShiftType]int{models.StaticPostShiftType: 1, models.MotorizedPatrolShiftType: 1},
+	}, loads[0])
+	assert.Equal(t, scheduling.SoldierLoad{
+		SoldierID:     testDriver.ID,
+		Shifts:        1,
+		TotalHours:    8,
+		NightHours:    8,
+		ShiftsPerType: map[models.ShiftType]int{models.StaticPostShiftType: 1},
+	}, loads[1])
+	assert.Equal(t, scheduling.SoldierLoad{
+		SoldierID:     testMedic.ID,
+		ShiftsPerType: map[models.ShiftType]int{},
+	}, loads[2])
+}
@@ -0,0 +1,153 @@
+package scheduling
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	NightStartHour = 22
+	NightEndHour   = 6
+)
+
+// RestRule entitles soldiers to at least MinRest off after a shift the rule applies to
+type RestRule struct {
+	// ShiftTypes the rule applies to. An empty slice applies the rule to every shift type.
+	ShiftTypes []models.ShiftType
+	// NightOnly limits the rule to shifts that take place during the night
+	NightOnly bool
+	MinRest   time.Duration
+}
+
+// RestViolation describes a soldier that does not get enough rest between ShiftID and ConflictingShiftID.
+// Overlapping shifts are reported as an Overlap rather than as a RestViolation.
+type RestViolation struct {
+	SoldierID          string        `json:"soldierId"`
+	ShiftID            string        `json:"shiftId"`
+	ConflictingShiftID string        `json:"conflictingShiftId"`
+	RequiredRest       time.Duration `json:"requiredRest"`
+	ActualRest         time.Duration `json:"actualRest"`
+}
+
+type RestPolicy struct {
+	rules []RestRule
+}
+
+func NewRestPolicy(rules ...RestRule) (*RestPolicy, error) {
+	for _, rule := range rules {
+		if rule.MinRest < 0 {
+			return nil, errors.New("rest rule with a negative minimum rest")
+		}
+	}
+	return &RestPolicy{rules: rules}, nil
+}
+
+// MinRestAfter returns the rest a soldier is entitled to after shift - the longest rest out of all applicable rules
+func (p *RestPolicy) MinRestAfter(shift models.Shift) time.Duration {
+	var minRest time.Duration
+	for _, rule := range p.rules {
+		if rule.appliesTo(shift) && rule.MinRest > minRest {
+			minRest = rule.MinRest
+		}
+	}
+	return minRest
+}
+
+// Violations checks every soldier staffed in shift against the other shifts the soldier is staffed in
+func (p *RestPolicy) Violations(shift models.Shift, existingShifts []models.Shift) []RestViolation {
+	violations := make([]RestViolation, 0)
+	for _, soldier := range shift.Soldiers() {
+		for _, other := range existingShifts {
+			if other.ID == shift.ID || !other.HasSoldier(soldier.ID) {
+				continue
+			}
+			if violation, violated := p.check(soldier.ID, shift, other); violated {
+				violations = append(violations, violation)
+			}
+		}
+	}
+	return violations
+}
+
+// Allows is a Constraint which keeps soldiers from being assigned to shifts they are not rested enough for
+func (p *RestPolicy) Allows(soldier models.Soldier, shift models.Shift, bookedShifts []models.Shift) bool {
+	for _, bookedShift := range bookedShifts {
+		if bookedShift.ID == shift.ID {
+			continue
+		}
+		if _, violated := p.check(soldier.ID, shift, bookedShift); violated {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *RestPolicy) check(soldierID string, shift models.Shift, other models.Shift) (RestViolation, bool) {
+	if Overlaps(shift, other) {
+		return RestViolation{}, false
+	}
+	earlier, later := shift, other
+	if later.StartTime.Before(earlier.StartTime) {
+		earlier, later = later, earlier
+	}
+	requiredRest := p.MinRestAfter(earlier)
+	actualRest := later.StartTime.Sub(earlier.EndTime)
+	if actualRest >= requiredRest {
+		return RestViolation{}, false
+	}
+	return RestViolation{
+		SoldierID:          soldierID,
+		ShiftID:            shift.ID,
+		ConflictingShiftID: other.ID,
+		RequiredRest:       requiredRest,
+		ActualRest:         actualRest,
+	}, true
+}
+
+func (r RestRule) appliesTo(shift models.Shift) bool {
+	if r.NightOnly && !IsNightShift(shift) {
+		return false
+	}
+	if len(r.ShiftTypes) == 0 {
+		return true
+	}
+	for _, shiftType := range r.ShiftTypes {
+		if shiftType == shift.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNightShift indicates whether any part of shift takes place between NightStartHour and NightEndHour
+func IsNightShift(shift models.Shift) bool {
+	return NightDuration(shift.StartTime, shift.EndTime) > 0
+}
+
+// NightDuration returns how much of the [start, end) time range takes place between NightStartHour and NightEndHour
+func NightDuration(start, end time.Time) time.Duration {
+	var nightDuration time.Duration
+	// A night starting on the day before start may still be running when start is reached
+	for day := truncateToDate(start).AddDate(0, 0, -1); day.Before(end); day = day.AddDate(0, 0, 1) {
+		nightStart := time.Date(day.Year(), day.Month(), day.Day(), NightStartHour, 0, 0, 0, day.Location())
+		nightEnd := time.Date(day.Year(), day.Month(), day.Day()+1, NightEndHour, 0, 0, 0, day.Location())
+		nightDuration += intersection(start, end, nightStart, nightEnd)
+	}
+	return nightDuration
+}
+
+func intersection(aStart, aEnd, bStart, bEnd time.Time) time.Duration {
+	start, end := aStart, aEnd
+	if bStart.After(start) {
+		start = bStart
+	}
+	if bEnd.Before(end) {
+		end = bEnd
+	}
+	if !start.Before(end) {
+		return 0
+	}
+	return end.Sub(start)
+}
@@ -0,0 +1,138 @@
+package scheduling_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRestPolicy(t *testing.T) *scheduling.RestPolicy {
+	restPolicy, err := scheduling.NewRestPolicy(
+		scheduling.RestRule{MinRest: 4 * time.Hour},
+		scheduling.RestRule{NightOnly: true, ShiftTypes: []models.ShiftType{models.StaticPostShiftType}, MinRest: 8 * time.Hour},
+	)
+	require.NoError(t, err)
+	return restPolicy
+}
+
+func TestNewRestPolicy__negative_rest(t *testing.T) {
+	// Act
+	restPolicy, err := scheduling.NewRestPolicy(scheduling.RestRule{MinRest: -time.Hour})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, restPolicy)
+}
+
+func TestRestPolicy_MinRestAfter(t *testing.T) {
+	day := time.Date(2025, time.April, 9, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		shift    models.Shift
+		expected time.Duration
+	}{
+		{
+			name:     "day static post",
+			shift:    models.Shift{Type: models.StaticPostShiftType, StartTime: day.Add(8 * time.Hour), EndTime: day.Add(16 * time.Hour)},
+			expected: 4 * time.Hour,
+		},
+		{
+			name:     "night static post",
+			shift:    models.Shift{Type: models.StaticPostShiftType, StartTime: day.Add(23 * time.Hour), EndTime: day.Add(31 * time.Hour)},
+			expected: 8 * time.Hour,
+		},
+		{
+			name:     "night patrol",
+			shift:    models.Shift{Type: models.MotorizedPatrolShiftType, StartTime: day.Add(2 * time.Hour), EndTime: day.Add(4 * time.Hour)},
+			expected: 4 * time.Hour,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Act
+			minRest := newTestRestPolicy(t).MinRestAfter(testCase.shift)
+
+			// Assert
+			assert.Equal(t, testCase.expected, minRest)
+		})
+	}
+}
+
+func TestRestPolicy_Violations__back_to_back_shifts(t *testing.T) {
+	// Arrange
+	start := time.Date(2025, time.April, 9, 6, 0, 0, 0, time.UTC)
+	shift := newTestShift("s1", start, 12*time.Hour, "")
+	shift.Commander = testSquadCommander
+	nextShift := newTestShift("s2", shift.EndTime, 12*time.Hour, "")
+	nextShift.Commander = testSquadCommander
+	otherSoldierShift := newTestShift("s3", shift.EndTime, 12*time.Hour, "")
+	otherSoldierShift.Commander = testDriver
+
+	// Act
+	violations := newTestRestPolicy(t).Violations(shift, []models.Shift{shift, nextShift, otherSoldierShift})
+
+	// Assert
+	assert.Equal(t, []scheduling.RestViolation{{
+		SoldierID:          testSquadCommander.ID,
+		ShiftID:            shift.ID,
+		ConflictingShiftID: nextShift.ID,
+		RequiredRest:       4 * time.Hour,
+		ActualRest:         0,
+	}}, violations)
+}
+
+func TestRestPolicy_Violations__enough_rest(t *testing.T) {
+	// Arrange
+	start := time.Date(2025, time.April, 9, 6, 0, 0, 0, time.UTC)
+	shift := newTestShift("s1", start, 4*time.Hour, "")
+	shift.Commander = testSquadCommander
+	previousShift := newTestShift("s2", start.Add(-8*time.Hour), 4*time.Hour, "")
+	previousShift.Commander = testSquadCommander
+
+	// Act
+	violations := newTestRestPolicy(t).Violations(shift, []models.Shift{previousShift})
+
+	// Assert
+	assert.Empty(t, violations)
+}
+
+func TestRestPolicy_Allows(t *testing.T) {
+	// Arrange
+	nightPost := newTestShift("night", time.Date(2025, time.April, 9, 22, 0, 0, 0, time.UTC), 8*time.Hour, "")
+	nightPost.Type = models.StaticPostShiftType
+	morningShift := newTestShift("morning", nightPost.EndTime.Add(5*time.Hour), 4*time.Hour, "")
+	eveningShift := newTestShift("evening", nightPost.EndTime.Add(9*time.Hour), 4*time.Hour, "")
+	restPolicy := newTestRestPolicy(t)
+
+	// Act & Assert
+	assert.False(t, restPolicy.Allows(testSquadCommander, morningShift, []models.Shift{nightPost}))
+	assert.True(t, restPolicy.Allows(testSquadCommander, eveningShift, []models.Shift{nightPost}))
+}
+
+func TestNightDuration(t *testing.T) {
+	day := time.Date(2025, time.April, 9, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		start    time.Time
+		end      time.Time
+		expected time.Duration
+	}{
+		{name: "day time", start: day.Add(8 * time.Hour), end: day.Add(16 * time.Hour), expected: 0},
+		{name: "early morning", start: day.Add(4 * time.Hour), end: day.Add(8 * time.Hour), expected: 2 * time.Hour},
+		{name: "whole night", start: day.Add(20 * time.Hour), end: day.Add(32 * time.Hour), expected: 8 * time.Hour},
+		{name: "two nights", start: day, end: day.Add(48 * time.Hour), expected: 6*time.Hour + 8*time.Hour + 2*time.Hour},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Act
+			nightDuration := scheduling.NightDuration(testCase.start, testCase.end)
+
+			// Assert
+			assert.Equal(t, testCase.expected, nightDuration)
+		})
+	}
+}
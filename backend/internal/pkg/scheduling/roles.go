@@ -0,0 +1,48 @@
+package scheduling
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"sort"
+)
+
+// StaffingMode decides how shifts that do not cover their template's PersonnelRequirement are handled
+type StaffingMode string
+
+const (
+	// StrictStaffingMode rejects shifts whose soldiers do not cover the shift's template PersonnelRequirement
+	StrictStaffingMode StaffingMode = "strict"
+	// WarnStaffingMode saves such shifts, flagged as understaffed
+	WarnStaffingMode StaffingMode = "warn"
+)
+
+func (m StaffingMode) IsValid() bool {
+	return m == StrictStaffingMode || m == WarnStaffingMode
+}
+
+// RoleShortfall describes a role required by a PersonnelRequirement which is not covered by the assigned soldiers
+type RoleShortfall struct {
+	Role     string `json:"role"`
+	Required int    `json:"required"`
+	Assigned int    `json:"assigned"`
+}
+
+// RoleShortfalls checks soldiers against requirement and returns the uncovered roles, sorted by role name.
+// A soldier holding several roles counts towards each one of them.
+func RoleShortfalls(requirement models.PersonnelRequirement, soldiers []models.Soldier) []RoleShortfall {
+	shortfalls := make([]RoleShortfall, 0)
+	for role, required := range requirement.SoldierRoleToCount {
+		assigned := 0
+		for _, soldier := range soldiers {
+			if soldier.HasRole(role) {
+				assigned++
+			}
+		}
+		if assigned < required {
+			shortfalls = append(shortfalls, RoleShortfall{Role: role, Required: required, Assigned: assigned})
+		}
+	}
+	sort.Slice(shortfalls, func(i, j int) bool {
+		return shortfalls[i].Role < shortfalls[j].Role
+	})
+	return shortfalls
+}
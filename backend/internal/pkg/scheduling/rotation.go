@@ -0,0 +1,63 @@
+package scheduling
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"fmt"
+	"time"
+)
+
+// RotationLeaves returns the home periods of rotations which intersect the [from, to) time range, as a home leave
+// per soldier. This lets rotations feed the same availability checks stored leaves do.
+func RotationLeaves(rotations []models.Rotation, from, to time.Time) []models.Leave {
+	leaves := make([]models.Leave, 0)
+	for _, rotation := range rotations {
+		start := truncateToDate(rotation.StartDate)
+		cycle := rotation.CycleLength()
+		// Begin with the cycle which may still be running when from is reached
+		firstCycle := 0
+		if from.After(start) {
+			firstCycle = int(from.Sub(start).Hours()/24)/cycle - 1
+			if firstCycle < 0 {
+				firstCycle = 0
+			}
+		}
+		for cycleIndex := firstCycle; ; cycleIndex++ {
+			homeStart := start.AddDate(0, 0, cycleIndex*cycle+rotation.OnBaseDays)
+			homeEnd := homeStart.AddDate(0, 0, rotation.HomeDays)
+			if !homeStart.Before(to) {
+				break
+			}
+			if !homeEnd.After(from) {
+				continue
+			}
+			for _, soldierID := range rotation.SoldierIDs {
+				leaves = append(leaves, models.Leave{
+					ID:        fmt.Sprintf("rotation-%s-%d", rotation.ID, cycleIndex),
+					SoldierID: soldierID,
+					Type:      models.HomeLeaveType,
+					StartTime: homeStart,
+					EndTime:   homeEnd,
+				})
+			}
+		}
+	}
+	return leaves
+}
+
+// IsOnBase indicates whether the soldier is on base on date. A soldier is on base unless one of rotations sends them
+// home, or they are on a leave lasting more than a partial day.
+func IsOnBase(soldierID string, date time.Time, rotations []models.Rotation, leaves []models.Leave) bool {
+	for _, rotation := range rotations {
+		if rotation.HasSoldier(soldierID) && !rotation.IsOnBase(date) {
+			return false
+		}
+	}
+	dayStart := truncateToDate(date)
+	for _, leave := range leaves {
+		if leave.SoldierID == soldierID && leave.Type != models.PartialDayLeaveType &&
+			leave.Overlaps(dayStart, dayStart.AddDate(0, 0, 1)) {
+			return false
+		}
+	}
+	return true
+}
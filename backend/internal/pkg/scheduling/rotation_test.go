@@ -0,0 +1,87 @@
+package scheduling_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testRotation is an 11/3 rotation - on base April 1st-11th, home April 12th-14th, back on base April 15th, and so on
+var testRotation = models.Rotation{
+	ID:         "11-3",
+	Name:       "Alpha squad",
+	SoldierIDs: []string{testDriver.ID, testMedic.ID},
+	OnBaseDays: 11,
+	HomeDays:   3,
+	StartDate:  time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestRotationLeaves(t *testing.T) {
+	// Act
+	leaves := scheduling.RotationLeaves([]models.Rotation{testRotation},
+		time.Date(2025, time.April, 10, 0, 0, 0, 0, time.UTC), time.Date(2025, time.April, 20, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	homeStart := time.Date(2025, time.April, 12, 0, 0, 0, 0, time.UTC)
+	homeEnd := time.Date(2025, time.April, 15, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []models.Leave{
+		{ID: "rotation-11-3-0", SoldierID: testDriver.ID, Type: models.HomeLeaveType, StartTime: homeStart, EndTime: homeEnd},
+		{ID: "rotation-11-3-0", SoldierID: testMedic.ID, Type: models.HomeLeaveType, StartTime: homeStart, EndTime: homeEnd},
+	}, leaves)
+}
+
+func TestRotationLeaves__later_cycle(t *testing.T) {
+	// Act
+	leaves := scheduling.RotationLeaves([]models.Rotation{testRotation},
+		time.Date(2025, time.April, 27, 12, 0, 0, 0, time.UTC), time.Date(2025, time.April, 27, 20, 0, 0, 0, time.UTC))
+
+	// Assert
+	assert.Len(t, leaves, 2)
+	for _, leave := range leaves {
+		assert.Equal(t, time.Date(2025, time.April, 26, 0, 0, 0, 0, time.UTC), leave.StartTime)
+		assert.Equal(t, time.Date(2025, time.April, 29, 0, 0, 0, 0, time.UTC), leave.EndTime)
+	}
+}
+
+func TestIsOnBase(t *testing.T) {
+	sickLeave := models.Leave{ID: "sick", SoldierID: testSquadCommander.ID, Type: models.SickLeaveType,
+		StartTime: time.Date(2025, time.April, 5, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, time.April, 7, 0, 0, 0, 0, time.UTC)}
+	appointment := models.Leave{ID: "appointment", SoldierID: testSquadCommander.ID, Type: models.PartialDayLeaveType,
+		StartTime: time.Date(2025, time.April, 8, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, time.April, 8, 12, 0, 0, 0, time.UTC)}
+	testCases := []struct {
+		name      string
+		soldierID string
+		date      time.Time
+		expected  bool
+	}{
+		{name: "before rotation starts", soldierID: testDriver.ID, date: time.Date(2025, time.March, 30, 0, 0, 0, 0, time.UTC),
+			expected: true},
+		{name: "on base days", soldierID: testDriver.ID, date: time.Date(2025, time.April, 11, 0, 0, 0, 0, time.UTC),
+			expected: true},
+		{name: "home days", soldierID: testDriver.ID, date: time.Date(2025, time.April, 12, 0, 0, 0, 0, time.UTC),
+			expected: false},
+		{name: "next cycle", soldierID: testDriver.ID, date: time.Date(2025, time.April, 15, 0, 0, 0, 0, time.UTC),
+			expected: true},
+		{name: "not in rotation", soldierID: testSquadCommander.ID, date: time.Date(2025, time.April, 12, 0, 0, 0, 0, time.UTC),
+			expected: true},
+		{name: "sick leave", soldierID: testSquadCommander.ID, date: time.Date(2025, time.April, 6, 0, 0, 0, 0, time.UTC),
+			expected: false},
+		{name: "partial day leave", soldierID: testSquadCommander.ID, date: time.Date(2025, time.April, 8, 0, 0, 0, 0, time.UTC),
+			expected: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Act
+			onBase := scheduling.IsOnBase(testCase.soldierID, testCase.date, []models.Rotation{testRotation},
+				[]models.Leave{sickLeave, appointment})
+
+			// Assert
+			assert.Equal(t, testCase.expected, onBase)
+		})
+	}
+}
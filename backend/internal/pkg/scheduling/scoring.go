@@ -0,0 +1,82 @@
+package scheduling
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LoadWeights sets how much every component of a soldier's duty load weighs in the soldier's load score
+type LoadWeights struct {
+	// Hour weighs every hour of duty
+	Hour float64
+	// NightHour weighs every hour of duty taking place at night, on top of Hour
+	NightHour float64
+	// ShabbatShift weighs every Shabbat shift
+	ShabbatShift float64
+	// HolidayShift weighs every shift taking place on a holiday
+	HolidayShift float64
+}
+
+// ScoreBreakdown is a soldier's weighted load score, along with the contribution of every one of its components
+type ScoreBreakdown struct {
+	Hours         float64 `json:"hours"`
+	NightHours    float64 `json:"nightHours"`
+	ShabbatShifts float64 `json:"shabbatShifts"`
+	HolidayShifts float64 `json:"holidayShifts"`
+	Total         float64 `json:"total"`
+}
+
+// Scorer weighs the duty load soldiers accumulated, so that soldiers who carried less of the burden are assigned first
+type Scorer struct {
+	weights  LoadWeights
+	holidays []time.Time
+}
+
+// NewScorer returns a Scorer weighing loads by weights. Shifts count as holiday shifts on the JewishHolidays, and on
+// the additional holidays dates.
+func NewScorer(weights LoadWeights, holidays ...time.Time) (*Scorer, error) {
+	if weights.Hour < 0 || weights.NightHour < 0 || weights.ShabbatShift < 0 || weights.HolidayShift < 0 {
+		return nil, errors.Errorf("load weights must not be negative, got %+v", weights)
+	}
+	truncated := make([]time.Time, 0, len(holidays))
+	for _, holiday := range holidays {
+		truncated = append(truncated, truncateToDate(holiday))
+	}
+	return &Scorer{weights: weights, holidays: truncated}, nil
+}
+
+// Score returns the weighted load score of a soldier booked to bookedShifts
+func (s *Scorer) Score(bookedShifts []models.Shift) ScoreBreakdown {
+	var breakdown ScoreBreakdown
+	for _, shift := range bookedShifts {
+		breakdown.Hours += s.weights.Hour * shift.EndTime.Sub(shift.StartTime).Hours()
+		breakdown.NightHours += s.weights.NightHour * NightDuration(shift.StartTime, shift.EndTime).Hours()
+		if IsShabbatShift(shift) {
+			breakdown.ShabbatShifts += s.weights.ShabbatShift
+		}
+		if s.IsHolidayShift(shift) {
+			breakdown.HolidayShifts += s.weights.HolidayShift
+		}
+	}
+	breakdown.Total = breakdown.Hours + breakdown.NightHours + breakdown.ShabbatShifts + breakdown.HolidayShifts
+	return breakdown
+}
+
+// IsHolidayShift indicates whether any part of shift takes place on a Jewish holiday or on one of the Scorer's holidays
+func (s *Scorer) IsHolidayShift(shift models.Shift) bool {
+	for day := truncateToDate(shift.StartTime); day.Before(shift.EndTime); day = day.AddDate(0, 0, 1) {
+		if IsJewishHoliday(day) {
+			return true
+		}
+	}
+	for _, holiday := range s.holidays {
+		// Holidays are compared in the shift's location, just like nights and Shabbat are
+		dayStart := time.Date(holiday.Year(), holiday.Month(), holiday.Day(), 0, 0, 0, 0, shift.StartTime.Location())
+		if intersection(shift.StartTime, shift.EndTime, dayStart, dayStart.AddDate(0, 0, 1)) > 0 {
+			return true
+		}
+	}
+	return false
+}
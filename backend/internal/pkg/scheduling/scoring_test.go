@@ -0,0 +1,84 @@
+package scheduling_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testHoliday = time.Date(2025, time.April, 13, 0, 0, 0, 0, time.UTC)
+
+func newTestScorer(t *testing.T) *scheduling.Scorer {
+	scorer, err := scheduling.NewScorer(scheduling.LoadWeights{Hour: 1, NightHour: 1, ShabbatShift: 10, HolidayShift: 10}, testHoliday)
+	require.NoError(t, err)
+	return scorer
+}
+
+func TestNewScorer__negative_weight(t *testing.T) {
+	// Act
+	scorer, err := scheduling.NewScorer(scheduling.LoadWeights{Hour: 1, NightHour: -1})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, scorer)
+}
+
+func TestScorer_Score(t *testing.T) {
+	testCases := []struct {
+		name     string
+		shifts   []models.Shift
+		expected scheduling.ScoreBreakdown
+	}{
+		{
+			name:     "no shifts",
+			shifts:   nil,
+			expected: scheduling.ScoreBreakdown{},
+		},
+		{
+			name:     "day shift",
+			shifts:   []models.Shift{newTestShift("s1", time.Date(2025, time.April, 9, 8, 0, 0, 0, time.UTC), 8*time.Hour, "")},
+			expected: scheduling.ScoreBreakdown{Hours: 8, Total: 8},
+		},
+		{
+			name:     "night shift",
+			shifts:   []models.Shift{newTestShift("s1", time.Date(2025, time.April, 9, 20, 0, 0, 0, time.UTC), 8*time.Hour, "")},
+			expected: scheduling.ScoreBreakdown{Hours: 8, NightHours: 6, Total: 14},
+		},
+		{
+			name:     "shabbat shift",
+			shifts:   []models.Shift{newTestShift("s1", time.Date(2025, time.April, 12, 8, 0, 0, 0, time.UTC), 4*time.Hour, "")},
+			expected: scheduling.ScoreBreakdown{Hours: 4, ShabbatShifts: 10, Total: 14},
+		},
+		{
+			name:     "holiday shift",
+			shifts:   []models.Shift{newTestShift("s1", testHoliday.Add(8*time.Hour), 4*time.Hour, "")},
+			expected: scheduling.ScoreBreakdown{Hours: 4, HolidayShifts: 10, Total: 14},
+		},
+		{
+			name:     "jewish holiday shift",
+			shifts:   []models.Shift{newTestShift("s1", time.Date(2026, time.September, 21, 8, 0, 0, 0, time.UTC), 4*time.Hour, "")},
+			expected: scheduling.ScoreBreakdown{Hours: 4, HolidayShifts: 10, Total: 14},
+		},
+		{
+			name: "several shifts",
+			shifts: []models.Shift{
+				newTestShift("s1", time.Date(2025, time.April, 9, 8, 0, 0, 0, time.UTC), 8*time.Hour, ""),
+				newTestShift("s2", testHoliday.Add(8*time.Hour), 4*time.Hour, ""),
+			},
+			expected: scheduling.ScoreBreakdown{Hours: 12, HolidayShifts: 10, Total: 22},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Act
+			score := newTestScorer(t).Score(testCase.shifts)
+
+			// Assert
+			assert.Equal(t, testCase.expected, score)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Package scheduling holds the roster building logic - expanding shift templates into concrete shifts
+// and validating the way shifts are staffed.
+package scheduling
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/utils"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MaxGenerationRange limits the amount of days a single template expansion may cover
+const MaxGenerationRange = 366 * 24 * time.Hour
+
+// GenerateShifts expands template into unstaffed shifts for every day in the inclusive [from, to] dates range.
+// Occurrences that already exist in existingShifts(same template and start time) are skipped,
+// so running the expansion again over the same range is safe.
+func GenerateShifts(template models.ShiftTemplate, from, to time.Time, existingShifts []models.Shift) ([]models.Shift, error) {
+	from, to = truncateToDate(from), truncateToDate(to)
+	if to.Before(from) {
+		return nil, errors.New("range end is before range start")
+	}
+	if to.Sub(from) > MaxGenerationRange {
+		return nil, errors.New("range is too long")
+	}
+
+	existingStartTimes := make(map[time.Time]bool)
+	for _, shift := range existingShifts {
+		if shift.ShiftTemplateID == template.ID {
+			existingStartTimes[shift.StartTime.UTC()] = true
+		}
+	}
+
+	shifts := make([]models.Shift, 0)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		for _, shiftTime := range template.DaysOfOccurrences[day.Weekday()] {
+			shift := newShiftFromTemplate(template, day, shiftTime)
+			if existingStartTimes[shift.StartTime.UTC()] {
+				continue
+			}
+			if err := shift.IsValid(); err != nil {
+				return nil, errors.Wrap(err, "generated an invalid shift")
+			}
+			shifts = append(shifts, shift)
+		}
+	}
+	return shifts, nil
+}
+
+func newShiftFromTemplate(template models.ShiftTemplate, day time.Time, shiftTime models.ShiftTime) models.Shift {
+	startTime := time.Date(day.Year(), day.Month(), day.Day(), shiftTime.StartTime.Hour, shiftTime.StartTime.Minute, 0, 0, day.Location())
+	return models.Shift{
+		ID:              utils.NewEntityID(),
+		StartTime:       startTime,
+		EndTime:         startTime.Add(shiftTime.Duration),
+		Name:            template.Name,
+		Type:            template.ShiftType,
+		ShiftTemplateID: template.ID,
+	}
+}
+
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,116 @@
+package scheduling_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/scheduling"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testGuardTemplate = models.ShiftTemplate{
+	ID:          "template-1",
+	Name:        "Guard",
+	Description: "Main gate guard",
+	ShiftType:   models.StaticPostShiftType,
+	PersonnelRequirement: models.PersonnelRequirement{
+		SoldierRoleToCount: map[string]int{},
+	},
+	DaysOfOccurrences: map[time.Weekday][]models.ShiftTime{
+		time.Sunday: {
+			{StartTime: models.TimeOfDay{Hour: 6, Minute: 0}, Duration: 8 * time.Hour},
+			{StartTime: models.TimeOfDay{Hour: 22, Minute: 30}, Duration: 8 * time.Hour},
+		},
+		time.Monday: {
+			{StartTime: models.TimeOfDay{Hour: 6, Minute: 0}, Duration: 8 * time.Hour},
+		},
+	},
+}
+
+// 2025-04-06 is a Sunday
+var (
+	testRangeStart = time.Date(2025, time.April, 6, 0, 0, 0, 0, time.UTC)
+	testRangeEnd   = time.Date(2025, time.April, 13, 0, 0, 0, 0, time.UTC)
+)
+
+func TestGenerateShifts__success(t *testing.T) {
+	// Act
+	shifts, err := scheduling.GenerateShifts(testGuardTemplate, testRangeStart, testRangeEnd, nil)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, shifts, 5)
+	startTimes := make([]time.Time, 0, len(shifts))
+	for _, shift := range shifts {
+		assert.NotEmpty(t, shift.ID)
+		assert.Equal(t, testGuardTemplate.ID, shift.ShiftTemplateID)
+		assert.Equal(t, testGuardTemplate.Name, shift.Name)
+		assert.Equal(t, models.StaticPostShiftType, shift.Type)
+		assert.Equal(t, 8*time.Hour, shift.EndTime.Sub(shift.StartTime))
+		assert.False(t, shift.IsStaffed())
+		startTimes = append(startTimes, shift.StartTime)
+	}
+	assert.ElementsMatch(t, []time.Time{
+		time.Date(2025, time.April, 6, 6, 0, 0, 0, time.UTC),
+		time.Date(2025, time.April, 6, 22, 30, 0, 0, time.UTC),
+		time.Date(2025, time.April, 7, 6, 0, 0, 0, time.UTC),
+		time.Date(2025, time.April, 13, 6, 0, 0, 0, time.UTC),
+		time.Date(2025, time.April, 13, 22, 30, 0, 0, time.UTC),
+	}, startTimes)
+}
+
+func TestGenerateShifts__skips_existing_occurrences(t *testing.T) {
+	// Arrange
+	existingShifts, err := scheduling.GenerateShifts(testGuardTemplate, testRangeStart, testRangeStart, nil)
+	require.NoError(t, err)
+	require.Len(t, existingShifts, 2)
+
+	// Act
+	shifts, err := scheduling.GenerateShifts(testGuardTemplate, testRangeStart, testRangeEnd, existingShifts)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, shifts, 3)
+	for _, shift := range shifts {
+		assert.NotEqual(t, testRangeStart.Day(), shift.StartTime.Day())
+	}
+}
+
+func TestGenerateShifts__ignores_other_templates_shifts(t *testing.T) {
+	// Arrange
+	existingShifts, err := scheduling.GenerateShifts(testGuardTemplate, testRangeStart, testRangeStart, nil)
+	require.NoError(t, err)
+	for i := range existingShifts {
+		existingShifts[i].ShiftTemplateID = "another-template"
+	}
+
+	// Act
+	shifts, err := scheduling.GenerateShifts(testGuardTemplate, testRangeStart, testRangeEnd, existingShifts)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, shifts, 5)
+}
+
+func TestGenerateShifts__invalid_range(t *testing.T) {
+	testCases := []struct {
+		name string
+		from time.Time
+		to   time.Time
+	}{
+		{name: "end before start", from: testRangeEnd, to: testRangeStart},
+		{name: "range too long", from: testRangeStart, to: testRangeStart.AddDate(2, 0, 0)},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Act
+			shifts, err := scheduling.GenerateShifts(testGuardTemplate, testCase.from, testCase.to, nil)
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, shifts)
+		})
+	}
+}
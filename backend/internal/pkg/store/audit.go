@@ -0,0 +1,110 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/middleware/requestctx"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type IAuditStore interface {
+	AddAuditEntry(ctx context.Context, entry models.AuditEntry) error
+	// FindAuditEntries returns the changes of a single entity, oldest first
+	FindAuditEntries(ctx context.Context, entityType models.AuditEntityType, entityID string) ([]models.AuditEntry, error)
+}
+
+type InMemAuditStore struct {
+	mu sync.RWMutex
+	// entries are kept in the order they were added
+	entries []models.AuditEntry
+}
+
+func NewAuditStore() (*InMemAuditStore, error) {
+	return &InMemAuditStore{entries: make([]models.AuditEntry, 0)}, nil
+}
+
+func (s *InMemAuditStore) AddAuditEntry(ctx context.Context, entry models.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *InMemAuditStore) FindAuditEntries(ctx context.Context, entityType models.AuditEntityType, entityID string) ([]models.AuditEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := make([]models.AuditEntry, 0)
+	for _, entry := range s.entries {
+		if entry.EntityType == entityType && entry.EntityID == entityID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// newAuditEntry describes a change of an entity, made by the user of the request ctx belongs to. before and after are
+// the entity as found before and after the change - an empty slice for an entity that did not exist.
+func newAuditEntry[T any](ctx context.Context, entityType models.AuditEntityType, entityID string, action models.AuditAction,
+	before, after []T) (models.AuditEntry, error) {
+	entry := models.AuditEntry{
+		ID:         utils.NewEntityID(),
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Username:   requestctx.Username(ctx),
+		RequestID:  requestctx.RequestID(ctx),
+		Timestamp:  time.Now().UTC(),
+	}
+	var err error
+	if len(before) > 0 {
+		if entry.Before, err = json.Marshal(before[0]); err != nil {
+			return models.AuditEntry{}, errors.Wrap(err, "could not marshal entity before change")
+		}
+	}
+	if len(after) > 0 {
+		if entry.After, err = json.Marshal(after[0]); err != nil {
+			return models.AuditEntry{}, errors.Wrap(err, "could not marshal entity after change")
+		}
+	}
+	if entry.ChangedFields, err = changedFields(entry.Before, entry.After); err != nil {
+		return models.AuditEntry{}, err
+	}
+	return entry, nil
+}
+
+// changedFields compares two JSON objects field by field. A missing object counts as having no fields.
+func changedFields(before, after json.RawMessage) ([]string, error) {
+	beforeFields := make(map[string]json.RawMessage)
+	afterFields := make(map[string]json.RawMessage)
+	if len(before) > 0 {
+		if err := json.Unmarshal(before, &beforeFields); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal entity before change")
+		}
+	}
+	if len(after) > 0 {
+		if err := json.Unmarshal(after, &afterFields); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal entity after change")
+		}
+	}
+
+	changed := make([]string, 0)
+	for field, beforeValue := range beforeFields {
+		if afterValue, exists := afterFields[field]; !exists || !bytes.Equal(beforeValue, afterValue) {
+			changed = append(changed, field)
+		}
+	}
+	for field := range afterFields {
+		if _, exists := beforeFields[field]; !exists {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
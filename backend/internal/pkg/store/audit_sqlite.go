@@ -0,0 +1,45 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+type SQLiteAuditStore struct {
+	db      sqlExecutor
+	entries jsonTable[models.AuditEntry]
+}
+
+func NewSQLiteAuditStore(db *sql.DB) (*SQLiteAuditStore, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return newSQLiteAuditStore(db), nil
+}
+
+func newSQLiteAuditStore(db sqlExecutor) *SQLiteAuditStore {
+	return &SQLiteAuditStore{db: db, entries: jsonTable[models.AuditEntry]{db: db, name: "audit_entries", keyColumn: "seq"}}
+}
+
+func (s *SQLiteAuditStore) AddAuditEntry(ctx context.Context, entry models.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal audit entry")
+	}
+	_, err = s.db.ExecContext(ctx, `INSERT INTO audit_entries (entity_type, entity_id, data) VALUES (?, ?, ?)`,
+		string(entry.EntityType), entry.EntityID, string(data))
+	return errors.Wrap(err, "could not insert into audit_entries")
+}
+
+func (s *SQLiteAuditStore) FindAuditEntries(ctx context.Context, entityType models.AuditEntityType, entityID string) ([]models.AuditEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM audit_entries WHERE entity_type = ? AND entity_id = ? ORDER BY seq`,
+		string(entityType), entityID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query audit_entries")
+	}
+	return s.entries.scan(rows)
+}
@@ -0,0 +1,182 @@
+package store_test
+
+import (
+	"brothers_in_batash/internal/pkg/middleware/requestctx"
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditedShiftStore__records_every_change(t *testing.T) {
+	// Arrange
+	ctx := requestctx.WithUsername(context.Background(), "commander")
+	shiftStore, err := store.NewShiftStore()
+	require.NoError(t, err)
+	auditStore, err := store.NewAuditStore()
+	require.NoError(t, err)
+	auditedStore, err := store.NewAuditedShiftStore(shiftStore, auditStore)
+	require.NoError(t, err)
+	renamedShift := testShiftModel
+	renamedShift.Name = "Renamed Shift"
+
+	// Act
+	require.NoError(t, auditedStore.CreateNewShift(ctx, testShiftModel))
+	require.NoError(t, auditedStore.UpdateShift(ctx, renamedShift))
+	require.NoError(t, auditedStore.DeleteShift(ctx, testShiftModel.ID, 2))
+
+	// Assert
+	entries, err := auditStore.FindAuditEntries(context.Background(), models.ShiftAuditEntity, testShiftModel.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, models.CreateAuditAction, entries[0].Action)
+	assert.Empty(t, entries[0].Before)
+	assert.NotEmpty(t, entries[0].After)
+	assert.Equal(t, models.UpdateAuditAction, entries[1].Action)
+	assert.Equal(t, []string{"name", "version"}, entries[1].ChangedFields)
+	assert.Equal(t, models.DeleteAuditAction, entries[2].Action)
+	assert.NotEmpty(t, entries[2].Before)
+	assert.Empty(t, entries[2].After)
+	for _, entry := range entries {
+		assert.Equal(t, "commander", entry.Username)
+	}
+}
+
+func TestAuditedShiftStore__failed_write_is_not_recorded(t *testing.T) {
+	// Arrange
+	shiftStore, err := store.NewShiftStore()
+	require.NoError(t, err)
+	auditStore, err := store.NewAuditStore()
+	require.NoError(t, err)
+	auditedStore, err := store.NewAuditedShiftStore(shiftStore, auditStore)
+	require.NoError(t, err)
+
+	// Act
+	err = auditedStore.UpdateShift(context.Background(), testShiftModel)
+
+	// Assert
+	assert.Error(t, err)
+	entries, err := auditStore.FindAuditEntries(context.Background(), models.ShiftAuditEntity, testShiftModel.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestAuditedShiftStore__restore_records_deleted_state(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	shiftStore, err := store.NewShiftStore()
+	require.NoError(t, err)
+	auditStore, err := store.NewAuditStore()
+	require.NoError(t, err)
+	auditedStore, err := store.NewAuditedShiftStore(shiftStore, auditStore)
+	require.NoError(t, err)
+	require.NoError(t, auditedStore.CreateNewShift(ctx, testShiftModel))
+	require.NoError(t, auditedStore.DeleteShift(ctx, testShiftModel.ID, 1))
+
+	// Act
+	err = auditedStore.RestoreShift(ctx, testShiftModel.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	entries, err := auditStore.FindAuditEntries(ctx, models.ShiftAuditEntity, testShiftModel.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, models.RestoreAuditAction, entries[2].Action)
+	assert.JSONEq(t, string(entries[1].Before), string(entries[2].Before))
+	assert.NotEmpty(t, entries[2].After)
+}
+
+func TestTxAuditedShiftStore__failed_audit_rolls_back_write(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	transactor, err := store.NewSQLiteTransactor(db)
+	require.NoError(t, err)
+	auditedTransactor, err := store.NewAuditedTransactor(transactor)
+	require.NoError(t, err)
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	auditedStore, err := store.NewTxAuditedShiftStore(shiftStore, auditedTransactor)
+	require.NoError(t, err)
+	_, err = db.Exec("DROP TABLE audit_entries")
+	require.NoError(t, err)
+
+	// Act
+	err = auditedStore.CreateNewShift(context.Background(), testShiftModel)
+
+	// Assert
+	assert.Error(t, err)
+	shifts, err := shiftStore.FindShiftByID(context.Background(), testShiftModel.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, shifts)
+}
+
+func TestTxAuditedShiftStore__records_write(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	transactor, err := store.NewSQLiteTransactor(db)
+	require.NoError(t, err)
+	auditedTransactor, err := store.NewAuditedTransactor(transactor)
+	require.NoError(t, err)
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	auditedStore, err := store.NewTxAuditedShiftStore(shiftStore, auditedTransactor)
+	require.NoError(t, err)
+	auditStore, err := store.NewSQLiteAuditStore(db)
+	require.NoError(t, err)
+
+	// Act
+	err = auditedStore.CreateNewShift(context.Background(), testShiftModel)
+
+	// Assert
+	assert.NoError(t, err)
+	shifts, err := auditedStore.FindShiftByID(context.Background(), testShiftModel.ID)
+	assert.NoError(t, err)
+	assert.Len(t, shifts, 1)
+	entries, err := auditStore.FindAuditEntries(context.Background(), models.ShiftAuditEntity, testShiftModel.ID)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestAuditedTransactor_WithinTx__rolls_back_audit_entries_on_error(t *testing.T) {
+	// Arrange
+	stores := newTestInMemStores(t)
+	transactor, err := store.NewInMemTransactor(stores)
+	require.NoError(t, err)
+	auditedTransactor, err := store.NewAuditedTransactor(transactor)
+	require.NoError(t, err)
+
+	// Act
+	err = auditedTransactor.WithinTx(context.Background(), createShiftAndDaySchedule(true))
+
+	// Assert
+	assert.Error(t, err)
+	entries, err := stores.Audit.FindAuditEntries(context.Background(), models.ShiftAuditEntity, testShiftModel.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestAuditedTransactor_WithinTx__records_changes_on_success(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	transactor, err := store.NewSQLiteTransactor(db)
+	require.NoError(t, err)
+	auditedTransactor, err := store.NewAuditedTransactor(transactor)
+	require.NoError(t, err)
+	auditStore, err := store.NewSQLiteAuditStore(db)
+	require.NoError(t, err)
+
+	// Act
+	err = auditedTransactor.WithinTx(context.Background(), createShiftAndDaySchedule(false))
+
+	// Assert
+	assert.NoError(t, err)
+	shiftEntries, err := auditStore.FindAuditEntries(context.Background(), models.ShiftAuditEntity, testShiftModel.ID)
+	assert.NoError(t, err)
+	assert.Len(t, shiftEntries, 1)
+	dayEntries, err := auditStore.FindAuditEntries(context.Background(), models.DayScheduleAuditEntity, "2025-04-09")
+	assert.NoError(t, err)
+	assert.Len(t, dayEntries, 1)
+}
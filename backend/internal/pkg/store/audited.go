@@ -0,0 +1,370 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The Audited stores wrap a store and record every change made through it in an audit store. The entity is read right
+// before and right after each write, so its audit entry holds the versions the write replaced and produced. The write
+// and its audit entry are saved separately, so the Audited stores are meant to be used within a transaction - the
+// AuditedTransactor binds them to its units of work, and the TxAudited stores run every single write as one.
+
+type AuditedShiftStore struct {
+	IShiftStore
+	audit IAuditStore
+}
+
+func NewAuditedShiftStore(shiftStore IShiftStore, auditStore IAuditStore) (*AuditedShiftStore, error) {
+	if shiftStore == nil {
+		return nil, errors.New("shiftStore is nil")
+	}
+	if auditStore == nil {
+		return nil, errors.New("auditStore is nil")
+	}
+	return &AuditedShiftStore{IShiftStore: shiftStore, audit: auditStore}, nil
+}
+
+func (s *AuditedShiftStore) CreateNewShift(ctx context.Context, shift models.Shift) error {
+	return auditedWrite(ctx, s.audit, models.ShiftAuditEntity, shift.ID, models.CreateAuditAction,
+		func() ([]models.Shift, error) { return s.FindShiftByID(ctx, shift.ID) },
+		func() error { return s.IShiftStore.CreateNewShift(ctx, shift) })
+}
+
+func (s *AuditedShiftStore) UpdateShift(ctx context.Context, shift models.Shift) error {
+	return auditedWrite(ctx, s.audit, models.ShiftAuditEntity, shift.ID, models.UpdateAuditAction,
+		func() ([]models.Shift, error) { return s.FindShiftByID(ctx, shift.ID) },
+		func() error { return s.IShiftStore.UpdateShift(ctx, shift) })
+}
+
+func (s *AuditedShiftStore) DeleteShift(ctx context.Context, id string, version int) error {
+	return auditedWrite(ctx, s.audit, models.ShiftAuditEntity, id, models.DeleteAuditAction,
+		func() ([]models.Shift, error) { return s.FindShiftByID(ctx, id) },
+		func() error { return s.IShiftStore.DeleteShift(ctx, id, version) })
+}
+
+func (s *AuditedShiftStore) RestoreShift(ctx context.Context, id string) error {
+	return auditedWrite(ctx, s.audit, models.ShiftAuditEntity, id, models.RestoreAuditAction,
+		func() ([]models.Shift, error) { return s.FindShiftByID(ctx, id) },
+		func() error { return s.IShiftStore.RestoreShift(ctx, id) })
+}
+
+type AuditedSoldierStore struct {
+	ISoldierStore
+	audit IAuditStore
+}
+
+func NewAuditedSoldierStore(soldierStore ISoldierStore, auditStore IAuditStore) (*AuditedSoldierStore, error) {
+	if soldierStore == nil {
+		return nil, errors.New("soldierStore is nil")
+	}
+	if auditStore == nil {
+		return nil, errors.New("auditStore is nil")
+	}
+	return &AuditedSoldierStore{ISoldierStore: soldierStore, audit: auditStore}, nil
+}
+
+func (s *AuditedSoldierStore) CreateNewSoldier(ctx context.Context, soldier models.Soldier) error {
+	return auditedWrite(ctx, s.audit, models.SoldierAuditEntity, soldier.ID, models.CreateAuditAction,
+		func() ([]models.Soldier, error) { return s.FindSoldierByID(ctx, soldier.ID) },
+		func() error { return s.ISoldierStore.CreateNewSoldier(ctx, soldier) })
+}
+
+func (s *AuditedSoldierStore) UpdateSoldier(ctx context.Context, soldier models.Soldier) error {
+	return auditedWrite(ctx, s.audit, models.SoldierAuditEntity, soldier.ID, models.UpdateAuditAction,
+		func() ([]models.Soldier, error) { return s.FindSoldierByID(ctx, soldier.ID) },
+		func() error { return s.ISoldierStore.UpdateSoldier(ctx, soldier) })
+}
+
+func (s *AuditedSoldierStore) DeleteSoldier(ctx context.Context, id string, version int) error {
+	return auditedWrite(ctx, s.audit, models.SoldierAuditEntity, id, models.DeleteAuditAction,
+		func() ([]models.Soldier, error) { return s.FindSoldierByID(ctx, id) },
+		func() error { return s.ISoldierStore.DeleteSoldier(ctx, id, version) })
+}
+
+func (s *AuditedSoldierStore) RestoreSoldier(ctx context.Context, id string) error {
+	return auditedWrite(ctx, s.audit, models.SoldierAuditEntity, id, models.RestoreAuditAction,
+		func() ([]models.Soldier, error) { return s.FindSoldierByID(ctx, id) },
+		func() error { return s.ISoldierStore.RestoreSoldier(ctx, id) })
+}
+
+type AuditedShiftTemplateStore struct {
+	IShiftTemplateStore
+	audit IAuditStore
+}
+
+func NewAuditedShiftTemplateStore(shiftTemplateStore IShiftTemplateStore, auditStore IAuditStore) (*AuditedShiftTemplateStore, error) {
+	if shiftTemplateStore == nil {
+		return nil, errors.New("shiftTemplateStore is nil")
+	}
+	if auditStore == nil {
+		return nil, errors.New("auditStore is nil")
+	}
+	return &AuditedShiftTemplateStore{IShiftTemplateStore: shiftTemplateStore, audit: auditStore}, nil
+}
+
+func (s *AuditedShiftTemplateStore) CreateNewShiftTemplate(ctx context.Context, template models.ShiftTemplate) error {
+	return auditedWrite(ctx, s.audit, models.ShiftTemplateAuditEntity, template.ID, models.CreateAuditAction,
+		func() ([]models.ShiftTemplate, error) { return s.FindShiftTemplateByID(ctx, template.ID) },
+		func() error { return s.IShiftTemplateStore.CreateNewShiftTemplate(ctx, template) })
+}
+
+func (s *AuditedShiftTemplateStore) UpdateShiftTemplate(ctx context.Context, template models.ShiftTemplate) error {
+	return auditedWrite(ctx, s.audit, models.ShiftTemplateAuditEntity, template.ID, models.UpdateAuditAction,
+		func() ([]models.ShiftTemplate, error) { return s.FindShiftTemplateByID(ctx, template.ID) },
+		func() error { return s.IShiftTemplateStore.UpdateShiftTemplate(ctx, template) })
+}
+
+func (s *AuditedShiftTemplateStore) DeleteShiftTemplate(ctx context.Context, id string, version int) error {
+	return auditedWrite(ctx, s.audit, models.ShiftTemplateAuditEntity, id, models.DeleteAuditAction,
+		func() ([]models.ShiftTemplate, error) { return s.FindShiftTemplateByID(ctx, id) },
+		func() error { return s.IShiftTemplateStore.DeleteShiftTemplate(ctx, id, version) })
+}
+
+func (s *AuditedShiftTemplateStore) RestoreShiftTemplate(ctx context.Context, id string) error {
+	return auditedWrite(ctx, s.audit, models.ShiftTemplateAuditEntity, id, models.RestoreAuditAction,
+		func() ([]models.ShiftTemplate, error) { return s.FindShiftTemplateByID(ctx, id) },
+		func() error { return s.IShiftTemplateStore.RestoreShiftTemplate(ctx, id) })
+}
+
+type AuditedDaySchedStore struct {
+	IDayStore
+	audit IAuditStore
+}
+
+func NewAuditedDaySchedStore(dayStore IDayStore, auditStore IAuditStore) (*AuditedDaySchedStore, error) {
+	if dayStore == nil {
+		return nil, errors.New("dayStore is nil")
+	}
+	if auditStore == nil {
+		return nil, errors.New("auditStore is nil")
+	}
+	return &AuditedDaySchedStore{IDayStore: dayStore, audit: auditStore}, nil
+}
+
+func (s *AuditedDaySchedStore) CreateNewDaySchedule(ctx context.Context, day models.DaySchedule) error {
+	return auditedWrite(ctx, s.audit, models.DayScheduleAuditEntity, normalizeDate(day.Date), models.CreateAuditAction,
+		func() ([]models.DaySchedule, error) { return s.FindDaySchedule(ctx, day.Date) },
+		func() error { return s.IDayStore.CreateNewDaySchedule(ctx, day) })
+}
+
+func (s *AuditedDaySchedStore) UpdateDaySchedule(ctx context.Context, day models.DaySchedule) error {
+	return auditedWrite(ctx, s.audit, models.DayScheduleAuditEntity, normalizeDate(day.Date), models.UpdateAuditAction,
+		func() ([]models.DaySchedule, error) { return s.FindDaySchedule(ctx, day.Date) },
+		func() error { return s.IDayStore.UpdateDaySchedule(ctx, day) })
+}
+
+func (s *AuditedDaySchedStore) DeleteDaySchedule(ctx context.Context, date time.Time, version int) error {
+	return auditedWrite(ctx, s.audit, models.DayScheduleAuditEntity, normalizeDate(date), models.DeleteAuditAction,
+		func() ([]models.DaySchedule, error) { return s.FindDaySchedule(ctx, date) },
+		func() error { return s.IDayStore.DeleteDaySchedule(ctx, date, version) })
+}
+
+// auditedWrite runs write, and records the change it made to the entity find fetches. Nothing is recorded if write fails.
+func auditedWrite[T any](ctx context.Context, audit IAuditStore, entityType models.AuditEntityType, entityID string,
+	action models.AuditAction, find func() ([]T, error), write func() error) error {
+	var before, after []T
+	var err error
+	switch action {
+	case models.CreateAuditAction:
+	case models.RestoreAuditAction:
+		// find does not return deleted entities, so the restored entity is recorded as it was when it was deleted
+		if before, err = deletedState[T](ctx, audit, entityType, entityID); err != nil {
+			return errors.Wrapf(err, "could not fetch %s %s before restore", entityType, entityID)
+		}
+	default:
+		if before, err = find(); err != nil {
+			return errors.Wrapf(err, "could not fetch %s %s before change", entityType, entityID)
+		}
+	}
+	if err := write(); err != nil {
+		return err
+	}
+	if action != models.DeleteAuditAction {
+		if after, err = find(); err != nil {
+			return errors.Wrapf(err, "could not fetch %s %s after change", entityType, entityID)
+		}
+	}
+
+	entry, err := newAuditEntry(ctx, entityType, entityID, action, before, after)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(audit.AddAuditEntry(ctx, entry), "could not audit change of %s %s", entityType, entityID)
+}
+
+// deletedState returns the entity as its latest delete audit entry recorded it, or an empty slice if its deletion was
+// not recorded
+func deletedState[T any](ctx context.Context, audit IAuditStore, entityType models.AuditEntityType, entityID string) ([]T, error) {
+	entries, err := audit.FindAuditEntries(ctx, entityType, entityID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch audit entries")
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Action != models.DeleteAuditAction || len(entries[i].Before) == 0 {
+			continue
+		}
+		var entity T
+		if err := json.Unmarshal(entries[i].Before, &entity); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal deleted entity")
+		}
+		return []T{entity}, nil
+	}
+	return []T{}, nil
+}
+
+// AuditedTransactor records the changes units of work make, as part of their transaction. A unit of work's changes and
+// their audit entries are saved together or not at all.
+type AuditedTransactor struct {
+	transactor ITransactor
+}
+
+func NewAuditedTransactor(transactor ITransactor) (*AuditedTransactor, error) {
+	if transactor == nil {
+		return nil, errors.New("transactor is nil")
+	}
+	return &AuditedTransactor{transactor: transactor}, nil
+}
+
+func (t *AuditedTransactor) WithinTx(ctx context.Context, work func(stores TxStores) error) error {
+	return t.transactor.WithinTx(ctx, func(stores TxStores) error {
+		stores.Days = &AuditedDaySchedStore{IDayStore: stores.Days, audit: stores.Audit}
+		stores.Shifts = &AuditedShiftStore{IShiftStore: stores.Shifts, audit: stores.Audit}
+		stores.Soldiers = &AuditedSoldierStore{ISoldierStore: stores.Soldiers, audit: stores.Audit}
+		stores.ShiftTemplates = &AuditedShiftTemplateStore{IShiftTemplateStore: stores.ShiftTemplates, audit: stores.Audit}
+		return work(stores)
+	})
+}
+
+// The TxAudited stores read through the store they wrap, and run every write as a unit of work of an AuditedTransactor,
+// so an entity and its audit entry are saved together or not at all.
+
+type TxAuditedShiftStore struct {
+	IShiftStore
+	transactor *AuditedTransactor
+}
+
+func NewTxAuditedShiftStore(shiftStore IShiftStore, transactor *AuditedTransactor) (*TxAuditedShiftStore, error) {
+	if shiftStore == nil {
+		return nil, errors.New("shiftStore is nil")
+	}
+	if transactor == nil {
+		return nil, errors.New("transactor is nil")
+	}
+	return &TxAuditedShiftStore{IShiftStore: shiftStore, transactor: transactor}, nil
+}
+
+func (s *TxAuditedShiftStore) CreateNewShift(ctx context.Context, shift models.Shift) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error { return stores.Shifts.CreateNewShift(ctx, shift) })
+}
+
+func (s *TxAuditedShiftStore) UpdateShift(ctx context.Context, shift models.Shift) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error { return stores.Shifts.UpdateShift(ctx, shift) })
+}
+
+func (s *TxAuditedShiftStore) DeleteShift(ctx context.Context, id string, version int) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error { return stores.Shifts.DeleteShift(ctx, id, version) })
+}
+
+func (s *TxAuditedShiftStore) RestoreShift(ctx context.Context, id string) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error { return stores.Shifts.RestoreShift(ctx, id) })
+}
+
+type TxAuditedSoldierStore struct {
+	ISoldierStore
+	transactor *AuditedTransactor
+}
+
+func NewTxAuditedSoldierStore(soldierStore ISoldierStore, transactor *AuditedTransactor) (*TxAuditedSoldierStore, error) {
+	if soldierStore == nil {
+		return nil, errors.New("soldierStore is nil")
+	}
+	if transactor == nil {
+		return nil, errors.New("transactor is nil")
+	}
+	return &TxAuditedSoldierStore{ISoldierStore: soldierStore, transactor: transactor}, nil
+}
+
+func (s *TxAuditedSoldierStore) CreateNewSoldier(ctx context.Context, soldier models.Soldier) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error { return stores.Soldiers.CreateNewSoldier(ctx, soldier) })
+}
+
+func (s *TxAuditedSoldierStore) UpdateSoldier(ctx context.Context, soldier models.Soldier) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error { return stores.Soldiers.UpdateSoldier(ctx, soldier) })
+}
+
+func (s *TxAuditedSoldierStore) DeleteSoldier(ctx context.Context, id string, version int) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error { return stores.Soldiers.DeleteSoldier(ctx, id, version) })
+}
+
+func (s *TxAuditedSoldierStore) RestoreSoldier(ctx context.Context, id string) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error { return stores.Soldiers.RestoreSoldier(ctx, id) })
+}
+
+type TxAuditedShiftTemplateStore struct {
+	IShiftTemplateStore
+	transactor *AuditedTransactor
+}
+
+func NewTxAuditedShiftTemplateStore(shiftTemplateStore IShiftTemplateStore, transactor *AuditedTransactor) (*TxAuditedShiftTemplateStore, error) {
+	if shiftTemplateStore == nil {
+		return nil, errors.New("shiftTemplateStore is nil")
+	}
+	if transactor == nil {
+		return nil, errors.New("transactor is nil")
+	}
+	return &TxAuditedShiftTemplateStore{IShiftTemplateStore: shiftTemplateStore, transactor: transactor}, nil
+}
+
+func (s *TxAuditedShiftTemplateStore) CreateNewShiftTemplate(ctx context.Context, template models.ShiftTemplate) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error {
+		return stores.ShiftTemplates.CreateNewShiftTemplate(ctx, template)
+	})
+}
+
+func (s *TxAuditedShiftTemplateStore) UpdateShiftTemplate(ctx context.Context, template models.ShiftTemplate) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error {
+		return stores.ShiftTemplates.UpdateShiftTemplate(ctx, template)
+	})
+}
+
+func (s *TxAuditedShiftTemplateStore) DeleteShiftTemplate(ctx context.Context, id string, version int) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error {
+		return stores.ShiftTemplates.DeleteShiftTemplate(ctx, id, version)
+	})
+}
+
+func (s *TxAuditedShiftTemplateStore) RestoreShiftTemplate(ctx context.Context, id string) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error { return stores.ShiftTemplates.RestoreShiftTemplate(ctx, id) })
+}
+
+type TxAuditedDaySchedStore struct {
+	IDayStore
+	transactor *AuditedTransactor
+}
+
+func NewTxAuditedDaySchedStore(dayStore IDayStore, transactor *AuditedTransactor) (*TxAuditedDaySchedStore, error) {
+	if dayStore == nil {
+		return nil, errors.New("dayStore is nil")
+	}
+	if transactor == nil {
+		return nil, errors.New("transactor is nil")
+	}
+	return &TxAuditedDaySchedStore{IDayStore: dayStore, transactor: transactor}, nil
+}
+
+func (s *TxAuditedDaySchedStore) CreateNewDaySchedule(ctx context.Context, day models.DaySchedule) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error { return stores.Days.CreateNewDaySchedule(ctx, day) })
+}
+
+func (s *TxAuditedDaySchedStore) UpdateDaySchedule(ctx context.Context, day models.DaySchedule) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error { return stores.Days.UpdateDaySchedule(ctx, day) })
+}
+
+func (s *TxAuditedDaySchedStore) DeleteDaySchedule(ctx context.Context, date time.Time, version int) error {
+	return s.transactor.WithinTx(ctx, func(stores TxStores) error { return stores.Days.DeleteDaySchedule(ctx, date, version) })
+}
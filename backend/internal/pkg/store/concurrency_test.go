@@ -0,0 +1,211 @@
+package store_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrentWorkers is the number of goroutines hammering a store at once. Run with -race to catch unguarded access.
+const concurrentWorkers = 50
+
+// runConcurrently runs work once per worker, all in parallel, and waits for all of them to finish
+func runConcurrently(work func(worker int)) {
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrentWorkers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			work(worker)
+		}(worker)
+	}
+	wg.Wait()
+}
+
+func TestInMemShiftStore__concurrent_access(t *testing.T) {
+	// Arrange
+	shiftStore, err := store.NewShiftStore()
+	require.NoError(t, err)
+
+	// Act
+	runConcurrently(func(worker int) {
+		shift := testShiftModel
+		shift.ID = fmt.Sprintf("shift-%d", worker)
+		assert.NoError(t, shiftStore.CreateNewShift(context.Background(), shift))
+		shift.Name = "Updated Shift"
+		assert.NoError(t, shiftStore.UpdateShift(context.Background(), shift))
+		_, err := shiftStore.FindAllShifts(context.Background())
+		assert.NoError(t, err)
+		if worker%2 == 0 {
+			assert.NoError(t, shiftStore.DeleteShift(context.Background(), shift.ID, 2))
+		}
+	})
+
+	// Assert
+	shifts, err := shiftStore.FindAllShifts(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, shifts, concurrentWorkers/2)
+}
+
+func TestInMemSoldierStore__concurrent_access(t *testing.T) {
+	// Arrange
+	soldierStore, err := store.NewSoldierStore()
+	require.NoError(t, err)
+
+	// Act
+	runConcurrently(func(worker int) {
+		soldier := testSoldier
+		soldier.ID = fmt.Sprintf("soldier-%d", worker)
+		assert.NoError(t, soldierStore.CreateNewSoldier(context.Background(), soldier))
+		soldier.FirstName = "Updated"
+		assert.NoError(t, soldierStore.UpdateSoldier(context.Background(), soldier))
+		_, err := soldierStore.FindSoldierByID(context.Background(), soldier.ID)
+		assert.NoError(t, err)
+		if worker%2 == 0 {
+			assert.NoError(t, soldierStore.DeleteSoldier(context.Background(), soldier.ID, 2))
+		}
+	})
+
+	// Assert
+	soldiers, err := soldierStore.FindAllSoldiers(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, soldiers, concurrentWorkers/2)
+}
+
+func TestInMemDaySchedStore__concurrent_access(t *testing.T) {
+	// Arrange
+	dayStore, err := store.NewInMemDaySchedStore()
+	require.NoError(t, err)
+
+	// Act
+	runConcurrently(func(worker int) {
+		day := models.DaySchedule{
+			Date:   time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, worker),
+			Shifts: []models.Shift{testShiftModel},
+		}
+		assert.NoError(t, dayStore.CreateNewDaySchedule(context.Background(), day))
+		day.Version = 1
+		assert.NoError(t, dayStore.UpdateDaySchedule(context.Background(), day))
+		_, err := dayStore.FindDaySchedule(context.Background(), day.Date)
+		assert.NoError(t, err)
+		if worker%2 == 0 {
+			assert.NoError(t, dayStore.DeleteDaySchedule(context.Background(), day.Date, 2))
+		}
+	})
+
+	// Assert
+	days, err := dayStore.FindAllDaySchedules(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, days, concurrentWorkers/2)
+}
+
+func TestInMemShiftTemplateStore__concurrent_access(t *testing.T) {
+	// Arrange
+	shiftTemplateStore, err := store.NewShiftTemplateStore()
+	require.NoError(t, err)
+
+	// Act
+	runConcurrently(func(worker int) {
+		template := models.ShiftTemplate{
+			ID:          fmt.Sprintf("template-%d", worker),
+			Name:        "Test Shift Template",
+			Description: "Test Description",
+			PersonnelRequirement: models.PersonnelRequirement{
+				SoldierRoleToCount: map[string]int{"Commander": 1},
+			},
+			DaysOfOccurrences: map[time.Weekday][]models.ShiftTime{
+				time.Monday: {{StartTime: models.TimeOfDay{Hour: 8}, Duration: time.Hour}},
+			},
+		}
+		assert.NoError(t, shiftTemplateStore.CreateNewShiftTemplate(context.Background(), template))
+		template.Version = 1
+		assert.NoError(t, shiftTemplateStore.UpdateShiftTemplate(context.Background(), template))
+		_, err := shiftTemplateStore.FindShiftTemplateByID(context.Background(), template.ID)
+		assert.NoError(t, err)
+		if worker%2 == 0 {
+			assert.NoError(t, shiftTemplateStore.DeleteShiftTemplate(context.Background(), template.ID, 2))
+		}
+	})
+
+	// Assert
+	templates, err := shiftTemplateStore.FindAllShiftsTemplate(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, templates, concurrentWorkers/2)
+}
+
+func TestInMemUserStore__concurrent_access(t *testing.T) {
+	// Arrange
+	userStore, err := store.NewUserStore()
+	require.NoError(t, err)
+
+	// Act
+	runConcurrently(func(worker int) {
+		user := models.User{Username: fmt.Sprintf("user-%d", worker), HashedPassword: []byte("hashed-password"),
+			Role: models.SoldierUserRole}
+		assert.NoError(t, userStore.CreateNewUser(context.Background(), user))
+		users, err := userStore.FindUserByUsername(context.Background(), user.Username)
+		assert.NoError(t, err)
+		assert.Len(t, users, 1)
+	})
+}
+
+func TestInMemLeaveStore__concurrent_access(t *testing.T) {
+	// Arrange
+	leaveStore, err := store.NewLeaveStore()
+	require.NoError(t, err)
+
+	// Act
+	runConcurrently(func(worker int) {
+		leave := testLeave
+		leave.ID = fmt.Sprintf("leave-%d", worker)
+		assert.NoError(t, leaveStore.CreateNewLeave(context.Background(), leave))
+		assert.NoError(t, leaveStore.UpdateLeave(context.Background(), leave))
+		_, err := leaveStore.FindLeavesBySoldierID(context.Background(), leave.SoldierID)
+		assert.NoError(t, err)
+		if worker%2 == 0 {
+			assert.NoError(t, leaveStore.DeleteLeave(context.Background(), leave.ID))
+		}
+	})
+
+	// Assert
+	leaves, err := leaveStore.FindAllLeaves(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, leaves, concurrentWorkers/2)
+}
+
+func TestInMemRotationStore__concurrent_access(t *testing.T) {
+	// Arrange
+	rotationStore, err := store.NewRotationStore()
+	require.NoError(t, err)
+
+	// Act
+	runConcurrently(func(worker int) {
+		rotation := models.Rotation{
+			ID:         fmt.Sprintf("rotation-%d", worker),
+			Name:       "11/3",
+			SoldierIDs: []string{testSoldier.ID},
+			OnBaseDays: 11,
+			HomeDays:   3,
+			StartDate:  time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+		}
+		assert.NoError(t, rotationStore.CreateNewRotation(context.Background(), rotation))
+		assert.NoError(t, rotationStore.UpdateRotation(context.Background(), rotation))
+		_, err := rotationStore.FindRotationsBySoldierID(context.Background(), testSoldier.ID)
+		assert.NoError(t, err)
+		if worker%2 == 0 {
+			assert.NoError(t, rotationStore.DeleteRotation(context.Background(), rotation.ID))
+		}
+	})
+
+	// Assert
+	rotations, err := rotationStore.FindAllRotations(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, rotations, concurrentWorkers/2)
+}
@@ -2,22 +2,25 @@ package store
 
 import (
 	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 )
 
-//TODO - accept ctx in signatures
-
 type IDayStore interface {
-	CreateNewDaySchedule(day models.DaySchedule) error
-	FindDaySchedule(date time.Time) ([]models.DaySchedule, error)
-	FindAllDaySchedules() ([]models.DaySchedule, error)
-	UpdateDaySchedule(day models.DaySchedule) error
-	DeleteDaySchedule(date time.Time) error
+	CreateNewDaySchedule(ctx context.Context, day models.DaySchedule) error
+	FindDaySchedule(ctx context.Context, date time.Time) ([]models.DaySchedule, error)
+	FindAllDaySchedules(ctx context.Context) ([]models.DaySchedule, error)
+	// FindDaySchedules returns the day schedules that match filter
+	FindDaySchedules(ctx context.Context, filter DayScheduleFilter) ([]models.DaySchedule, error)
+	UpdateDaySchedule(ctx context.Context, day models.DaySchedule) error
+	DeleteDaySchedule(ctx context.Context, date time.Time, version int) error
 }
 
 type InMemDaySchedStore struct {
+	mu sync.RWMutex
 	//days maps between a normalized string representation of date to the instance
 	days map[string]models.DaySchedule
 }
@@ -26,15 +29,24 @@ func NewInMemDaySchedStore() (*InMemDaySchedStore, error) {
 	return &InMemDaySchedStore{days: make(map[string]models.DaySchedule)}, nil
 }
 
-func (s *InMemDaySchedStore) CreateNewDaySchedule(day models.DaySchedule) error {
+func (s *InMemDaySchedStore) CreateNewDaySchedule(ctx context.Context, day models.DaySchedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if err := day.IsValid(); err != nil {
 		return errors.Wrap(err, "Could not insert invalid day schedule")
 	}
+	if _, exists := s.days[normalizeDate(day.Date)]; exists {
+		return alreadyExists("day schedule", normalizeDate(day.Date))
+	}
+	day = day.WithSoldierRefs().WithShiftRefs()
+	day.Version = 1
 	s.days[normalizeDate(day.Date)] = day
 	return nil
 }
 
-func (s *InMemDaySchedStore) FindDaySchedule(date time.Time) ([]models.DaySchedule, error) {
+func (s *InMemDaySchedStore) FindDaySchedule(ctx context.Context, date time.Time) ([]models.DaySchedule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	if day, ok := s.days[normalizeDate(date)]; !ok {
 		return []models.DaySchedule{}, nil
 	} else {
@@ -42,18 +54,26 @@ func (s *InMemDaySchedStore) FindDaySchedule(date time.Time) ([]models.DaySchedu
 	}
 }
 
-func (s *InMemDaySchedStore) UpdateDaySchedule(day models.DaySchedule) error {
+func (s *InMemDaySchedStore) UpdateDaySchedule(ctx context.Context, day models.DaySchedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	//TODO - potential bug - users might change day.Date and overwrite the wrong day instance
-	if _, ok := s.days[normalizeDate(day.Date)]; !ok {
+	if stored, ok := s.days[normalizeDate(day.Date)]; !ok {
 		return errors.New("day does not exist")
 	} else if err := day.IsValid(); err != nil {
 		return errors.Wrap(err, "could not update day schedule with an invalid instance")
+	} else if stored.Version != day.Version {
+		return versionConflict("day schedule", normalizeDate(day.Date), stored.Version)
 	}
+	day = day.WithSoldierRefs().WithShiftRefs()
+	day.Version++
 	s.days[normalizeDate(day.Date)] = day
 	return nil
 }
 
-func (s *InMemDaySchedStore) FindAllDaySchedules() ([]models.DaySchedule, error) {
+func (s *InMemDaySchedStore) FindAllDaySchedules(ctx context.Context) ([]models.DaySchedule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	daySchedules := make([]models.DaySchedule, 0, len(s.days))
 	for _, daySchedule := range s.days {
 		daySchedules = append(daySchedules, daySchedule)
@@ -61,10 +81,26 @@ func (s *InMemDaySchedStore) FindAllDaySchedules() ([]models.DaySchedule, error)
 	return daySchedules, nil
 }
 
-func (s *InMemDaySchedStore) DeleteDaySchedule(date time.Time) error {
+func (s *InMemDaySchedStore) FindDaySchedules(ctx context.Context, filter DayScheduleFilter) ([]models.DaySchedule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daySchedules := make([]models.DaySchedule, 0)
+	for _, daySchedule := range s.days {
+		if filter.matchesDate(daySchedule.Date) {
+			daySchedules = append(daySchedules, daySchedule)
+		}
+	}
+	return filter.filterShifts(daySchedules), nil
+}
+
+func (s *InMemDaySchedStore) DeleteDaySchedule(ctx context.Context, date time.Time, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	dateStr := normalizeDate(date)
-	if _, exists := s.days[dateStr]; !exists {
+	if stored, exists := s.days[dateStr]; !exists {
 		return errors.New("day schedule does not exist")
+	} else if stored.Version != version {
+		return versionConflict("day schedule", dateStr, stored.Version)
 	}
 	delete(s.days, dateStr)
 	return nil
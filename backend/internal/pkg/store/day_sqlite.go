@@ -0,0 +1,72 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type SQLiteDaySchedStore struct {
+	days jsonTable[models.DaySchedule]
+}
+
+func NewSQLiteDaySchedStore(db *sql.DB) (*SQLiteDaySchedStore, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return newSQLiteDaySchedStore(db), nil
+}
+
+func newSQLiteDaySchedStore(db sqlExecutor) *SQLiteDaySchedStore {
+	return &SQLiteDaySchedStore{days: jsonTable[models.DaySchedule]{db: db, name: "day_schedules", keyColumn: "date"}}
+}
+
+func (s *SQLiteDaySchedStore) CreateNewDaySchedule(ctx context.Context, day models.DaySchedule) error {
+	if err := day.IsValid(); err != nil {
+		return errors.Wrap(err, "Could not insert invalid day schedule")
+	}
+	day = day.WithSoldierRefs().WithShiftRefs()
+	day.Version = 1
+	return s.days.insert(ctx, normalizeDate(day.Date), day)
+}
+
+func (s *SQLiteDaySchedStore) FindDaySchedule(ctx context.Context, date time.Time) ([]models.DaySchedule, error) {
+	return s.days.find(ctx, normalizeDate(date))
+}
+
+func (s *SQLiteDaySchedStore) FindAllDaySchedules(ctx context.Context) ([]models.DaySchedule, error) {
+	return s.days.findAll(ctx)
+}
+
+// FindDaySchedules filters the dates within the query, and the shifts of the matching day schedules after reading them
+func (s *SQLiteDaySchedStore) FindDaySchedules(ctx context.Context, filter DayScheduleFilter) ([]models.DaySchedule, error) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+	if !filter.From.IsZero() {
+		conditions = append(conditions, `date >= ?`)
+		args = append(args, normalizeDate(filter.From))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, `date < ?`)
+		args = append(args, normalizeDate(filter.To))
+	}
+	days, err := s.days.findWhere(ctx, conditions, args...)
+	if err != nil {
+		return nil, err
+	}
+	return filter.filterShifts(days), nil
+}
+
+func (s *SQLiteDaySchedStore) UpdateDaySchedule(ctx context.Context, day models.DaySchedule) error {
+	if err := day.IsValid(); err != nil {
+		return errors.Wrap(err, "could not update day schedule with an invalid instance")
+	}
+	return s.days.updateVersioned(ctx, normalizeDate(day.Date), day.WithSoldierRefs().WithShiftRefs(), day.Version)
+}
+
+func (s *SQLiteDaySchedStore) DeleteDaySchedule(ctx context.Context, date time.Time, version int) error {
+	return s.days.deleteVersioned(ctx, normalizeDate(date), version)
+}
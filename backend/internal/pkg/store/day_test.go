@@ -3,6 +3,7 @@ package store_test
 import (
 	"brothers_in_batash/internal/pkg/models"
 	"brothers_in_batash/internal/pkg/store"
+	"context"
 	"testing"
 	"time"
 
@@ -34,6 +35,7 @@ var testDaySchedule = models.DaySchedule{
 			Commander: testCommander,
 		},
 	},
+	Version: 1,
 }
 
 func TestInMemDaySchedStore_CreateNewDaySchedule__success(t *testing.T) {
@@ -42,14 +44,14 @@ func TestInMemDaySchedStore_CreateNewDaySchedule__success(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Act
-	err = dayStore.CreateNewDaySchedule(testDaySchedule)
+	err = dayStore.CreateNewDaySchedule(context.Background(), testDaySchedule)
 
 	// Assert
 	assert.NoError(t, err)
-	storedDaySchedule, err := dayStore.FindDaySchedule(testDaySchedule.Date)
+	storedDaySchedule, err := dayStore.FindDaySchedule(context.Background(), testDaySchedule.Date)
 	require.NoError(t, err)
 	require.Len(t, storedDaySchedule, 1)
-	assert.Equal(t, testDaySchedule, storedDaySchedule[0])
+	assert.Equal(t, testDaySchedule.WithSoldierRefs(), storedDaySchedule[0])
 }
 
 func TestInMemDaySchedStore_CreateNewDaySchedule__error_on_invalid_data(t *testing.T) {
@@ -61,29 +63,51 @@ func TestInMemDaySchedStore_CreateNewDaySchedule__error_on_invalid_data(t *testi
 	}
 
 	// Act
-	err = dayStore.CreateNewDaySchedule(daySchedule)
+	err = dayStore.CreateNewDaySchedule(context.Background(), daySchedule)
 
 	// Assert
 	assert.Error(t, err)
-	storedDaySchedule, err := dayStore.FindDaySchedule(daySchedule.Date)
+	storedDaySchedule, err := dayStore.FindDaySchedule(context.Background(), daySchedule.Date)
 	require.NoError(t, err)
 	assert.Empty(t, storedDaySchedule)
 }
 
+func TestInMemDaySchedStore_CreateNewDaySchedule__existing_date(t *testing.T) {
+	// Arrange
+	dayStore, err := store.NewInMemDaySchedStore()
+	require.NoError(t, err)
+	err = dayStore.CreateNewDaySchedule(context.Background(), testDaySchedule)
+	require.NoError(t, err)
+	otherShift := testDaySchedule.Shifts[0]
+	otherShift.ID = "2"
+	sameDateDay := testDaySchedule
+	sameDateDay.Shifts = []models.Shift{otherShift}
+
+	// Act
+	err = dayStore.CreateNewDaySchedule(context.Background(), sameDateDay)
+
+	// Assert
+	assert.ErrorIs(t, err, store.ErrAlreadyExists)
+	storedDaySchedule, err := dayStore.FindDaySchedule(context.Background(), testDaySchedule.Date)
+	require.NoError(t, err)
+	require.Len(t, storedDaySchedule, 1)
+	assert.Equal(t, testDaySchedule.WithSoldierRefs(), storedDaySchedule[0])
+}
+
 func TestInMemDaySchedStore_FindDaySchedule__found(t *testing.T) {
 	// Arrange
 	dayStore, err := store.NewInMemDaySchedStore()
 	require.NoError(t, err)
-	err = dayStore.CreateNewDaySchedule(testDaySchedule)
+	err = dayStore.CreateNewDaySchedule(context.Background(), testDaySchedule)
 	require.NoError(t, err)
 
 	// Act
-	result, err := dayStore.FindDaySchedule(testDaySchedule.Date)
+	result, err := dayStore.FindDaySchedule(context.Background(), testDaySchedule.Date)
 
 	// Assert
 	assert.NoError(t, err)
 	require.Len(t, result, 1)
-	assert.Equal(t, testDaySchedule, result[0])
+	assert.Equal(t, testDaySchedule.WithSoldierRefs(), result[0])
 }
 
 func TestInMemDaySchedStore_FindDaySchedule__not_found(t *testing.T) {
@@ -94,7 +118,7 @@ func TestInMemDaySchedStore_FindDaySchedule__not_found(t *testing.T) {
 	date := time.Date(2023, time.May, 15, 0, 0, 0, 0, time.UTC)
 
 	// Act
-	result, err := dayStore.FindDaySchedule(date)
+	result, err := dayStore.FindDaySchedule(context.Background(), date)
 
 	// Assert
 	assert.NoError(t, err)
@@ -106,22 +130,44 @@ func TestInMemDaySchedStore_UpdateDaySchedule__success(t *testing.T) {
 	dayStore, err := store.NewInMemDaySchedStore()
 	require.NoError(t, err)
 	require.NotNil(t, dayStore)
-	err = dayStore.CreateNewDaySchedule(testDaySchedule)
+	err = dayStore.CreateNewDaySchedule(context.Background(), testDaySchedule)
 	require.NoError(t, err)
 	updatedDaySchedule := testDaySchedule
 	updatedDaySchedule.Shifts = append(updatedDaySchedule.Shifts, updatedDaySchedule.Shifts[0])
 	updatedDaySchedule.Shifts[1].StartTime = updatedDaySchedule.Shifts[1].StartTime.Add(2 * time.Hour)
 	updatedDaySchedule.Shifts[1].EndTime = updatedDaySchedule.Shifts[1].EndTime.Add(3 * time.Hour)
+	updatedDaySchedule.Version = 1
 
 	// Act
-	err = dayStore.UpdateDaySchedule(updatedDaySchedule)
+	err = dayStore.UpdateDaySchedule(context.Background(), updatedDaySchedule)
 
 	// Assert
 	assert.NoError(t, err)
-	storedDaySchedule, err := dayStore.FindDaySchedule(testDaySchedule.Date)
+	storedDaySchedule, err := dayStore.FindDaySchedule(context.Background(), testDaySchedule.Date)
 	require.NoError(t, err)
 	require.Len(t, storedDaySchedule, 1)
-	assert.Equal(t, updatedDaySchedule, storedDaySchedule[0])
+	updatedDaySchedule.Version = 2
+	assert.Equal(t, updatedDaySchedule.WithSoldierRefs(), storedDaySchedule[0])
+}
+
+func TestInMemDaySchedStore_UpdateDaySchedule__stale_version(t *testing.T) {
+	// Arrange
+	dayStore, err := store.NewInMemDaySchedStore()
+	require.NoError(t, err)
+	err = dayStore.CreateNewDaySchedule(context.Background(), testDaySchedule)
+	require.NoError(t, err)
+	err = dayStore.UpdateDaySchedule(context.Background(), testDaySchedule)
+	require.NoError(t, err)
+
+	// Act
+	err = dayStore.UpdateDaySchedule(context.Background(), testDaySchedule)
+
+	// Assert
+	assert.ErrorIs(t, err, store.ErrVersionConflict)
+	storedDaySchedule, err := dayStore.FindDaySchedule(context.Background(), testDaySchedule.Date)
+	require.NoError(t, err)
+	require.Len(t, storedDaySchedule, 1)
+	assert.Equal(t, 2, storedDaySchedule[0].Version)
 }
 
 func TestInMemDaySchedStore_UpdateDaySchedule__not_found(t *testing.T) {
@@ -131,11 +177,11 @@ func TestInMemDaySchedStore_UpdateDaySchedule__not_found(t *testing.T) {
 	require.NotNil(t, dayStore)
 
 	// Act
-	err = dayStore.UpdateDaySchedule(testDaySchedule)
+	err = dayStore.UpdateDaySchedule(context.Background(), testDaySchedule)
 
 	// Assert
 	assert.Error(t, err)
-	storedDaySchedule, err := dayStore.FindDaySchedule(testDaySchedule.Date)
+	storedDaySchedule, err := dayStore.FindDaySchedule(context.Background(), testDaySchedule.Date)
 	assert.NoError(t, err)
 	assert.Empty(t, storedDaySchedule)
 }
@@ -145,7 +191,7 @@ func TestInMemDaySchedStore_UpdateDaySchedule__invalid_schedule(t *testing.T) {
 	dayStore, err := store.NewInMemDaySchedStore()
 	require.NoError(t, err)
 	require.NotNil(t, dayStore)
-	err = dayStore.CreateNewDaySchedule(testDaySchedule)
+	err = dayStore.CreateNewDaySchedule(context.Background(), testDaySchedule)
 	require.NoError(t, err)
 	invalidDaySchedule := models.DaySchedule{
 		Date: testDaySchedule.Date,
@@ -155,14 +201,14 @@ func TestInMemDaySchedStore_UpdateDaySchedule__invalid_schedule(t *testing.T) {
 	}
 
 	// Act
-	err = dayStore.UpdateDaySchedule(invalidDaySchedule)
+	err = dayStore.UpdateDaySchedule(context.Background(), invalidDaySchedule)
 
 	// Assert
 	assert.Error(t, err)
-	storedDaySchedule, err := dayStore.FindDaySchedule(testDaySchedule.Date)
+	storedDaySchedule, err := dayStore.FindDaySchedule(context.Background(), testDaySchedule.Date)
 	require.NoError(t, err)
 	require.Len(t, storedDaySchedule, 1)
-	assert.Equal(t, testDaySchedule, storedDaySchedule[0]) // Original schedule unchanged
+	assert.Equal(t, testDaySchedule.WithSoldierRefs(), storedDaySchedule[0]) // Original schedule unchanged
 }
 
 func TestInMemDaySchedStore_FindAllDaySchedules__success(t *testing.T) {
@@ -173,17 +219,17 @@ func TestInMemDaySchedStore_FindAllDaySchedules__success(t *testing.T) {
 	anotherDaySched.Shifts[0].ID = "2"
 	anotherDaySched.Date = anotherDaySched.Date.Add(time.Hour * 24)
 
-	err = dayStore.CreateNewDaySchedule(testDaySchedule)
+	err = dayStore.CreateNewDaySchedule(context.Background(), testDaySchedule)
 	require.NoError(t, err)
-	err = dayStore.CreateNewDaySchedule(anotherDaySched)
+	err = dayStore.CreateNewDaySchedule(context.Background(), anotherDaySched)
 	require.NoError(t, err)
 
 	// Act
-	daySchedules, err := dayStore.FindAllDaySchedules()
+	daySchedules, err := dayStore.FindAllDaySchedules(context.Background())
 
 	// Assert
 	assert.NoError(t, err)
-	assert.ElementsMatch(t, []models.DaySchedule{testDaySchedule, anotherDaySched}, daySchedules)
+	assert.ElementsMatch(t, []models.DaySchedule{testDaySchedule.WithSoldierRefs(), anotherDaySched.WithSoldierRefs()}, daySchedules)
 }
 
 func TestInMemDaySchedStore_FindAllDaySchedules__empty(t *testing.T) {
@@ -192,7 +238,7 @@ func TestInMemDaySchedStore_FindAllDaySchedules__empty(t *testing.T) {
 	require.NoError(t, err)
 
 	// Act
-	daySchedules, err := dayStore.FindAllDaySchedules()
+	daySchedules, err := dayStore.FindAllDaySchedules(context.Background())
 
 	// Assert
 	assert.NoError(t, err)
@@ -203,19 +249,38 @@ func TestInMemDaySchedStore_DeleteDaySchedule__success(t *testing.T) {
 	// Arrange
 	dayStore, err := store.NewInMemDaySchedStore()
 	require.NoError(t, err)
-	err = dayStore.CreateNewDaySchedule(testDaySchedule)
+	err = dayStore.CreateNewDaySchedule(context.Background(), testDaySchedule)
 	require.NoError(t, err)
 
 	// Act
-	err = dayStore.DeleteDaySchedule(testDaySchedule.Date)
+	err = dayStore.DeleteDaySchedule(context.Background(), testDaySchedule.Date, 1)
 
 	// Assert
 	assert.NoError(t, err)
-	storedDaySchedule, err := dayStore.FindDaySchedule(testDaySchedule.Date)
+	storedDaySchedule, err := dayStore.FindDaySchedule(context.Background(), testDaySchedule.Date)
 	require.NoError(t, err)
 	assert.Empty(t, storedDaySchedule)
 }
 
+func TestInMemDaySchedStore_DeleteDaySchedule__stale_version(t *testing.T) {
+	// Arrange
+	dayStore, err := store.NewInMemDaySchedStore()
+	require.NoError(t, err)
+	err = dayStore.CreateNewDaySchedule(context.Background(), testDaySchedule)
+	require.NoError(t, err)
+	err = dayStore.UpdateDaySchedule(context.Background(), testDaySchedule)
+	require.NoError(t, err)
+
+	// Act
+	err = dayStore.DeleteDaySchedule(context.Background(), testDaySchedule.Date, 1)
+
+	// Assert
+	assert.ErrorIs(t, err, store.ErrVersionConflict)
+	storedDaySchedule, err := dayStore.FindDaySchedule(context.Background(), testDaySchedule.Date)
+	require.NoError(t, err)
+	assert.Len(t, storedDaySchedule, 1)
+}
+
 func TestInMemDaySchedStore_DeleteDaySchedule__not_found(t *testing.T) {
 	// Arrange
 	dayStore, err := store.NewInMemDaySchedStore()
@@ -223,7 +288,7 @@ func TestInMemDaySchedStore_DeleteDaySchedule__not_found(t *testing.T) {
 	date := time.Date(2023, time.May, 15, 0, 0, 0, 0, time.UTC)
 
 	// Act
-	err = dayStore.DeleteDaySchedule(date)
+	err = dayStore.DeleteDaySchedule(context.Background(), date, 1)
 
 	// Assert
 	assert.Error(t, err)
@@ -0,0 +1,11 @@
+package store
+
+import "github.com/pkg/errors"
+
+// ErrAlreadyExists is returned when creating an entity whose key is already taken. Day schedules are keyed by their
+// date, so an existing day schedule has to be updated, with its version, rather than created again.
+var ErrAlreadyExists = errors.New("entity already exists")
+
+func alreadyExists(entity, key string) error {
+	return errors.Wrapf(ErrAlreadyExists, "%s %s", entity, key)
+}
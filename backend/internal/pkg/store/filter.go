@@ -0,0 +1,79 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"time"
+)
+
+// ShiftFilter narrows down the shifts FindShifts returns. Zero valued fields match every shift.
+type ShiftFilter struct {
+	// From and To bound the time range the shifts take place in, even partially. To is exclusive.
+	From time.Time
+	To   time.Time
+	// SoldierID matches the shifts the soldier is staffed in, either as the commander or as an additional soldier
+	SoldierID string
+	// Type is a pointer since the zero ShiftType is a valid type
+	Type            *models.ShiftType
+	ShiftTemplateID string
+}
+
+func (f ShiftFilter) IsEmpty() bool {
+	return f == ShiftFilter{}
+}
+
+func (f ShiftFilter) Matches(shift models.Shift) bool {
+	if !f.From.IsZero() && !shift.EndTime.After(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !shift.StartTime.Before(f.To) {
+		return false
+	}
+	if f.SoldierID != "" && !shift.HasSoldier(f.SoldierID) {
+		return false
+	}
+	if f.Type != nil && shift.Type != *f.Type {
+		return false
+	}
+	if f.ShiftTemplateID != "" && shift.ShiftTemplateID != f.ShiftTemplateID {
+		return false
+	}
+	return true
+}
+
+// DayScheduleFilter narrows down the day schedules FindDaySchedules returns. Zero valued fields match every day schedule.
+type DayScheduleFilter struct {
+	// From and To bound the dates of the day schedules. To is exclusive.
+	From time.Time
+	To   time.Time
+	// Shifts narrows down the shifts of every day schedule. Unless it is empty, day schedules left with no shifts are
+	// not returned.
+	Shifts ShiftFilter
+}
+
+func (f DayScheduleFilter) matchesDate(date time.Time) bool {
+	if !f.From.IsZero() && normalizeDate(date) < normalizeDate(f.From) {
+		return false
+	}
+	return f.To.IsZero() || normalizeDate(date) < normalizeDate(f.To)
+}
+
+// filterShifts narrows down the shifts of days by f.Shifts
+func (f DayScheduleFilter) filterShifts(days []models.DaySchedule) []models.DaySchedule {
+	if f.Shifts.IsEmpty() {
+		return days
+	}
+	filtered := make([]models.DaySchedule, 0, len(days))
+	for _, day := range days {
+		shifts := make([]models.Shift, 0, len(day.Shifts))
+		for _, shift := range day.Shifts {
+			if f.Shifts.Matches(shift) {
+				shifts = append(shifts, shift)
+			}
+		}
+		if len(shifts) > 0 {
+			day.Shifts = shifts
+			filtered = append(filtered, day)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,153 @@
+package store_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFilterTestShifts returns shifts that tell apart every ShiftFilter field. The night shift is written in a non UTC
+// zone and with fractional seconds, to make sure times are compared as times.
+func newFilterTestShifts() []models.Shift {
+	israelZone := time.FixedZone("IDT", 3*60*60)
+	morningShift := testShiftModel
+	morningShift.ID = "morning"
+	morningShift.StartTime = time.Date(2026, time.October, 1, 8, 0, 0, 0, time.UTC)
+	morningShift.EndTime = time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	nightShift := testShiftModel
+	nightShift.ID = "night"
+	nightShift.Type = models.StaticPostShiftType
+	nightShift.ShiftTemplateID = "template"
+	nightShift.Commander = testCommander
+	nightShift.AdditionalSoldiers = []models.Soldier{testSoldier}
+	nightShift.StartTime = time.Date(2026, time.October, 8, 1, 30, 0, 500, israelZone)
+	nightShift.EndTime = time.Date(2026, time.October, 8, 5, 30, 0, 500, israelZone)
+	laterShift := testShiftModel
+	laterShift.ID = "later"
+	laterShift.Commander = testCommander
+	laterShift.StartTime = time.Date(2026, time.October, 9, 8, 0, 0, 0, time.UTC)
+	laterShift.EndTime = time.Date(2026, time.October, 9, 12, 0, 0, 0, time.UTC)
+	return []models.Shift{morningShift, nightShift, laterShift}
+}
+
+func TestShiftStores_FindShifts(t *testing.T) {
+	staticPost := models.StaticPostShiftType
+	testCases := []struct {
+		name        string
+		filter      store.ShiftFilter
+		expectedIDs []string
+	}{
+		{name: "empty filter", filter: store.ShiftFilter{}, expectedIDs: []string{"later", "morning", "night"}},
+		{name: "time range", filter: store.ShiftFilter{
+			From: time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC),
+			To:   time.Date(2026, time.October, 8, 0, 0, 0, 0, time.UTC),
+		}, expectedIDs: []string{"morning", "night"}},
+		{name: "range end is exclusive", filter: store.ShiftFilter{
+			To: time.Date(2026, time.October, 1, 8, 0, 0, 0, time.UTC),
+		}, expectedIDs: []string{}},
+		{name: "additional soldier", filter: store.ShiftFilter{SoldierID: testSoldier.ID},
+			expectedIDs: []string{"morning", "night"}},
+		{name: "commander", filter: store.ShiftFilter{SoldierID: testCommander.ID},
+			expectedIDs: []string{"later", "night"}},
+		{name: "type", filter: store.ShiftFilter{Type: &staticPost}, expectedIDs: []string{"night"}},
+		{name: "template", filter: store.ShiftFilter{ShiftTemplateID: "template"}, expectedIDs: []string{"night"}},
+		{name: "all fields", filter: store.ShiftFilter{
+			From:      time.Date(2026, time.October, 8, 0, 0, 0, 0, time.UTC),
+			SoldierID: testCommander.ID,
+			Type:      &staticPost,
+		}, expectedIDs: []string{"night"}},
+	}
+	inMemShiftStore, err := store.NewShiftStore()
+	require.NoError(t, err)
+	db, _ := openTestSQLiteDB(t)
+	sqliteShiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	shiftStores := map[string]store.IShiftStore{"in memory": inMemShiftStore, "sqlite": sqliteShiftStore}
+	for _, shiftStore := range shiftStores {
+		for _, shift := range newFilterTestShifts() {
+			require.NoError(t, shiftStore.CreateNewShift(context.Background(), shift))
+		}
+	}
+	for storeName, shiftStore := range shiftStores {
+		for _, testCase := range testCases {
+			t.Run(storeName+" "+testCase.name, func(t *testing.T) {
+				// Act
+				foundShifts, err := shiftStore.FindShifts(context.Background(), testCase.filter)
+
+				// Assert
+				assert.NoError(t, err)
+				foundIDs := make([]string, 0, len(foundShifts))
+				for _, shift := range foundShifts {
+					foundIDs = append(foundIDs, shift.ID)
+				}
+				sort.Strings(foundIDs)
+				assert.Equal(t, testCase.expectedIDs, foundIDs)
+			})
+		}
+	}
+}
+
+func TestShiftStores_FindShifts__hides_deleted_shifts(t *testing.T) {
+	// Arrange
+	inMemShiftStore, err := store.NewShiftStore()
+	require.NoError(t, err)
+	db, _ := openTestSQLiteDB(t)
+	sqliteShiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	for storeName, shiftStore := range map[string]store.IShiftStore{"in memory": inMemShiftStore, "sqlite": sqliteShiftStore} {
+		t.Run(storeName, func(t *testing.T) {
+			require.NoError(t, shiftStore.CreateNewShift(context.Background(), testShiftModel))
+			require.NoError(t, shiftStore.DeleteShift(context.Background(), testShiftModel.ID, 1))
+
+			// Act
+			foundShifts, err := shiftStore.FindShifts(context.Background(), store.ShiftFilter{SoldierID: testSoldier.ID})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Empty(t, foundShifts)
+		})
+	}
+}
+
+func TestDaySchedStores_FindDaySchedules(t *testing.T) {
+	// Arrange
+	shifts := newFilterTestShifts()
+	firstDay := models.DaySchedule{Date: time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), Shifts: shifts[:1]}
+	secondDay := models.DaySchedule{Date: time.Date(2026, time.October, 8, 0, 0, 0, 0, time.UTC), Shifts: shifts[1:]}
+	filter := store.DayScheduleFilter{
+		From:   time.Date(2026, time.October, 2, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2026, time.October, 9, 0, 0, 0, 0, time.UTC),
+		Shifts: store.ShiftFilter{SoldierID: testSoldier.ID},
+	}
+	inMemDayStore, err := store.NewInMemDaySchedStore()
+	require.NoError(t, err)
+	db, _ := openTestSQLiteDB(t)
+	sqliteDayStore, err := store.NewSQLiteDaySchedStore(db)
+	require.NoError(t, err)
+	for storeName, dayStore := range map[string]store.IDayStore{"in memory": inMemDayStore, "sqlite": sqliteDayStore} {
+		t.Run(storeName, func(t *testing.T) {
+			require.NoError(t, dayStore.CreateNewDaySchedule(context.Background(), firstDay))
+			require.NoError(t, dayStore.CreateNewDaySchedule(context.Background(), secondDay))
+
+			// Act
+			foundDays, err := dayStore.FindDaySchedules(context.Background(), filter)
+
+			// Assert
+			assert.NoError(t, err)
+			require.Len(t, foundDays, 1)
+			assert.Equal(t, normalizedDate(secondDay.Date), normalizedDate(foundDays[0].Date))
+			require.Len(t, foundDays[0].Shifts, 1)
+			assert.Equal(t, "night", foundDays[0].Shifts[0].ID)
+		})
+	}
+}
+
+func normalizedDate(date time.Time) string {
+	return date.Format("2006-01-02")
+}
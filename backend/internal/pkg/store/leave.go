@@ -0,0 +1,95 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type ILeaveStore interface {
+	CreateNewLeave(ctx context.Context, leave models.Leave) error
+	FindLeaveByID(ctx context.Context, id string) ([]models.Leave, error)
+	FindLeavesBySoldierID(ctx context.Context, soldierID string) ([]models.Leave, error)
+	FindAllLeaves(ctx context.Context) ([]models.Leave, error)
+	UpdateLeave(ctx context.Context, leave models.Leave) error
+	DeleteLeave(ctx context.Context, id string) error
+}
+
+type InMemLeaveStore struct {
+	mu     sync.RWMutex
+	leaves map[string]models.Leave
+}
+
+func NewLeaveStore() (*InMemLeaveStore, error) {
+	return &InMemLeaveStore{leaves: make(map[string]models.Leave)}, nil
+}
+
+func (s *InMemLeaveStore) CreateNewLeave(ctx context.Context, leave models.Leave) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := leave.IsValid(); err != nil {
+		return errors.Wrap(err, "leave validation failed")
+	}
+	if _, exists := s.leaves[leave.ID]; exists {
+		return errors.New("leave already exists")
+	}
+	s.leaves[leave.ID] = leave
+	return nil
+}
+
+func (s *InMemLeaveStore) FindLeaveByID(ctx context.Context, id string) ([]models.Leave, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if leave, exists := s.leaves[id]; !exists {
+		return []models.Leave{}, nil
+	} else {
+		return []models.Leave{leave}, nil
+	}
+}
+
+func (s *InMemLeaveStore) FindLeavesBySoldierID(ctx context.Context, soldierID string) ([]models.Leave, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	leaves := make([]models.Leave, 0)
+	for _, leave := range s.leaves {
+		if leave.SoldierID == soldierID {
+			leaves = append(leaves, leave)
+		}
+	}
+	return leaves, nil
+}
+
+func (s *InMemLeaveStore) FindAllLeaves(ctx context.Context) ([]models.Leave, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	leaves := make([]models.Leave, 0, len(s.leaves))
+	for _, leave := range s.leaves {
+		leaves = append(leaves, leave)
+	}
+	return leaves, nil
+}
+
+func (s *InMemLeaveStore) UpdateLeave(ctx context.Context, leave models.Leave) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := leave.IsValid(); err != nil {
+		return errors.Wrap(err, "leave validation failed")
+	}
+	if _, exists := s.leaves[leave.ID]; !exists {
+		return errors.New("leave not found")
+	}
+	s.leaves[leave.ID] = leave
+	return nil
+}
+
+func (s *InMemLeaveStore) DeleteLeave(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.leaves[id]; !exists {
+		return errors.New("leave not found")
+	}
+	delete(s.leaves, id)
+	return nil
+}
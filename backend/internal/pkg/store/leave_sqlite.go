@@ -0,0 +1,64 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+)
+
+type SQLiteLeaveStore struct {
+	leaves jsonTable[models.Leave]
+}
+
+func NewSQLiteLeaveStore(db *sql.DB) (*SQLiteLeaveStore, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return newSQLiteLeaveStore(db), nil
+}
+
+func newSQLiteLeaveStore(db sqlExecutor) *SQLiteLeaveStore {
+	return &SQLiteLeaveStore{leaves: jsonTable[models.Leave]{db: db, name: "leaves", keyColumn: "id"}}
+}
+
+func (s *SQLiteLeaveStore) CreateNewLeave(ctx context.Context, leave models.Leave) error {
+	if err := leave.IsValid(); err != nil {
+		return errors.Wrap(err, "leave validation failed")
+	}
+	return s.leaves.insert(ctx, leave.ID, leave)
+}
+
+func (s *SQLiteLeaveStore) FindLeaveByID(ctx context.Context, id string) ([]models.Leave, error) {
+	return s.leaves.find(ctx, id)
+}
+
+func (s *SQLiteLeaveStore) FindLeavesBySoldierID(ctx context.Context, soldierID string) ([]models.Leave, error) {
+	all, err := s.leaves.findAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	leaves := make([]models.Leave, 0)
+	for _, leave := range all {
+		if leave.SoldierID == soldierID {
+			leaves = append(leaves, leave)
+		}
+	}
+	return leaves, nil
+}
+
+func (s *SQLiteLeaveStore) FindAllLeaves(ctx context.Context) ([]models.Leave, error) {
+	return s.leaves.findAll(ctx)
+}
+
+func (s *SQLiteLeaveStore) UpdateLeave(ctx context.Context, leave models.Leave) error {
+	if err := leave.IsValid(); err != nil {
+		return errors.Wrap(err, "leave validation failed")
+	}
+	return s.leaves.update(ctx, leave.ID, leave)
+}
+
+func (s *SQLiteLeaveStore) DeleteLeave(ctx context.Context, id string) error {
+	return s.leaves.delete(ctx, id)
+}
@@ -0,0 +1,94 @@
+package store_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLeave = models.Leave{
+	ID:        "leave",
+	SoldierID: testSoldier.ID,
+	Type:      models.HomeLeaveType,
+	StartTime: time.Date(2025, time.April, 9, 8, 0, 0, 0, time.UTC),
+	EndTime:   time.Date(2025, time.April, 12, 8, 0, 0, 0, time.UTC),
+}
+
+func TestInMemLeaveStore_CreateNewLeave__invalid_leave(t *testing.T) {
+	// Arrange
+	leaveStore, err := store.NewLeaveStore()
+	require.NoError(t, err)
+	leave := testLeave
+	leave.EndTime = leave.StartTime.Add(-time.Hour)
+
+	// Act
+	err = leaveStore.CreateNewLeave(context.Background(), leave)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestInMemLeaveStore_CreateNewLeave__duplicate_id(t *testing.T) {
+	// Arrange
+	leaveStore, err := store.NewLeaveStore()
+	require.NoError(t, err)
+	err = leaveStore.CreateNewLeave(context.Background(), testLeave)
+	require.NoError(t, err)
+
+	// Act
+	err = leaveStore.CreateNewLeave(context.Background(), testLeave)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestInMemLeaveStore_FindLeavesBySoldierID(t *testing.T) {
+	// Arrange
+	leaveStore, err := store.NewLeaveStore()
+	require.NoError(t, err)
+	otherSoldierLeave := testLeave
+	otherSoldierLeave.ID = "other"
+	otherSoldierLeave.SoldierID = "other-soldier"
+	require.NoError(t, leaveStore.CreateNewLeave(context.Background(), testLeave))
+	require.NoError(t, leaveStore.CreateNewLeave(context.Background(), otherSoldierLeave))
+
+	// Act
+	leaves, err := leaveStore.FindLeavesBySoldierID(context.Background(), testSoldier.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Leave{testLeave}, leaves)
+}
+
+func TestInMemLeaveStore_UpdateLeave__not_found(t *testing.T) {
+	// Arrange
+	leaveStore, err := store.NewLeaveStore()
+	require.NoError(t, err)
+
+	// Act
+	err = leaveStore.UpdateLeave(context.Background(), testLeave)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestInMemLeaveStore_DeleteLeave__success(t *testing.T) {
+	// Arrange
+	leaveStore, err := store.NewLeaveStore()
+	require.NoError(t, err)
+	require.NoError(t, leaveStore.CreateNewLeave(context.Background(), testLeave))
+
+	// Act
+	err = leaveStore.DeleteLeave(context.Background(), testLeave.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	leaves, err := leaveStore.FindLeaveByID(context.Background(), testLeave.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, leaves)
+}
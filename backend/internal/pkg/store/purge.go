@@ -0,0 +1,79 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/logging"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Purger permanently removes the shifts, soldiers and shift templates that were soft deleted longer than a retention
+// window ago. Until then they can be restored.
+type Purger struct {
+	shiftStore         IShiftStore
+	soldierStore       ISoldierStore
+	shiftTemplateStore IShiftTemplateStore
+	retention          time.Duration
+}
+
+func NewPurger(shiftStore IShiftStore, soldierStore ISoldierStore, shiftTemplateStore IShiftTemplateStore,
+	retention time.Duration) (*Purger, error) {
+	if shiftStore == nil {
+		return nil, errors.New("shiftStore is nil")
+	}
+	if soldierStore == nil {
+		return nil, errors.New("soldierStore is nil")
+	}
+	if shiftTemplateStore == nil {
+		return nil, errors.New("shiftTemplateStore is nil")
+	}
+	if retention <= 0 {
+		return nil, errors.New("retention must be positive")
+	}
+	return &Purger{
+		shiftStore:         shiftStore,
+		soldierStore:       soldierStore,
+		shiftTemplateStore: shiftTemplateStore,
+		retention:          retention,
+	}, nil
+}
+
+// Purge removes the entities whose retention window has passed by now
+func (p *Purger) Purge(ctx context.Context, now time.Time) error {
+	deletedBefore := now.Add(-p.retention)
+	purgedShifts, err := p.shiftStore.PurgeDeletedShifts(ctx, deletedBefore)
+	if err != nil {
+		return errors.Wrap(err, "could not purge deleted shifts")
+	}
+	purgedSoldiers, err := p.soldierStore.PurgeDeletedSoldiers(ctx, deletedBefore)
+	if err != nil {
+		return errors.Wrap(err, "could not purge deleted soldiers")
+	}
+	purgedShiftTemplates, err := p.shiftTemplateStore.PurgeDeletedShiftTemplates(ctx, deletedBefore)
+	if err != nil {
+		return errors.Wrap(err, "could not purge deleted shift templates")
+	}
+	if purgedShifts+purgedSoldiers+purgedShiftTemplates > 0 {
+		logging.Info("Purged deleted entities", []logging.LogProp{{"shifts", strconv.Itoa(purgedShifts)},
+			{"soldiers", strconv.Itoa(purgedSoldiers)}, {"shiftTemplates", strconv.Itoa(purgedShiftTemplates)}})
+	}
+	return nil
+}
+
+// Run purges every interval, until ctx is done
+func (p *Purger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := p.Purge(ctx, now); err != nil {
+				logging.Error(err, "could not purge deleted entities", nil)
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package store_test
+
+import (
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurger_Purge__removes_entities_past_retention(t *testing.T) {
+	// Arrange
+	stores := newTestInMemStores(t)
+	err := stores.Shifts.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+	err = stores.Shifts.DeleteShift(context.Background(), testShiftModel.ID, 1)
+	require.NoError(t, err)
+	purger, err := store.NewPurger(stores.Shifts, stores.Soldiers, stores.ShiftTemplates, 24*time.Hour)
+	require.NoError(t, err)
+
+	// Act
+	withinRetentionErr := purger.Purge(context.Background(), time.Now())
+	restorableErr := stores.Shifts.RestoreShift(context.Background(), testShiftModel.ID)
+	err = stores.Shifts.DeleteShift(context.Background(), testShiftModel.ID, 3)
+	require.NoError(t, err)
+	pastRetentionErr := purger.Purge(context.Background(), time.Now().Add(48*time.Hour))
+
+	// Assert
+	assert.NoError(t, withinRetentionErr)
+	assert.NoError(t, restorableErr)
+	assert.NoError(t, pastRetentionErr)
+	assert.ErrorIs(t, stores.Shifts.RestoreShift(context.Background(), testShiftModel.ID), store.ErrNotDeleted)
+}
+
+func TestPurger_NewPurger__sad_flows(t *testing.T) {
+	// Arrange
+	stores := newTestInMemStores(t)
+
+	// Act
+	nilShiftStorePurger, nilShiftStoreErr := store.NewPurger(nil, stores.Soldiers, stores.ShiftTemplates, time.Hour)
+	zeroRetentionPurger, zeroRetentionErr := store.NewPurger(stores.Shifts, stores.Soldiers, stores.ShiftTemplates, 0)
+
+	// Assert
+	assert.Error(t, nilShiftStoreErr)
+	assert.Nil(t, nilShiftStorePurger)
+	assert.Error(t, zeroRetentionErr)
+	assert.Nil(t, zeroRetentionPurger)
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/logging"
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type IRevokedTokenStore interface {
+	// RevokeToken is idempotent - revoking a token that is already revoked is not an error. newlyRevoked tells whether
+	// this call is the one that revoked the token, so that of concurrent calls revoking the same token only one wins.
+	RevokeToken(ctx context.Context, token models.RevokedToken) (newlyRevoked bool, err error)
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+	// PruneRevokedTokens forgets the revoked tokens that expired before expiredBefore, and returns how many were forgotten
+	PruneRevokedTokens(ctx context.Context, expiredBefore time.Time) (int, error)
+}
+
+type InMemRevokedTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]models.RevokedToken
+}
+
+func NewRevokedTokenStore() (*InMemRevokedTokenStore, error) {
+	return &InMemRevokedTokenStore{tokens: make(map[string]models.RevokedToken)}, nil
+}
+
+func (s *InMemRevokedTokenStore) RevokeToken(ctx context.Context, token models.RevokedToken) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token.ID == "" {
+		return false, errors.New("token ID is empty")
+	}
+	if _, exists := s.tokens[token.ID]; exists {
+		return false, nil
+	}
+	s.tokens[token.ID] = token
+	return true, nil
+}
+
+func (s *InMemRevokedTokenStore) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, revoked := s.tokens[tokenID]
+	return revoked, nil
+}
+
+func (s *InMemRevokedTokenStore) PruneRevokedTokens(ctx context.Context, expiredBefore time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pruned := 0
+	for id, token := range s.tokens {
+		if token.ExpiresAt.Before(expiredBefore) {
+			delete(s.tokens, id)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// TokenPruner periodically forgets the revoked tokens that have expired. Expired tokens are rejected regardless of
+// whether they were revoked, so there is no point keeping them.
+type TokenPruner struct {
+	revokedTokenStore IRevokedTokenStore
+}
+
+func NewTokenPruner(revokedTokenStore IRevokedTokenStore) (*TokenPruner, error) {
+	if revokedTokenStore == nil {
+		return nil, errors.New("revokedTokenStore is nil")
+	}
+	return &TokenPruner{revokedTokenStore: revokedTokenStore}, nil
+}
+
+// Prune forgets the revoked tokens that expired by now
+func (p *TokenPruner) Prune(ctx context.Context, now time.Time) error {
+	pruned, err := p.revokedTokenStore.PruneRevokedTokens(ctx, now)
+	if err != nil {
+		return errors.Wrap(err, "could not prune revoked tokens")
+	}
+	if pruned > 0 {
+		logging.Debug("Pruned expired revoked tokens", []logging.LogProp{{"tokens", strconv.Itoa(pruned)}})
+	}
+	return nil
+}
+
+// Run prunes every interval, until ctx is done
+func (p *TokenPruner) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := p.Prune(ctx, now); err != nil {
+				logging.Error(err, "could not prune revoked tokens", nil)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type SQLiteRevokedTokenStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteRevokedTokenStore(db *sql.DB) (*SQLiteRevokedTokenStore, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return &SQLiteRevokedTokenStore{db: db}, nil
+}
+
+func (s *SQLiteRevokedTokenStore) RevokeToken(ctx context.Context, token models.RevokedToken) (bool, error) {
+	if token.ID == "" {
+		return false, errors.New("token ID is empty")
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return false, errors.Wrap(err, "could not marshal revoked token")
+	}
+	result, err := s.db.ExecContext(ctx, `INSERT INTO revoked_tokens (id, data) VALUES (?, ?) ON CONFLICT (id) DO NOTHING`,
+		token.ID, string(data))
+	if err != nil {
+		return false, errors.Wrap(err, "could not insert into revoked_tokens")
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "could not count affected rows")
+	}
+	return inserted > 0, nil
+}
+
+func (s *SQLiteRevokedTokenStore) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	var revoked bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE id = ?)`, tokenID).Scan(&revoked)
+	if err != nil {
+		return false, errors.Wrap(err, "could not query revoked_tokens")
+	}
+	return revoked, nil
+}
+
+// PruneRevokedTokens compares expiry times as times rather than as text, the same way as purgeDeleted
+func (s *SQLiteRevokedTokenStore) PruneRevokedTokens(ctx context.Context, expiredBefore time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM revoked_tokens WHERE julianday(json_extract(data, '$.expiresAt')) < julianday(?)`,
+		expiredBefore.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return 0, errors.Wrap(err, "could not prune revoked_tokens")
+	}
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "could not count affected rows")
+	}
+	return int(pruned), nil
+}
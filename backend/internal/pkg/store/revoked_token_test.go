@@ -0,0 +1,87 @@
+package store_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRevokedTokenStores(t *testing.T) map[string]store.IRevokedTokenStore {
+	inMemStore, err := store.NewRevokedTokenStore()
+	require.NoError(t, err)
+	db, _ := openTestSQLiteDB(t)
+	sqliteStore, err := store.NewSQLiteRevokedTokenStore(db)
+	require.NoError(t, err)
+	return map[string]store.IRevokedTokenStore{"in memory": inMemStore, "sqlite": sqliteStore}
+}
+
+func TestRevokedTokenStores_RevokeToken(t *testing.T) {
+	for storeName, revokedTokenStore := range newTestRevokedTokenStores(t) {
+		t.Run(storeName, func(t *testing.T) {
+			// Arrange
+			token := models.RevokedToken{ID: "revoked", ExpiresAt: time.Now().Add(time.Hour)}
+
+			// Act
+			firstRevoked, firstErr := revokedTokenStore.RevokeToken(context.Background(), token)
+			secondRevoked, secondErr := revokedTokenStore.RevokeToken(context.Background(), token)
+
+			// Assert
+			assert.NoError(t, firstErr)
+			assert.True(t, firstRevoked)
+			assert.NoError(t, secondErr)
+			assert.False(t, secondRevoked)
+			revoked, err := revokedTokenStore.IsTokenRevoked(context.Background(), "revoked")
+			assert.NoError(t, err)
+			assert.True(t, revoked)
+			revoked, err = revokedTokenStore.IsTokenRevoked(context.Background(), "other")
+			assert.NoError(t, err)
+			assert.False(t, revoked)
+		})
+	}
+}
+
+func TestRevokedTokenStores_RevokeToken__empty_id(t *testing.T) {
+	for storeName, revokedTokenStore := range newTestRevokedTokenStores(t) {
+		t.Run(storeName, func(t *testing.T) {
+			// Act
+			_, err := revokedTokenStore.RevokeToken(context.Background(), models.RevokedToken{ExpiresAt: time.Now()})
+
+			// Assert
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRevokedTokenStores_PruneRevokedTokens(t *testing.T) {
+	israelZone := time.FixedZone("IDT", 3*60*60)
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	for storeName, revokedTokenStore := range newTestRevokedTokenStores(t) {
+		t.Run(storeName, func(t *testing.T) {
+			// Arrange
+			_, err := revokedTokenStore.RevokeToken(context.Background(),
+				models.RevokedToken{ID: "expired", ExpiresAt: now.Add(-time.Minute).In(israelZone)})
+			require.NoError(t, err)
+			_, err = revokedTokenStore.RevokeToken(context.Background(),
+				models.RevokedToken{ID: "valid", ExpiresAt: now.Add(time.Minute).In(israelZone)})
+			require.NoError(t, err)
+
+			// Act
+			pruned, err := revokedTokenStore.PruneRevokedTokens(context.Background(), now)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 1, pruned)
+			expiredRevoked, err := revokedTokenStore.IsTokenRevoked(context.Background(), "expired")
+			assert.NoError(t, err)
+			assert.False(t, expiredRevoked)
+			validRevoked, err := revokedTokenStore.IsTokenRevoked(context.Background(), "valid")
+			assert.NoError(t, err)
+			assert.True(t, validRevoked)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type IRotationStore interface {
+	CreateNewRotation(ctx context.Context, rotation models.Rotation) error
+	FindRotationByID(ctx context.Context, id string) ([]models.Rotation, error)
+	FindRotationsBySoldierID(ctx context.Context, soldierID string) ([]models.Rotation, error)
+	FindAllRotations(ctx context.Context) ([]models.Rotation, error)
+	UpdateRotation(ctx context.Context, rotation models.Rotation) error
+	DeleteRotation(ctx context.Context, id string) error
+}
+
+type InMemRotationStore struct {
+	mu        sync.RWMutex
+	rotations map[string]models.Rotation
+}
+
+func NewRotationStore() (*InMemRotationStore, error) {
+	return &InMemRotationStore{rotations: make(map[string]models.Rotation)}, nil
+}
+
+func (s *InMemRotationStore) CreateNewRotation(ctx context.Context, rotation models.Rotation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := rotation.IsValid(); err != nil {
+		return errors.Wrap(err, "rotation validation failed")
+	}
+	if _, exists := s.rotations[rotation.ID]; exists {
+		return errors.New("rotation already exists")
+	}
+	s.rotations[rotation.ID] = rotation
+	return nil
+}
+
+func (s *InMemRotationStore) FindRotationByID(ctx context.Context, id string) ([]models.Rotation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if rotation, exists := s.rotations[id]; !exists {
+		return []models.Rotation{}, nil
+	} else {
+		return []models.Rotation{rotation}, nil
+	}
+}
+
+func (s *InMemRotationStore) FindRotationsBySoldierID(ctx context.Context, soldierID string) ([]models.Rotation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rotations := make([]models.Rotation, 0)
+	for _, rotation := range s.rotations {
+		if rotation.HasSoldier(soldierID) {
+			rotations = append(rotations, rotation)
+		}
+	}
+	return rotations, nil
+}
+
+func (s *InMemRotationStore) FindAllRotations(ctx context.Context) ([]models.Rotation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rotations := make([]models.Rotation, 0, len(s.rotations))
+	for _, rotation := range s.rotations {
+		rotations = append(rotations, rotation)
+	}
+	return rotations, nil
+}
+
+func (s *InMemRotationStore) UpdateRotation(ctx context.Context, rotation models.Rotation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := rotation.IsValid(); err != nil {
+		return errors.Wrap(err, "rotation validation failed")
+	}
+	if _, exists := s.rotations[rotation.ID]; !exists {
+		return errors.New("rotation not found")
+	}
+	s.rotations[rotation.ID] = rotation
+	return nil
+}
+
+func (s *InMemRotationStore) DeleteRotation(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.rotations[id]; !exists {
+		return errors.New("rotation not found")
+	}
+	delete(s.rotations, id)
+	return nil
+}
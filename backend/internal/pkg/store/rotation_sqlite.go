@@ -0,0 +1,64 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+)
+
+type SQLiteRotationStore struct {
+	rotations jsonTable[models.Rotation]
+}
+
+func NewSQLiteRotationStore(db *sql.DB) (*SQLiteRotationStore, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return newSQLiteRotationStore(db), nil
+}
+
+func newSQLiteRotationStore(db sqlExecutor) *SQLiteRotationStore {
+	return &SQLiteRotationStore{rotations: jsonTable[models.Rotation]{db: db, name: "rotations", keyColumn: "id"}}
+}
+
+func (s *SQLiteRotationStore) CreateNewRotation(ctx context.Context, rotation models.Rotation) error {
+	if err := rotation.IsValid(); err != nil {
+		return errors.Wrap(err, "rotation validation failed")
+	}
+	return s.rotations.insert(ctx, rotation.ID, rotation)
+}
+
+func (s *SQLiteRotationStore) FindRotationByID(ctx context.Context, id string) ([]models.Rotation, error) {
+	return s.rotations.find(ctx, id)
+}
+
+func (s *SQLiteRotationStore) FindRotationsBySoldierID(ctx context.Context, soldierID string) ([]models.Rotation, error) {
+	all, err := s.rotations.findAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rotations := make([]models.Rotation, 0)
+	for _, rotation := range all {
+		if rotation.HasSoldier(soldierID) {
+			rotations = append(rotations, rotation)
+		}
+	}
+	return rotations, nil
+}
+
+func (s *SQLiteRotationStore) FindAllRotations(ctx context.Context) ([]models.Rotation, error) {
+	return s.rotations.findAll(ctx)
+}
+
+func (s *SQLiteRotationStore) UpdateRotation(ctx context.Context, rotation models.Rotation) error {
+	if err := rotation.IsValid(); err != nil {
+		return errors.Wrap(err, "rotation validation failed")
+	}
+	return s.rotations.update(ctx, rotation.ID, rotation)
+}
+
+func (s *SQLiteRotationStore) DeleteRotation(ctx context.Context, id string) error {
+	return s.rotations.delete(ctx, id)
+}
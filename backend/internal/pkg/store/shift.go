@@ -2,21 +2,30 @@ package store
 
 import (
 	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
-//TODO - accept ctx in signatures
-
 type IShiftStore interface {
-	CreateNewShift(shift models.Shift) error
-	FindShiftByID(id string) ([]models.Shift, error)
-	FindAllShifts() ([]models.Shift, error)
-	UpdateShift(shift models.Shift) error
-	DeleteShift(id string) error
+	CreateNewShift(ctx context.Context, shift models.Shift) error
+	FindShiftByID(ctx context.Context, id string) ([]models.Shift, error)
+	FindAllShifts(ctx context.Context) ([]models.Shift, error)
+	// FindShifts returns the shifts that match filter
+	FindShifts(ctx context.Context, filter ShiftFilter) ([]models.Shift, error)
+	UpdateShift(ctx context.Context, shift models.Shift) error
+	// DeleteShift soft deletes the shift - the Find methods no longer return it, but it can be restored until it is purged
+	DeleteShift(ctx context.Context, id string, version int) error
+	// RestoreShift undoes the deletion of a shift, or returns ErrNotDeleted if it is not deleted
+	RestoreShift(ctx context.Context, id string) error
+	// PurgeDeletedShifts permanently removes the shifts deleted before deletedBefore, and returns how many it removed
+	PurgeDeletedShifts(ctx context.Context, deletedBefore time.Time) (int, error)
 }
 
 type InMemShiftStore struct {
+	mu     sync.RWMutex
 	shifts map[string]models.Shift
 }
 
@@ -24,48 +33,114 @@ func NewShiftStore() (*InMemShiftStore, error) {
 	return &InMemShiftStore{shifts: make(map[string]models.Shift)}, nil
 }
 
-func (s *InMemShiftStore) CreateNewShift(shift models.Shift) error {
+func (s *InMemShiftStore) CreateNewShift(ctx context.Context, shift models.Shift) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if err := shift.IsValid(); err != nil {
 		return errors.Wrap(err, "shift validation failed")
 	}
 	if _, exists := s.shifts[shift.ID]; exists {
 		return errors.New("shift already exists")
 	}
+	shift = shift.WithSoldierRefs()
+	shift.Version = 1
+	shift.DeletedAt = nil
 	s.shifts[shift.ID] = shift
 	return nil
 }
 
-func (s *InMemShiftStore) FindShiftByID(id string) ([]models.Shift, error) {
-	if shift, exists := s.shifts[id]; !exists {
+func (s *InMemShiftStore) FindShiftByID(ctx context.Context, id string) ([]models.Shift, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if shift, exists := s.shifts[id]; !exists || shift.DeletedAt != nil {
 		return []models.Shift{}, nil
 	} else {
 		return []models.Shift{shift}, nil
 	}
 }
 
-func (s *InMemShiftStore) FindAllShifts() ([]models.Shift, error) {
+func (s *InMemShiftStore) FindAllShifts(ctx context.Context) ([]models.Shift, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	shifts := make([]models.Shift, 0, len(s.shifts))
 	for _, shift := range s.shifts {
+		if shift.DeletedAt != nil {
+			continue
+		}
+		shifts = append(shifts, shift)
+	}
+	return shifts, nil
+}
+
+func (s *InMemShiftStore) FindShifts(ctx context.Context, filter ShiftFilter) ([]models.Shift, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	shifts := make([]models.Shift, 0)
+	for _, shift := range s.shifts {
+		if shift.DeletedAt != nil || !filter.Matches(shift) {
+			continue
+		}
 		shifts = append(shifts, shift)
 	}
 	return shifts, nil
 }
 
-func (s *InMemShiftStore) UpdateShift(shift models.Shift) error {
+func (s *InMemShiftStore) UpdateShift(ctx context.Context, shift models.Shift) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if err := shift.IsValid(); err != nil {
 		return errors.Wrap(err, "shift validation failed")
 	}
-	if _, exists := s.shifts[shift.ID]; !exists {
+	if stored, exists := s.shifts[shift.ID]; !exists || stored.DeletedAt != nil {
 		return errors.New("shift not found")
+	} else if stored.Version != shift.Version {
+		return versionConflict("shift", shift.ID, stored.Version)
 	}
+	shift = shift.WithSoldierRefs()
+	shift.Version++
+	shift.DeletedAt = nil
 	s.shifts[shift.ID] = shift
 	return nil
 }
 
-func (s *InMemShiftStore) DeleteShift(id string) error {
-	if _, exists := s.shifts[id]; !exists {
+func (s *InMemShiftStore) DeleteShift(ctx context.Context, id string, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, exists := s.shifts[id]
+	if !exists || stored.DeletedAt != nil {
 		return errors.New("shift not found")
+	} else if stored.Version != version {
+		return versionConflict("shift", id, stored.Version)
 	}
-	delete(s.shifts, id)
+	deletedAt := time.Now().UTC()
+	stored.DeletedAt = &deletedAt
+	stored.Version++
+	s.shifts[id] = stored
 	return nil
 }
+
+func (s *InMemShiftStore) RestoreShift(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, exists := s.shifts[id]
+	if !exists || stored.DeletedAt == nil {
+		return notDeleted("shift", id)
+	}
+	stored.DeletedAt = nil
+	stored.Version++
+	s.shifts[id] = stored
+	return nil
+}
+
+func (s *InMemShiftStore) PurgeDeletedShifts(ctx context.Context, deletedBefore time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	purged := 0
+	for id, shift := range s.shifts {
+		if shift.DeletedAt != nil && shift.DeletedAt.Before(deletedBefore) {
+			delete(s.shifts, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
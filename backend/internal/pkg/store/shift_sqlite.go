@@ -0,0 +1,93 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type SQLiteShiftStore struct {
+	shifts jsonTable[models.Shift]
+}
+
+func NewSQLiteShiftStore(db *sql.DB) (*SQLiteShiftStore, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return newSQLiteShiftStore(db), nil
+}
+
+func newSQLiteShiftStore(db sqlExecutor) *SQLiteShiftStore {
+	return &SQLiteShiftStore{shifts: jsonTable[models.Shift]{db: db, name: "shifts", keyColumn: "id", softDelete: true}}
+}
+
+func (s *SQLiteShiftStore) CreateNewShift(ctx context.Context, shift models.Shift) error {
+	if err := shift.IsValid(); err != nil {
+		return errors.Wrap(err, "shift validation failed")
+	}
+	shift = shift.WithSoldierRefs()
+	shift.Version = 1
+	shift.DeletedAt = nil
+	return s.shifts.insert(ctx, shift.ID, shift)
+}
+
+func (s *SQLiteShiftStore) FindShiftByID(ctx context.Context, id string) ([]models.Shift, error) {
+	return s.shifts.find(ctx, id)
+}
+
+func (s *SQLiteShiftStore) FindAllShifts(ctx context.Context) ([]models.Shift, error) {
+	return s.shifts.findAll(ctx)
+}
+
+// FindShifts filters the shifts within the query. Times are compared as times rather than as text, the same way as when
+// purging deleted shifts.
+func (s *SQLiteShiftStore) FindShifts(ctx context.Context, filter ShiftFilter) ([]models.Shift, error) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+	if !filter.From.IsZero() {
+		conditions = append(conditions, `julianday(json_extract(data, '$.endTime')) > julianday(?)`)
+		args = append(args, filter.From.UTC().Format(time.RFC3339Nano))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, `julianday(json_extract(data, '$.startTime')) < julianday(?)`)
+		args = append(args, filter.To.UTC().Format(time.RFC3339Nano))
+	}
+	if filter.SoldierID != "" {
+		conditions = append(conditions, `(json_extract(data, '$.commander.id') = ? OR EXISTS (
+			SELECT 1 FROM json_each(data, '$.additionalSoldiers') WHERE json_extract(value, '$.id') = ?))`)
+		args = append(args, filter.SoldierID, filter.SoldierID)
+	}
+	if filter.Type != nil {
+		conditions = append(conditions, `json_extract(data, '$.type') = ?`)
+		args = append(args, int(*filter.Type))
+	}
+	if filter.ShiftTemplateID != "" {
+		conditions = append(conditions, `json_extract(data, '$.shiftTemplateId') = ?`)
+		args = append(args, filter.ShiftTemplateID)
+	}
+	return s.shifts.findWhere(ctx, conditions, args...)
+}
+
+func (s *SQLiteShiftStore) UpdateShift(ctx context.Context, shift models.Shift) error {
+	if err := shift.IsValid(); err != nil {
+		return errors.Wrap(err, "shift validation failed")
+	}
+	shift = shift.WithSoldierRefs()
+	shift.DeletedAt = nil
+	return s.shifts.updateVersioned(ctx, shift.ID, shift, shift.Version)
+}
+
+func (s *SQLiteShiftStore) DeleteShift(ctx context.Context, id string, version int) error {
+	return s.shifts.softDeleteVersioned(ctx, id, version)
+}
+
+func (s *SQLiteShiftStore) RestoreShift(ctx context.Context, id string) error {
+	return s.shifts.restore(ctx, id)
+}
+
+func (s *SQLiteShiftStore) PurgeDeletedShifts(ctx context.Context, deletedBefore time.Time) (int, error) {
+	return s.shifts.purgeDeleted(ctx, deletedBefore)
+}
@@ -2,22 +2,27 @@ package store
 
 import (
 	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"sync"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/pkg/errors"
 )
 
-//TODO - accept ctx in signatures
-
 type IShiftTemplateStore interface {
-	CreateNewShiftTemplate(template models.ShiftTemplate) error
-	FindShiftTemplateByID(id string) ([]models.ShiftTemplate, error)
-	FindAllShiftsTemplate() ([]models.ShiftTemplate, error)
-	UpdateShiftTemplate(template models.ShiftTemplate) error
-	DeleteShiftTemplate(id string) error
+	CreateNewShiftTemplate(ctx context.Context, template models.ShiftTemplate) error
+	FindShiftTemplateByID(ctx context.Context, id string) ([]models.ShiftTemplate, error)
+	FindAllShiftsTemplate(ctx context.Context) ([]models.ShiftTemplate, error)
+	UpdateShiftTemplate(ctx context.Context, template models.ShiftTemplate) error
+	// DeleteShiftTemplate soft deletes the template, the same way as IShiftStore.DeleteShift
+	DeleteShiftTemplate(ctx context.Context, id string, version int) error
+	RestoreShiftTemplate(ctx context.Context, id string) error
+	PurgeDeletedShiftTemplates(ctx context.Context, deletedBefore time.Time) (int, error)
 }
 
 type InMemShiftTemplateStore struct {
+	mu             sync.RWMutex
 	shiftTemplates map[string]models.ShiftTemplate
 }
 
@@ -25,48 +30,99 @@ func NewShiftTemplateStore() (*InMemShiftTemplateStore, error) {
 	return &InMemShiftTemplateStore{shiftTemplates: make(map[string]models.ShiftTemplate)}, nil
 }
 
-func (s *InMemShiftTemplateStore) CreateNewShiftTemplate(template models.ShiftTemplate) error {
+func (s *InMemShiftTemplateStore) CreateNewShiftTemplate(ctx context.Context, template models.ShiftTemplate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if err := validator.New().Struct(template); err != nil {
 		return errors.Wrap(err, "shift template validation failed")
 	}
 	if _, exists := s.shiftTemplates[template.ID]; exists {
 		return errors.New("shift template already exists")
 	}
+	template.Version = 1
+	template.DeletedAt = nil
 	s.shiftTemplates[template.ID] = template
 	return nil
 }
 
-func (s *InMemShiftTemplateStore) FindShiftTemplateByID(id string) ([]models.ShiftTemplate, error) {
-	if template, exists := s.shiftTemplates[id]; !exists {
+func (s *InMemShiftTemplateStore) FindShiftTemplateByID(ctx context.Context, id string) ([]models.ShiftTemplate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if template, exists := s.shiftTemplates[id]; !exists || template.DeletedAt != nil {
 		return []models.ShiftTemplate{}, nil
 	} else {
 		return []models.ShiftTemplate{template}, nil
 	}
 }
 
-func (s *InMemShiftTemplateStore) FindAllShiftsTemplate() ([]models.ShiftTemplate, error) {
+func (s *InMemShiftTemplateStore) FindAllShiftsTemplate(ctx context.Context) ([]models.ShiftTemplate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	templates := make([]models.ShiftTemplate, 0, len(s.shiftTemplates))
 	for _, template := range s.shiftTemplates {
+		if template.DeletedAt != nil {
+			continue
+		}
 		templates = append(templates, template)
 	}
 	return templates, nil
 }
 
-func (s *InMemShiftTemplateStore) UpdateShiftTemplate(template models.ShiftTemplate) error {
+func (s *InMemShiftTemplateStore) UpdateShiftTemplate(ctx context.Context, template models.ShiftTemplate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if err := validator.New().Struct(template); err != nil {
 		return errors.Wrap(err, "shift template validation failed")
 	}
-	if _, exists := s.shiftTemplates[template.ID]; !exists {
+	if stored, exists := s.shiftTemplates[template.ID]; !exists || stored.DeletedAt != nil {
 		return errors.New("shift template not found")
+	} else if stored.Version != template.Version {
+		return versionConflict("shift template", template.ID, stored.Version)
 	}
+	template.Version++
+	template.DeletedAt = nil
 	s.shiftTemplates[template.ID] = template
 	return nil
 }
 
-func (s *InMemShiftTemplateStore) DeleteShiftTemplate(id string) error {
-	if _, exists := s.shiftTemplates[id]; !exists {
+func (s *InMemShiftTemplateStore) DeleteShiftTemplate(ctx context.Context, id string, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, exists := s.shiftTemplates[id]
+	if !exists || stored.DeletedAt != nil {
 		return errors.New("shift template not found")
+	} else if stored.Version != version {
+		return versionConflict("shift template", id, stored.Version)
+	}
+	deletedAt := time.Now().UTC()
+	stored.DeletedAt = &deletedAt
+	stored.Version++
+	s.shiftTemplates[id] = stored
+	return nil
+}
+
+func (s *InMemShiftTemplateStore) RestoreShiftTemplate(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, exists := s.shiftTemplates[id]
+	if !exists || stored.DeletedAt == nil {
+		return notDeleted("shift template", id)
 	}
-	delete(s.shiftTemplates, id)
+	stored.DeletedAt = nil
+	stored.Version++
+	s.shiftTemplates[id] = stored
 	return nil
 }
+
+func (s *InMemShiftTemplateStore) PurgeDeletedShiftTemplates(ctx context.Context, deletedBefore time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	purged := 0
+	for id, template := range s.shiftTemplates {
+		if template.DeletedAt != nil && template.DeletedAt.Before(deletedBefore) {
+			delete(s.shiftTemplates, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+)
+
+type SQLiteShiftTemplateStore struct {
+	shiftTemplates jsonTable[models.ShiftTemplate]
+}
+
+func NewSQLiteShiftTemplateStore(db *sql.DB) (*SQLiteShiftTemplateStore, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return newSQLiteShiftTemplateStore(db), nil
+}
+
+func newSQLiteShiftTemplateStore(db sqlExecutor) *SQLiteShiftTemplateStore {
+	return &SQLiteShiftTemplateStore{
+		shiftTemplates: jsonTable[models.ShiftTemplate]{db: db, name: "shift_templates", keyColumn: "id", softDelete: true},
+	}
+}
+
+func (s *SQLiteShiftTemplateStore) CreateNewShiftTemplate(ctx context.Context, template models.ShiftTemplate) error {
+	if err := validator.New().Struct(template); err != nil {
+		return errors.Wrap(err, "shift template validation failed")
+	}
+	template.Version = 1
+	template.DeletedAt = nil
+	return s.shiftTemplates.insert(ctx, template.ID, template)
+}
+
+func (s *SQLiteShiftTemplateStore) FindShiftTemplateByID(ctx context.Context, id string) ([]models.ShiftTemplate, error) {
+	return s.shiftTemplates.find(ctx, id)
+}
+
+func (s *SQLiteShiftTemplateStore) FindAllShiftsTemplate(ctx context.Context) ([]models.ShiftTemplate, error) {
+	return s.shiftTemplates.findAll(ctx)
+}
+
+func (s *SQLiteShiftTemplateStore) UpdateShiftTemplate(ctx context.Context, template models.ShiftTemplate) error {
+	if err := validator.New().Struct(template); err != nil {
+		return errors.Wrap(err, "shift template validation failed")
+	}
+	template.DeletedAt = nil
+	return s.shiftTemplates.updateVersioned(ctx, template.ID, template, template.Version)
+}
+
+func (s *SQLiteShiftTemplateStore) DeleteShiftTemplate(ctx context.Context, id string, version int) error {
+	return s.shiftTemplates.softDeleteVersioned(ctx, id, version)
+}
+
+func (s *SQLiteShiftTemplateStore) RestoreShiftTemplate(ctx context.Context, id string) error {
+	return s.shiftTemplates.restore(ctx, id)
+}
+
+func (s *SQLiteShiftTemplateStore) PurgeDeletedShiftTemplates(ctx context.Context, deletedBefore time.Time) (int, error) {
+	return s.shiftTemplates.purgeDeleted(ctx, deletedBefore)
+}
@@ -3,6 +3,7 @@ package store_test
 import (
 	"brothers_in_batash/internal/pkg/models"
 	"brothers_in_batash/internal/pkg/store"
+	"context"
 	"testing"
 	"time"
 
@@ -21,6 +22,7 @@ var (
 			ID:   "1",
 			Name: "Commander",
 		}},
+		Version: 1,
 	}
 )
 
@@ -45,7 +47,7 @@ func TestInMemShiftStore_CreateNewShift__success(t *testing.T) {
 	}
 
 	// Act
-	err = shiftStore.CreateNewShift(shift)
+	err = shiftStore.CreateNewShift(context.Background(), shift)
 
 	// Assert
 	assert.NoError(t, err)
@@ -65,11 +67,11 @@ func TestInMemShiftStore_CreateNewShift__duplicate_id(t *testing.T) {
 		Commander: testSoldier,
 	}
 
-	err = shiftStore.CreateNewShift(shift)
+	err = shiftStore.CreateNewShift(context.Background(), shift)
 	require.NoError(t, err)
 
 	// Act
-	err = shiftStore.CreateNewShift(shift)
+	err = shiftStore.CreateNewShift(context.Background(), shift)
 
 	// Assert
 	assert.Error(t, err)
@@ -87,7 +89,7 @@ func TestInMemShiftStore_CreateNewShift__invalid_shift(t *testing.T) {
 	}
 
 	// Act
-	err = shiftStore.CreateNewShift(invalidShift)
+	err = shiftStore.CreateNewShift(context.Background(), invalidShift)
 
 	// Assert
 	assert.Error(t, err)
@@ -105,18 +107,19 @@ func TestInMemShiftStore_FindShiftByID__success(t *testing.T) {
 		StartTime: time.Date(2025, time.April, 9, 15, 0, 0, 0, time.UTC),
 		EndTime:   time.Date(2025, time.April, 9, 16, 0, 0, 0, time.UTC),
 		Commander: testSoldier,
+		Version:   1,
 	}
 
-	err = shiftStore.CreateNewShift(shift)
+	err = shiftStore.CreateNewShift(context.Background(), shift)
 	require.NoError(t, err)
 
 	// Act
-	foundShifts, err := shiftStore.FindShiftByID("123")
+	foundShifts, err := shiftStore.FindShiftByID(context.Background(), "123")
 
 	// Assert
 	assert.NoError(t, err)
 	assert.Len(t, foundShifts, 1)
-	assert.Equal(t, shift, foundShifts[0])
+	assert.Equal(t, shift.WithSoldierRefs(), foundShifts[0])
 }
 
 func TestInMemShiftStore_FindShiftByID__not_found(t *testing.T) {
@@ -125,7 +128,7 @@ func TestInMemShiftStore_FindShiftByID__not_found(t *testing.T) {
 	require.NoError(t, err)
 
 	// Act
-	foundShifts, err := shiftStore.FindShiftByID("456")
+	foundShifts, err := shiftStore.FindShiftByID(context.Background(), "456")
 
 	// Assert
 	assert.NoError(t, err)
@@ -144,7 +147,8 @@ func TestInMemShiftStore_FindAllShifts__success(t *testing.T) {
 			Type:      models.MotorizedPatrolShiftType,
 			StartTime: time.Date(2025, time.April, 9, 15, 0, 0, 0, time.UTC),
 			EndTime:   time.Date(2025, time.April, 9, 16, 0, 0, 0, time.UTC),
-			Commander: testSoldier,
+			Commander: models.Soldier{ID: testSoldier.ID},
+			Version:   1,
 		},
 		{
 			ID:        "2",
@@ -152,17 +156,18 @@ func TestInMemShiftStore_FindAllShifts__success(t *testing.T) {
 			Type:      models.StaticPostShiftType,
 			StartTime: time.Date(2025, time.April, 9, 15, 0, 0, 0, time.UTC),
 			EndTime:   time.Date(2025, time.April, 9, 16, 0, 0, 0, time.UTC),
-			Commander: testSoldier,
+			Commander: models.Soldier{ID: testSoldier.ID},
+			Version:   1,
 		},
 	}
 
 	for _, shift := range shifts {
-		err := shiftStore.CreateNewShift(shift)
+		err := shiftStore.CreateNewShift(context.Background(), shift)
 		require.NoError(t, err)
 	}
 
 	// Act
-	allShifts, err := shiftStore.FindAllShifts()
+	allShifts, err := shiftStore.FindAllShifts(context.Background())
 
 	// Assert
 	assert.NoError(t, err)
@@ -184,21 +189,23 @@ func TestInMemShiftStore_UpdateShift__success(t *testing.T) {
 		Commander: testSoldier,
 	}
 
-	err = shiftStore.CreateNewShift(shift)
+	err = shiftStore.CreateNewShift(context.Background(), shift)
 	require.NoError(t, err)
 
 	updatedShift := shift
 	updatedShift.Name = "Updated Shift"
+	updatedShift.Version = 1
 
 	// Act
-	err = shiftStore.UpdateShift(updatedShift)
+	err = shiftStore.UpdateShift(context.Background(), updatedShift)
 
 	// Assert
 	assert.NoError(t, err)
-	foundShifts, err := shiftStore.FindShiftByID(shiftID)
+	foundShifts, err := shiftStore.FindShiftByID(context.Background(), shiftID)
 	assert.NoError(t, err)
 	assert.Len(t, foundShifts, 1)
-	assert.Equal(t, updatedShift, foundShifts[0])
+	updatedShift.Version = 2
+	assert.Equal(t, updatedShift.WithSoldierRefs(), foundShifts[0])
 }
 
 func TestInMemShiftStore_UpdateShift__not_found(t *testing.T) {
@@ -210,7 +217,7 @@ func TestInMemShiftStore_UpdateShift__not_found(t *testing.T) {
 	nonExistentShift := models.Shift{ID: "456"}
 
 	// Assert
-	err = shiftStore.UpdateShift(nonExistentShift)
+	err = shiftStore.UpdateShift(context.Background(), nonExistentShift)
 	assert.Error(t, err)
 }
 
@@ -228,14 +235,14 @@ func TestInMemShiftStore_UpdateShift__invalid_data(t *testing.T) {
 		Commander: testSoldier,
 	}
 
-	err = shiftStore.CreateNewShift(shift)
+	err = shiftStore.CreateNewShift(context.Background(), shift)
 	require.NoError(t, err)
 
 	invalidShift := shift
 	invalidShift.Name = ""
 
 	// Act
-	err = shiftStore.UpdateShift(invalidShift)
+	err = shiftStore.UpdateShift(context.Background(), invalidShift)
 
 	// Assert
 	assert.Error(t, err)
@@ -256,15 +263,15 @@ func TestInMemShiftStore_DeleteShift__success(t *testing.T) {
 		Commander: testSoldier,
 	}
 
-	err = shiftStore.CreateNewShift(shift)
+	err = shiftStore.CreateNewShift(context.Background(), shift)
 	require.NoError(t, err)
 
 	// Act
-	err = shiftStore.DeleteShift(shiftID)
+	err = shiftStore.DeleteShift(context.Background(), shiftID, 1)
 
 	// Assert
 	assert.NoError(t, err)
-	foundShifts, err := shiftStore.FindShiftByID(shiftID)
+	foundShifts, err := shiftStore.FindShiftByID(context.Background(), shiftID)
 	assert.NoError(t, err)
 	assert.Len(t, foundShifts, 0)
 }
@@ -275,8 +282,91 @@ func TestInMemShiftStore_DeleteShift__not_found(t *testing.T) {
 	require.NoError(t, err)
 
 	// Act
-	err = shiftStore.DeleteShift("456")
+	err = shiftStore.DeleteShift(context.Background(), "456", 1)
 
 	// Assert
 	assert.Error(t, err)
 }
+
+func TestInMemShiftStore_DeleteShift__hidden_from_find_all(t *testing.T) {
+	// Arrange
+	shiftStore, err := store.NewShiftStore()
+	require.NoError(t, err)
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+
+	// Act
+	err = shiftStore.DeleteShift(context.Background(), testShiftModel.ID, 1)
+
+	// Assert
+	assert.NoError(t, err)
+	foundShifts, err := shiftStore.FindAllShifts(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, foundShifts)
+	assert.Error(t, shiftStore.UpdateShift(context.Background(), testShiftModel))
+}
+
+func TestInMemShiftStore_RestoreShift__success(t *testing.T) {
+	// Arrange
+	shiftStore, err := store.NewShiftStore()
+	require.NoError(t, err)
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+	err = shiftStore.DeleteShift(context.Background(), testShiftModel.ID, 1)
+	require.NoError(t, err)
+
+	// Act
+	err = shiftStore.RestoreShift(context.Background(), testShiftModel.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	foundShifts, err := shiftStore.FindShiftByID(context.Background(), testShiftModel.ID)
+	assert.NoError(t, err)
+	require.Len(t, foundShifts, 1)
+	assert.Nil(t, foundShifts[0].DeletedAt)
+	assert.Equal(t, 3, foundShifts[0].Version)
+}
+
+func TestInMemShiftStore_RestoreShift__not_deleted(t *testing.T) {
+	// Arrange
+	shiftStore, err := store.NewShiftStore()
+	require.NoError(t, err)
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+
+	// Act
+	existingErr := shiftStore.RestoreShift(context.Background(), testShiftModel.ID)
+	missingErr := shiftStore.RestoreShift(context.Background(), "456")
+
+	// Assert
+	assert.ErrorIs(t, existingErr, store.ErrNotDeleted)
+	assert.ErrorIs(t, missingErr, store.ErrNotDeleted)
+}
+
+func TestInMemShiftStore_PurgeDeletedShifts__success(t *testing.T) {
+	// Arrange
+	shiftStore, err := store.NewShiftStore()
+	require.NoError(t, err)
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+	liveShift := testShiftModel
+	liveShift.ID = "456"
+	err = shiftStore.CreateNewShift(context.Background(), liveShift)
+	require.NoError(t, err)
+	err = shiftStore.DeleteShift(context.Background(), testShiftModel.ID, 1)
+	require.NoError(t, err)
+
+	// Act
+	notYetPurged, notYetErr := shiftStore.PurgeDeletedShifts(context.Background(), time.Now().Add(-time.Hour))
+	purged, err := shiftStore.PurgeDeletedShifts(context.Background(), time.Now().Add(time.Hour))
+
+	// Assert
+	assert.NoError(t, notYetErr)
+	assert.Equal(t, 0, notYetPurged)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.ErrorIs(t, shiftStore.RestoreShift(context.Background(), testShiftModel.ID), store.ErrNotDeleted)
+	foundShifts, err := shiftStore.FindAllShifts(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, foundShifts, 1)
+}
@@ -0,0 +1,276 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/logging"
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Snapshot is the content of all the in-memory stores at a single point in time
+type Snapshot struct {
+	TakenAt        time.Time              `json:"takenAt"`
+	DaySchedules   []models.DaySchedule   `json:"daySchedules"`
+	Shifts         []models.Shift         `json:"shifts"`
+	Soldiers       []models.Soldier       `json:"soldiers"`
+	ShiftTemplates []models.ShiftTemplate `json:"shiftTemplates"`
+	Users          []models.User          `json:"users"`
+	Leaves         []models.Leave         `json:"leaves"`
+	Rotations      []models.Rotation      `json:"rotations"`
+	AuditEntries   []models.AuditEntry    `json:"auditEntries"`
+	RevokedTokens  []models.RevokedToken  `json:"revokedTokens"`
+}
+
+// InMemStores are the in-memory stores a Snapshotter persists
+type InMemStores struct {
+	DaySchedules   *InMemDaySchedStore
+	Shifts         *InMemShiftStore
+	Soldiers       *InMemSoldierStore
+	ShiftTemplates *InMemShiftTemplateStore
+	Users          *InMemUserStore
+	Leaves         *InMemLeaveStore
+	Rotations      *InMemRotationStore
+	Audit          *InMemAuditStore
+	RevokedTokens  *InMemRevokedTokenStore
+}
+
+// Snapshotter persists the in-memory stores to a JSON file, and loads them back from it
+type Snapshotter struct {
+	stores InMemStores
+	path   string
+}
+
+func NewSnapshotter(path string, stores InMemStores) (*Snapshotter, error) {
+	if path == "" {
+		return nil, errors.New("snapshot path is empty")
+	}
+	if !stores.complete() {
+		return nil, errors.New("all in-memory stores are required")
+	}
+	return &Snapshotter{stores: stores, path: path}, nil
+}
+
+// Load fills the stores with the snapshot file's content, replacing whatever they hold. A missing snapshot file is not
+// an error - there is simply nothing to load yet.
+func (s *Snapshotter) Load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "could not read snapshot file")
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return errors.Wrap(err, "could not parse snapshot file")
+	}
+
+	s.stores.lockAll()
+	defer s.stores.unlockAll()
+	s.stores.DaySchedules.days = make(map[string]models.DaySchedule, len(snapshot.DaySchedules))
+	for _, day := range snapshot.DaySchedules {
+		s.stores.DaySchedules.days[normalizeDate(day.Date)] = day
+	}
+	s.stores.Shifts.shifts = make(map[string]models.Shift, len(snapshot.Shifts))
+	for _, shift := range snapshot.Shifts {
+		s.stores.Shifts.shifts[shift.ID] = shift
+	}
+	s.stores.Soldiers.soldiers = make(map[string]models.Soldier, len(snapshot.Soldiers))
+	for _, soldier := range snapshot.Soldiers {
+		s.stores.Soldiers.soldiers[soldier.ID] = soldier
+	}
+	s.stores.ShiftTemplates.shiftTemplates = make(map[string]models.ShiftTemplate, len(snapshot.ShiftTemplates))
+	for _, template := range snapshot.ShiftTemplates {
+		s.stores.ShiftTemplates.shiftTemplates[template.ID] = template
+	}
+	s.stores.Users.users = make(map[string]models.User, len(snapshot.Users))
+	for _, user := range snapshot.Users {
+		s.stores.Users.users[user.Username] = user
+	}
+	s.stores.Leaves.leaves = make(map[string]models.Leave, len(snapshot.Leaves))
+	for _, leave := range snapshot.Leaves {
+		s.stores.Leaves.leaves[leave.ID] = leave
+	}
+	s.stores.Rotations.rotations = make(map[string]models.Rotation, len(snapshot.Rotations))
+	for _, rotation := range snapshot.Rotations {
+		s.stores.Rotations.rotations[rotation.ID] = rotation
+	}
+	s.stores.Audit.entries = make([]models.AuditEntry, 0, len(snapshot.AuditEntries))
+	s.stores.Audit.entries = append(s.stores.Audit.entries, snapshot.AuditEntries...)
+	s.stores.RevokedTokens.tokens = make(map[string]models.RevokedToken, len(snapshot.RevokedTokens))
+	for _, token := range snapshot.RevokedTokens {
+		s.stores.RevokedTokens.tokens[token.ID] = token
+	}
+	return nil
+}
+
+// Save writes a snapshot of the stores to the snapshot file. The file is replaced atomically, so it always holds
+// either the previous snapshot or the new one, even if the process dies halfway through.
+func (s *Snapshotter) Save() error {
+	data, err := json.Marshal(s.take())
+	if err != nil {
+		return errors.Wrap(err, "could not marshal snapshot")
+	}
+
+	dir := filepath.Dir(s.path)
+	tmpFile, err := os.CreateTemp(dir, filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "could not create temporary snapshot file")
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return errors.Wrap(err, "could not write temporary snapshot file")
+	}
+	if err := tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()
+		return errors.Wrap(err, "could not sync temporary snapshot file")
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.Wrap(err, "could not close temporary snapshot file")
+	}
+	if err := os.Rename(tmpFile.Name(), s.path); err != nil {
+		return errors.Wrap(err, "could not replace snapshot file")
+	}
+	// Sync the directory too, so the rename itself survives a power cut
+	if dirFile, err := os.Open(dir); err == nil {
+		_ = dirFile.Sync()
+		_ = dirFile.Close()
+	}
+	return nil
+}
+
+// Start saves a snapshot every interval in the background, until the returned stop function is called. stop waits for
+// a periodic snapshot that is being saved, so it cannot replace the final snapshot stop saves afterwards.
+func (s *Snapshotter) Start(interval time.Duration) (stop func() error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx, interval)
+	}()
+	return func() error {
+		cancel()
+		<-done
+		return s.Save()
+	}
+}
+
+// Run saves a snapshot every interval, until ctx is done
+func (s *Snapshotter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Save(); err != nil {
+				logging.Error(err, "could not save periodic snapshot", []logging.LogProp{{"path", s.path}})
+			}
+		}
+	}
+}
+
+// take copies the content of all the stores. All of them are locked together, so the snapshot is consistent.
+func (s *Snapshotter) take() Snapshot {
+	s.stores.rLockAll()
+	defer s.stores.rUnlockAll()
+	snapshot := Snapshot{
+		TakenAt:        time.Now().UTC(),
+		DaySchedules:   make([]models.DaySchedule, 0, len(s.stores.DaySchedules.days)),
+		Shifts:         make([]models.Shift, 0, len(s.stores.Shifts.shifts)),
+		Soldiers:       make([]models.Soldier, 0, len(s.stores.Soldiers.soldiers)),
+		ShiftTemplates: make([]models.ShiftTemplate, 0, len(s.stores.ShiftTemplates.shiftTemplates)),
+		Users:          make([]models.User, 0, len(s.stores.Users.users)),
+		Leaves:         make([]models.Leave, 0, len(s.stores.Leaves.leaves)),
+		Rotations:      make([]models.Rotation, 0, len(s.stores.Rotations.rotations)),
+		AuditEntries:   slices.Clone(s.stores.Audit.entries),
+		RevokedTokens:  make([]models.RevokedToken, 0, len(s.stores.RevokedTokens.tokens)),
+	}
+	for _, day := range s.stores.DaySchedules.days {
+		snapshot.DaySchedules = append(snapshot.DaySchedules, day)
+	}
+	for _, shift := range s.stores.Shifts.shifts {
+		snapshot.Shifts = append(snapshot.Shifts, shift)
+	}
+	for _, soldier := range s.stores.Soldiers.soldiers {
+		snapshot.Soldiers = append(snapshot.Soldiers, soldier)
+	}
+	for _, template := range s.stores.ShiftTemplates.shiftTemplates {
+		snapshot.ShiftTemplates = append(snapshot.ShiftTemplates, template)
+	}
+	for _, user := range s.stores.Users.users {
+		snapshot.Users = append(snapshot.Users, user)
+	}
+	for _, leave := range s.stores.Leaves.leaves {
+		snapshot.Leaves = append(snapshot.Leaves, leave)
+	}
+	for _, rotation := range s.stores.Rotations.rotations {
+		snapshot.Rotations = append(snapshot.Rotations, rotation)
+	}
+	for _, token := range s.stores.RevokedTokens.tokens {
+		snapshot.RevokedTokens = append(snapshot.RevokedTokens, token)
+	}
+	return snapshot
+}
+
+func (s InMemStores) complete() bool {
+	return s.DaySchedules != nil && s.Shifts != nil && s.Soldiers != nil && s.ShiftTemplates != nil && s.Users != nil &&
+		s.Leaves != nil && s.Rotations != nil && s.Audit != nil && s.RevokedTokens != nil
+}
+
+// The stores are always locked in the same order and unlocked in reverse, so locking them all can not deadlock
+
+func (s InMemStores) lockAll() {
+	s.DaySchedules.mu.Lock()
+	s.Shifts.mu.Lock()
+	s.Soldiers.mu.Lock()
+	s.ShiftTemplates.mu.Lock()
+	s.Users.mu.Lock()
+	s.Leaves.mu.Lock()
+	s.Rotations.mu.Lock()
+	s.Audit.mu.Lock()
+	s.RevokedTokens.mu.Lock()
+}
+
+func (s InMemStores) unlockAll() {
+	s.RevokedTokens.mu.Unlock()
+	s.Audit.mu.Unlock()
+	s.Rotations.mu.Unlock()
+	s.Leaves.mu.Unlock()
+	s.Users.mu.Unlock()
+	s.ShiftTemplates.mu.Unlock()
+	s.Soldiers.mu.Unlock()
+	s.Shifts.mu.Unlock()
+	s.DaySchedules.mu.Unlock()
+}
+
+func (s InMemStores) rLockAll() {
+	s.DaySchedules.mu.RLock()
+	s.Shifts.mu.RLock()
+	s.Soldiers.mu.RLock()
+	s.ShiftTemplates.mu.RLock()
+	s.Users.mu.RLock()
+	s.Leaves.mu.RLock()
+	s.Rotations.mu.RLock()
+	s.Audit.mu.RLock()
+	s.RevokedTokens.mu.RLock()
+}
+
+func (s InMemStores) rUnlockAll() {
+	s.RevokedTokens.mu.RUnlock()
+	s.Audit.mu.RUnlock()
+	s.Rotations.mu.RUnlock()
+	s.Leaves.mu.RUnlock()
+	s.Users.mu.RUnlock()
+	s.ShiftTemplates.mu.RUnlock()
+	s.Soldiers.mu.RUnlock()
+	s.Shifts.mu.RUnlock()
+	s.DaySchedules.mu.RUnlock()
+}
@@ -0,0 +1,177 @@
+package store_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestInMemStores(t *testing.T) store.InMemStores {
+	daySchedStore, err := store.NewInMemDaySchedStore()
+	require.NoError(t, err)
+	shiftStore, err := store.NewShiftStore()
+	require.NoError(t, err)
+	soldierStore, err := store.NewSoldierStore()
+	require.NoError(t, err)
+	shiftTemplateStore, err := store.NewShiftTemplateStore()
+	require.NoError(t, err)
+	userStore, err := store.NewUserStore()
+	require.NoError(t, err)
+	leaveStore, err := store.NewLeaveStore()
+	require.NoError(t, err)
+	rotationStore, err := store.NewRotationStore()
+	require.NoError(t, err)
+	auditStore, err := store.NewAuditStore()
+	require.NoError(t, err)
+	revokedTokenStore, err := store.NewRevokedTokenStore()
+	require.NoError(t, err)
+	return store.InMemStores{
+		DaySchedules:   daySchedStore,
+		Shifts:         shiftStore,
+		Soldiers:       soldierStore,
+		ShiftTemplates: shiftTemplateStore,
+		Users:          userStore,
+		Leaves:         leaveStore,
+		Rotations:      rotationStore,
+		Audit:          auditStore,
+		RevokedTokens:  revokedTokenStore,
+	}
+}
+
+func TestNewSnapshotter__sad_flows(t *testing.T) {
+	// Act
+	emptyPathSnapshotter, emptyPathErr := store.NewSnapshotter("", newTestInMemStores(t))
+	missingStoreSnapshotter, missingStoreErr := store.NewSnapshotter("snapshot.json", store.InMemStores{})
+
+	// Assert
+	assert.Error(t, emptyPathErr)
+	assert.Nil(t, emptyPathSnapshotter)
+	assert.Error(t, missingStoreErr)
+	assert.Nil(t, missingStoreSnapshotter)
+}
+
+func TestSnapshotter_Save__loads_into_new_stores(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	stores := newTestInMemStores(t)
+	require.NoError(t, stores.Shifts.CreateNewShift(context.Background(), testShiftModel))
+	require.NoError(t, stores.Soldiers.CreateNewSoldier(context.Background(), testSoldier))
+	require.NoError(t, stores.Leaves.CreateNewLeave(context.Background(), testLeave))
+	_, err := stores.RevokedTokens.RevokeToken(context.Background(),
+		models.RevokedToken{ID: "token", ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	snapshotter, err := store.NewSnapshotter(path, stores)
+	require.NoError(t, err)
+	loadedStores := newTestInMemStores(t)
+	loadingSnapshotter, err := store.NewSnapshotter(path, loadedStores)
+	require.NoError(t, err)
+
+	// Act
+	saveErr := snapshotter.Save()
+	loadErr := loadingSnapshotter.Load()
+
+	// Assert
+	assert.NoError(t, saveErr)
+	assert.NoError(t, loadErr)
+	shifts, err := loadedStores.Shifts.FindAllShifts(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Shift{testShiftModel.WithSoldierRefs()}, shifts)
+	soldiers, err := loadedStores.Soldiers.FindAllSoldiers(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Soldier{testSoldier}, soldiers)
+	leaves, err := loadedStores.Leaves.FindAllLeaves(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Leave{testLeave}, leaves)
+	revoked, err := loadedStores.RevokedTokens.IsTokenRevoked(context.Background(), "token")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestSnapshotter_Save__replaces_previous_snapshot(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	path := filepath.Join(dir, "snapshot.json")
+	stores := newTestInMemStores(t)
+	snapshotter, err := store.NewSnapshotter(path, stores)
+	require.NoError(t, err)
+	require.NoError(t, snapshotter.Save())
+	require.NoError(t, stores.Shifts.CreateNewShift(context.Background(), testShiftModel))
+
+	// Act
+	err = snapshotter.Save()
+
+	// Assert
+	assert.NoError(t, err)
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	require.Len(t, entries, 1, "temporary snapshot files should not be left behind")
+	loadedStores := newTestInMemStores(t)
+	loadingSnapshotter, err := store.NewSnapshotter(path, loadedStores)
+	require.NoError(t, err)
+	require.NoError(t, loadingSnapshotter.Load())
+	shifts, err := loadedStores.Shifts.FindAllShifts(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, shifts, 1)
+}
+
+func TestSnapshotter_Start__stop_saves_final_snapshot_last(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	stores := newTestInMemStores(t)
+	snapshotter, err := store.NewSnapshotter(path, stores)
+	require.NoError(t, err)
+	stop := snapshotter.Start(time.Microsecond)
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, stores.Shifts.CreateNewShift(context.Background(), testShiftModel))
+
+	// Act
+	err = stop()
+
+	// Assert
+	assert.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	loadedStores := newTestInMemStores(t)
+	loadingSnapshotter, err := store.NewSnapshotter(path, loadedStores)
+	require.NoError(t, err)
+	require.NoError(t, loadingSnapshotter.Load())
+	shifts, err := loadedStores.Shifts.FindAllShifts(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, shifts, 1)
+}
+
+func TestSnapshotter_Load__missing_file(t *testing.T) {
+	// Arrange
+	stores := newTestInMemStores(t)
+	snapshotter, err := store.NewSnapshotter(filepath.Join(t.TempDir(), "snapshot.json"), stores)
+	require.NoError(t, err)
+
+	// Act
+	err = snapshotter.Load()
+
+	// Assert
+	assert.NoError(t, err)
+	shifts, err := stores.Shifts.FindAllShifts(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, shifts)
+}
+
+func TestSnapshotter_Load__corrupt_file(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+	snapshotter, err := store.NewSnapshotter(path, newTestInMemStores(t))
+	require.NoError(t, err)
+
+	// Act
+	err = snapshotter.Load()
+
+	// Assert
+	assert.Error(t, err)
+}
@@ -0,0 +1,11 @@
+package store
+
+import "github.com/pkg/errors"
+
+// ErrNotDeleted is returned when restoring a Shift, Soldier or ShiftTemplate that is not soft deleted - it either was
+// never deleted, or was already restored or purged
+var ErrNotDeleted = errors.New("entity is not deleted")
+
+func notDeleted(entity, key string) error {
+	return errors.Wrapf(ErrNotDeleted, "%s %s", entity, key)
+}
@@ -2,22 +2,27 @@ package store
 
 import (
 	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"sync"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/pkg/errors"
 )
 
-//TODO - accept ctx in signatures
-
 type ISoldierStore interface {
-	CreateNewSoldier(soldier models.Soldier) error
-	FindSoldierByID(id string) ([]models.Soldier, error)
-	FindAllSoldiers() ([]models.Soldier, error)
-	UpdateSoldier(soldier models.Soldier) error
-	DeleteSoldier(id string) error
+	CreateNewSoldier(ctx context.Context, soldier models.Soldier) error
+	FindSoldierByID(ctx context.Context, id string) ([]models.Soldier, error)
+	FindAllSoldiers(ctx context.Context) ([]models.Soldier, error)
+	UpdateSoldier(ctx context.Context, soldier models.Soldier) error
+	// DeleteSoldier soft deletes the soldier, the same way as IShiftStore.DeleteShift
+	DeleteSoldier(ctx context.Context, id string, version int) error
+	RestoreSoldier(ctx context.Context, id string) error
+	PurgeDeletedSoldiers(ctx context.Context, deletedBefore time.Time) (int, error)
 }
 
 type InMemSoldierStore struct {
+	mu       sync.RWMutex
 	soldiers map[string]models.Soldier
 }
 
@@ -25,48 +30,99 @@ func NewSoldierStore() (*InMemSoldierStore, error) {
 	return &InMemSoldierStore{soldiers: make(map[string]models.Soldier)}, nil
 }
 
-func (s *InMemSoldierStore) CreateNewSoldier(soldier models.Soldier) error {
+func (s *InMemSoldierStore) CreateNewSoldier(ctx context.Context, soldier models.Soldier) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if err := validator.New().Struct(soldier); err != nil {
 		return errors.Wrap(err, "soldier validation failed")
 	}
 	if _, exists := s.soldiers[soldier.ID]; exists {
 		return errors.New("soldier already exists")
 	}
+	soldier.Version = 1
+	soldier.DeletedAt = nil
 	s.soldiers[soldier.ID] = soldier
 	return nil
 }
 
-func (s *InMemSoldierStore) FindSoldierByID(id string) ([]models.Soldier, error) {
-	if soldier, exists := s.soldiers[id]; !exists {
+func (s *InMemSoldierStore) FindSoldierByID(ctx context.Context, id string) ([]models.Soldier, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if soldier, exists := s.soldiers[id]; !exists || soldier.DeletedAt != nil {
 		return []models.Soldier{}, nil
 	} else {
 		return []models.Soldier{soldier}, nil
 	}
 }
 
-func (s *InMemSoldierStore) FindAllSoldiers() ([]models.Soldier, error) {
+func (s *InMemSoldierStore) FindAllSoldiers(ctx context.Context) ([]models.Soldier, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	soldiers := make([]models.Soldier, 0, len(s.soldiers))
 	for _, soldier := range s.soldiers {
+		if soldier.DeletedAt != nil {
+			continue
+		}
 		soldiers = append(soldiers, soldier)
 	}
 	return soldiers, nil
 }
 
-func (s *InMemSoldierStore) UpdateSoldier(soldier models.Soldier) error {
+func (s *InMemSoldierStore) UpdateSoldier(ctx context.Context, soldier models.Soldier) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if err := validator.New().Struct(soldier); err != nil {
 		return errors.Wrap(err, "soldier validation failed")
 	}
-	if _, exists := s.soldiers[soldier.ID]; !exists {
+	if stored, exists := s.soldiers[soldier.ID]; !exists || stored.DeletedAt != nil {
 		return errors.New("soldier not found")
+	} else if stored.Version != soldier.Version {
+		return versionConflict("soldier", soldier.ID, stored.Version)
 	}
+	soldier.Version++
+	soldier.DeletedAt = nil
 	s.soldiers[soldier.ID] = soldier
 	return nil
 }
 
-func (s *InMemSoldierStore) DeleteSoldier(id string) error {
-	if _, exists := s.soldiers[id]; !exists {
+func (s *InMemSoldierStore) DeleteSoldier(ctx context.Context, id string, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, exists := s.soldiers[id]
+	if !exists || stored.DeletedAt != nil {
 		return errors.New("soldier not found")
+	} else if stored.Version != version {
+		return versionConflict("soldier", id, stored.Version)
+	}
+	deletedAt := time.Now().UTC()
+	stored.DeletedAt = &deletedAt
+	stored.Version++
+	s.soldiers[id] = stored
+	return nil
+}
+
+func (s *InMemSoldierStore) RestoreSoldier(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, exists := s.soldiers[id]
+	if !exists || stored.DeletedAt == nil {
+		return notDeleted("soldier", id)
 	}
-	delete(s.soldiers, id)
+	stored.DeletedAt = nil
+	stored.Version++
+	s.soldiers[id] = stored
 	return nil
 }
+
+func (s *InMemSoldierStore) PurgeDeletedSoldiers(ctx context.Context, deletedBefore time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	purged := 0
+	for id, soldier := range s.soldiers {
+		if soldier.DeletedAt != nil && soldier.DeletedAt.Before(deletedBefore) {
+			delete(s.soldiers, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Shifts and day schedules reference their soldiers by ID. The Resolving stores wrap them and resolve these references
+// from a soldier store on every read, so changes to a soldier show in all the shifts they are staffed in. References to
+// deleted soldiers are left unresolved, holding only the soldier's ID.
+// Day schedules reference the shifts generated into them by ID too, and the ResolvingDaySchedStore resolves these from
+// a shift store the same way. References to deleted shifts are left out, so a shift is never read in a stale version.
+
+type ResolvingShiftStore struct {
+	IShiftStore
+	soldierStore ISoldierStore
+}
+
+func NewResolvingShiftStore(shiftStore IShiftStore, soldierStore ISoldierStore) (*ResolvingShiftStore, error) {
+	if shiftStore == nil {
+		return nil, errors.New("shiftStore is nil")
+	}
+	if soldierStore == nil {
+		return nil, errors.New("soldierStore is nil")
+	}
+	return &ResolvingShiftStore{IShiftStore: shiftStore, soldierStore: soldierStore}, nil
+}
+
+func (s *ResolvingShiftStore) FindShiftByID(ctx context.Context, id string) ([]models.Shift, error) {
+	shifts, err := s.IShiftStore.FindShiftByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return resolveSoldiers(ctx, s.soldierStore, shifts)
+}
+
+func (s *ResolvingShiftStore) FindAllShifts(ctx context.Context) ([]models.Shift, error) {
+	shifts, err := s.IShiftStore.FindAllShifts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resolveSoldiers(ctx, s.soldierStore, shifts)
+}
+
+func (s *ResolvingShiftStore) FindShifts(ctx context.Context, filter ShiftFilter) ([]models.Shift, error) {
+	shifts, err := s.IShiftStore.FindShifts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return resolveSoldiers(ctx, s.soldierStore, shifts)
+}
+
+type ResolvingDaySchedStore struct {
+	IDayStore
+	shiftStore   IShiftStore
+	soldierStore ISoldierStore
+}
+
+func NewResolvingDaySchedStore(dayStore IDayStore, shiftStore IShiftStore, soldierStore ISoldierStore) (*ResolvingDaySchedStore, error) {
+	if dayStore == nil {
+		return nil, errors.New("dayStore is nil")
+	}
+	if shiftStore == nil {
+		return nil, errors.New("shiftStore is nil")
+	}
+	if soldierStore == nil {
+		return nil, errors.New("soldierStore is nil")
+	}
+	return &ResolvingDaySchedStore{IDayStore: dayStore, shiftStore: shiftStore, soldierStore: soldierStore}, nil
+}
+
+func (s *ResolvingDaySchedStore) FindDaySchedule(ctx context.Context, date time.Time) ([]models.DaySchedule, error) {
+	days, err := s.IDayStore.FindDaySchedule(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, days)
+}
+
+func (s *ResolvingDaySchedStore) FindAllDaySchedules(ctx context.Context) ([]models.DaySchedule, error) {
+	days, err := s.IDayStore.FindAllDaySchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, days)
+}
+
+// FindDaySchedules narrows down the shifts of the day schedules only once their shift references are resolved, so the
+// referenced shifts are filtered too
+func (s *ResolvingDaySchedStore) FindDaySchedules(ctx context.Context, filter DayScheduleFilter) ([]models.DaySchedule, error) {
+	days, err := s.IDayStore.FindDaySchedules(ctx, DayScheduleFilter{From: filter.From, To: filter.To})
+	if err != nil {
+		return nil, err
+	}
+	if days, err = s.resolve(ctx, days); err != nil {
+		return nil, err
+	}
+	return filter.filterShifts(days), nil
+}
+
+func (s *ResolvingDaySchedStore) resolve(ctx context.Context, days []models.DaySchedule) ([]models.DaySchedule, error) {
+	days, err := resolveShifts(ctx, s.shiftStore, days)
+	if err != nil {
+		return nil, err
+	}
+	for i := range days {
+		shifts, err := resolveSoldiers(ctx, s.soldierStore, days[i].Shifts)
+		if err != nil {
+			return nil, err
+		}
+		days[i].Shifts = shifts
+	}
+	return days, nil
+}
+
+// resolveShifts appends to every day schedule the shifts it references by ID, in the order they are referenced
+func resolveShifts(ctx context.Context, shiftStore IShiftStore, days []models.DaySchedule) ([]models.DaySchedule, error) {
+	referencing := false
+	for _, day := range days {
+		referencing = referencing || len(day.ShiftIDs) > 0
+	}
+	if !referencing {
+		return days, nil
+	}
+	shifts, err := shiftStore.FindAllShifts(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch shifts to resolve day schedules by")
+	}
+	shiftsByID := make(map[string]models.Shift, len(shifts))
+	for _, shift := range shifts {
+		shiftsByID[shift.ID] = shift
+	}
+	for i := range days {
+		resolved := append(make([]models.Shift, 0, len(days[i].Shifts)+len(days[i].ShiftIDs)), days[i].Shifts...)
+		for _, id := range days[i].ShiftIDs {
+			if shift, found := shiftsByID[id]; found {
+				resolved = append(resolved, shift)
+			}
+		}
+		days[i].Shifts = resolved
+	}
+	return days, nil
+}
+
+func resolveSoldiers(ctx context.Context, soldierStore ISoldierStore, shifts []models.Shift) ([]models.Shift, error) {
+	if len(shifts) == 0 {
+		return shifts, nil
+	}
+	soldiers, err := soldierStore.FindAllSoldiers(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch soldiers to resolve shifts by")
+	}
+	soldiersByID := make(map[string]models.Soldier, len(soldiers))
+	for _, soldier := range soldiers {
+		soldiersByID[soldier.ID] = soldier
+	}
+	resolved := make([]models.Shift, 0, len(shifts))
+	for _, shift := range shifts {
+		resolved = append(resolved, shift.WithResolvedSoldiers(soldiersByID))
+	}
+	return resolved, nil
+}
+
+// ResolvingTransactor resolves the soldiers of the shifts and day schedules units of work read, and the shifts of these
+// day schedules, the same way as the Resolving stores
+type ResolvingTransactor struct {
+	transactor ITransactor
+}
+
+func NewResolvingTransactor(transactor ITransactor) (*ResolvingTransactor, error) {
+	if transactor == nil {
+		return nil, errors.New("transactor is nil")
+	}
+	return &ResolvingTransactor{transactor: transactor}, nil
+}
+
+func (t *ResolvingTransactor) WithinTx(ctx context.Context, work func(stores TxStores) error) error {
+	return t.transactor.WithinTx(ctx, func(stores TxStores) error {
+		stores.Days = &ResolvingDaySchedStore{IDayStore: stores.Days, shiftStore: stores.Shifts, soldierStore: stores.Soldiers}
+		stores.Shifts = &ResolvingShiftStore{IShiftStore: stores.Shifts, soldierStore: stores.Soldiers}
+		return work(stores)
+	})
+}
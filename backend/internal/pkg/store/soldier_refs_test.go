@@ -0,0 +1,137 @@
+package store_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvingShiftStore__reflects_soldier_updates(t *testing.T) {
+	// Arrange
+	stores := newTestInMemStores(t)
+	shiftStore, err := store.NewResolvingShiftStore(stores.Shifts, stores.Soldiers)
+	require.NoError(t, err)
+	err = stores.Soldiers.CreateNewSoldier(context.Background(), testSoldier)
+	require.NoError(t, err)
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+	renamedSoldier := testSoldier
+	renamedSoldier.LastName = "Renamed"
+
+	// Act
+	err = stores.Soldiers.UpdateSoldier(context.Background(), renamedSoldier)
+
+	// Assert
+	require.NoError(t, err)
+	storedShifts, err := stores.Shifts.FindShiftByID(context.Background(), testShiftModel.ID)
+	require.NoError(t, err)
+	require.Len(t, storedShifts, 1)
+	assert.Equal(t, models.Soldier{ID: testSoldier.ID}, storedShifts[0].Commander)
+	foundShifts, err := shiftStore.FindAllShifts(context.Background())
+	require.NoError(t, err)
+	require.Len(t, foundShifts, 1)
+	assert.Equal(t, "Renamed", foundShifts[0].Commander.LastName)
+	assert.Equal(t, 2, foundShifts[0].Commander.Version)
+}
+
+func TestResolvingShiftStore__keeps_references_to_deleted_soldiers(t *testing.T) {
+	// Arrange
+	stores := newTestInMemStores(t)
+	shiftStore, err := store.NewResolvingShiftStore(stores.Shifts, stores.Soldiers)
+	require.NoError(t, err)
+	err = stores.Soldiers.CreateNewSoldier(context.Background(), testSoldier)
+	require.NoError(t, err)
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+
+	// Act
+	err = stores.Soldiers.DeleteSoldier(context.Background(), testSoldier.ID, 1)
+
+	// Assert
+	require.NoError(t, err)
+	foundShifts, err := shiftStore.FindShiftByID(context.Background(), testShiftModel.ID)
+	require.NoError(t, err)
+	require.Len(t, foundShifts, 1)
+	assert.Equal(t, models.Soldier{ID: testSoldier.ID}, foundShifts[0].Commander)
+}
+
+func TestResolvingDaySchedStore__resolves_referenced_shifts(t *testing.T) {
+	// Arrange
+	stores := newTestInMemStores(t)
+	dayStore, err := store.NewResolvingDaySchedStore(stores.DaySchedules, stores.Shifts, stores.Soldiers)
+	require.NoError(t, err)
+	require.NoError(t, stores.Shifts.CreateNewShift(context.Background(), testShiftModel))
+	day := models.DaySchedule{Date: testShiftModel.StartTime, Shifts: []models.Shift{testShiftModel},
+		ShiftIDs: []string{testShiftModel.ID}}
+	require.NoError(t, dayStore.CreateNewDaySchedule(context.Background(), day))
+	renamedShift := testShiftModel
+	renamedShift.Name = "Renamed Shift"
+	require.NoError(t, stores.Shifts.UpdateShift(context.Background(), renamedShift))
+
+	// Act
+	foundDays, err := dayStore.FindDaySchedule(context.Background(), testShiftModel.StartTime)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, foundDays, 1)
+	require.Len(t, foundDays[0].Shifts, 1)
+	assert.Equal(t, "Renamed Shift", foundDays[0].Shifts[0].Name)
+	storedDays, err := stores.DaySchedules.FindDaySchedule(context.Background(), testShiftModel.StartTime)
+	require.NoError(t, err)
+	require.Len(t, storedDays, 1)
+	assert.Empty(t, storedDays[0].Shifts)
+}
+
+func TestResolvingDaySchedStore__leaves_out_deleted_shifts(t *testing.T) {
+	// Arrange
+	stores := newTestInMemStores(t)
+	dayStore, err := store.NewResolvingDaySchedStore(stores.DaySchedules, stores.Shifts, stores.Soldiers)
+	require.NoError(t, err)
+	require.NoError(t, stores.Shifts.CreateNewShift(context.Background(), testShiftModel))
+	day := models.DaySchedule{Date: testShiftModel.StartTime, ShiftIDs: []string{testShiftModel.ID}}
+	require.NoError(t, dayStore.CreateNewDaySchedule(context.Background(), day))
+
+	// Act
+	err = stores.Shifts.DeleteShift(context.Background(), testShiftModel.ID, 1)
+
+	// Assert
+	require.NoError(t, err)
+	foundDays, err := dayStore.FindAllDaySchedules(context.Background())
+	require.NoError(t, err)
+	require.Len(t, foundDays, 1)
+	assert.Empty(t, foundDays[0].Shifts)
+	filteredDays, err := dayStore.FindDaySchedules(context.Background(),
+		store.DayScheduleFilter{Shifts: store.ShiftFilter{From: testShiftModel.StartTime}})
+	require.NoError(t, err)
+	assert.Empty(t, filteredDays)
+}
+
+func TestResolvingTransactor_WithinTx__resolves_shifts_read_in_units_of_work(t *testing.T) {
+	// Arrange
+	stores := newTestInMemStores(t)
+	err := stores.Soldiers.CreateNewSoldier(context.Background(), testSoldier)
+	require.NoError(t, err)
+	err = stores.Shifts.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+	transactor, err := store.NewInMemTransactor(stores)
+	require.NoError(t, err)
+	resolvingTransactor, err := store.NewResolvingTransactor(transactor)
+	require.NoError(t, err)
+
+	// Act
+	var foundShifts []models.Shift
+	err = resolvingTransactor.WithinTx(context.Background(), func(txStores store.TxStores) error {
+		var err error
+		foundShifts, err = txStores.Shifts.FindAllShifts(context.Background())
+		return err
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	require.Len(t, foundShifts, 1)
+	assert.Equal(t, testSoldier.FirstName, foundShifts[0].Commander.FirstName)
+}
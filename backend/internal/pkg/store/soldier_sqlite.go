@@ -0,0 +1,63 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+)
+
+type SQLiteSoldierStore struct {
+	soldiers jsonTable[models.Soldier]
+}
+
+func NewSQLiteSoldierStore(db *sql.DB) (*SQLiteSoldierStore, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return newSQLiteSoldierStore(db), nil
+}
+
+func newSQLiteSoldierStore(db sqlExecutor) *SQLiteSoldierStore {
+	return &SQLiteSoldierStore{soldiers: jsonTable[models.Soldier]{db: db, name: "soldiers", keyColumn: "id", softDelete: true}}
+}
+
+func (s *SQLiteSoldierStore) CreateNewSoldier(ctx context.Context, soldier models.Soldier) error {
+	if err := validator.New().Struct(soldier); err != nil {
+		return errors.Wrap(err, "soldier validation failed")
+	}
+	soldier.Version = 1
+	soldier.DeletedAt = nil
+	return s.soldiers.insert(ctx, soldier.ID, soldier)
+}
+
+func (s *SQLiteSoldierStore) FindSoldierByID(ctx context.Context, id string) ([]models.Soldier, error) {
+	return s.soldiers.find(ctx, id)
+}
+
+func (s *SQLiteSoldierStore) FindAllSoldiers(ctx context.Context) ([]models.Soldier, error) {
+	return s.soldiers.findAll(ctx)
+}
+
+func (s *SQLiteSoldierStore) UpdateSoldier(ctx context.Context, soldier models.Soldier) error {
+	if err := validator.New().Struct(soldier); err != nil {
+		return errors.Wrap(err, "soldier validation failed")
+	}
+	soldier.DeletedAt = nil
+	return s.soldiers.updateVersioned(ctx, soldier.ID, soldier, soldier.Version)
+}
+
+func (s *SQLiteSoldierStore) DeleteSoldier(ctx context.Context, id string, version int) error {
+	return s.soldiers.softDeleteVersioned(ctx, id, version)
+}
+
+func (s *SQLiteSoldierStore) RestoreSoldier(ctx context.Context, id string) error {
+	return s.soldiers.restore(ctx, id)
+}
+
+func (s *SQLiteSoldierStore) PurgeDeletedSoldiers(ctx context.Context, deletedBefore time.Time) (int, error) {
+	return s.soldiers.purgeDeleted(ctx, deletedBefore)
+}
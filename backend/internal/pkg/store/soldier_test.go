@@ -3,6 +3,7 @@ package store_test
 import (
 	"brothers_in_batash/internal/pkg/models"
 	"brothers_in_batash/internal/pkg/store"
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,7 +33,7 @@ func TestInMemSoldierStore_CreateNewSoldier__success(t *testing.T) {
 	}
 
 	//Act
-	err = soldierStore.CreateNewSoldier(soldier)
+	err = soldierStore.CreateNewSoldier(context.Background(), soldier)
 
 	//Assert
 	assert.NoError(t, err)
@@ -54,11 +55,11 @@ func TestInMemSoldierStore_CreateNewSoldier__duplicate_id(t *testing.T) {
 		},
 	}
 
-	err = soldierStore.CreateNewSoldier(soldier)
+	err = soldierStore.CreateNewSoldier(context.Background(), soldier)
 	require.NoError(t, err)
 
 	//Act
-	err = soldierStore.CreateNewSoldier(soldier)
+	err = soldierStore.CreateNewSoldier(context.Background(), soldier)
 
 	//Assert
 	assert.Error(t, err)
@@ -79,7 +80,7 @@ func TestInMemSoldierStore_CreateNewSoldier__invalid_soldier(t *testing.T) {
 	}
 
 	//Act
-	err = soldierStore.CreateNewSoldier(invalidSoldier)
+	err = soldierStore.CreateNewSoldier(context.Background(), invalidSoldier)
 
 	//Assert
 	assert.Error(t, err)
@@ -99,13 +100,14 @@ func TestInMemSoldierStore_FindSoldierByID__success(t *testing.T) {
 		Roles: []models.SoldierRole{
 			{ID: "1", Name: "Role"},
 		},
+		Version: 1,
 	}
 
-	err = soldierStore.CreateNewSoldier(soldier)
+	err = soldierStore.CreateNewSoldier(context.Background(), soldier)
 	require.NoError(t, err)
 
 	//Act
-	foundSoldiers, err := soldierStore.FindSoldierByID("123")
+	foundSoldiers, err := soldierStore.FindSoldierByID(context.Background(), "123")
 
 	//Assert
 	assert.NoError(t, err)
@@ -119,7 +121,7 @@ func TestInMemSoldierStore_FindSoldierByID__not_found(t *testing.T) {
 	require.NoError(t, err)
 
 	//Act
-	foundSoldiers, err := soldierStore.FindSoldierByID("456")
+	foundSoldiers, err := soldierStore.FindSoldierByID(context.Background(), "456")
 
 	//Assert
 	assert.NoError(t, err)
@@ -141,6 +143,7 @@ func TestInMemSoldierStore_FindAllSoldiers__success(t *testing.T) {
 			Roles: []models.SoldierRole{
 				{ID: "1", Name: "Role"},
 			},
+			Version: 1,
 		},
 		{
 			ID:             "2",
@@ -151,16 +154,17 @@ func TestInMemSoldierStore_FindAllSoldiers__success(t *testing.T) {
 			Roles: []models.SoldierRole{
 				{ID: "1", Name: "Role"},
 			},
+			Version: 1,
 		},
 	}
 
 	for _, soldier := range soldiers {
-		err := soldierStore.CreateNewSoldier(soldier)
+		err := soldierStore.CreateNewSoldier(context.Background(), soldier)
 		require.NoError(t, err)
 	}
 
 	//Act
-	allSoldiers, err := soldierStore.FindAllSoldiers()
+	allSoldiers, err := soldierStore.FindAllSoldiers(context.Background())
 
 	//Assert
 	assert.NoError(t, err)
@@ -184,20 +188,22 @@ func TestInMemSoldierStore_UpdateSoldier__success(t *testing.T) {
 		},
 	}
 
-	err = soldierStore.CreateNewSoldier(soldier)
+	err = soldierStore.CreateNewSoldier(context.Background(), soldier)
 	require.NoError(t, err)
 
 	updatedSoldier := soldier
 	updatedSoldier.Position = models.SquadCommanderPosition
+	updatedSoldier.Version = 1
 
 	//Act
-	err = soldierStore.UpdateSoldier(updatedSoldier)
+	err = soldierStore.UpdateSoldier(context.Background(), updatedSoldier)
 
 	//Assert
 	assert.NoError(t, err)
-	foundSoldiers, err := soldierStore.FindSoldierByID(soldierID)
+	foundSoldiers, err := soldierStore.FindSoldierByID(context.Background(), soldierID)
 	assert.NoError(t, err)
 	assert.Len(t, foundSoldiers, 1)
+	updatedSoldier.Version = 2
 	assert.Equal(t, updatedSoldier, foundSoldiers[0])
 }
 
@@ -210,7 +216,7 @@ func TestInMemSoldierStore_UpdateSoldier__not_found(t *testing.T) {
 	nonExistentSoldier := models.Soldier{ID: "456"}
 
 	//Assert
-	err = soldierStore.UpdateSoldier(nonExistentSoldier)
+	err = soldierStore.UpdateSoldier(context.Background(), nonExistentSoldier)
 	assert.Error(t, err)
 }
 
@@ -230,14 +236,14 @@ func TestInMemSoldierStore_UpdateSoldier__invalid_data(t *testing.T) {
 		},
 	}
 
-	err = soldierStore.CreateNewSoldier(soldier)
+	err = soldierStore.CreateNewSoldier(context.Background(), soldier)
 	require.NoError(t, err)
 
 	invalidSoldier := soldier
 	invalidSoldier.FirstName = ""
 
 	//Act
-	err = soldierStore.UpdateSoldier(invalidSoldier)
+	err = soldierStore.UpdateSoldier(context.Background(), invalidSoldier)
 
 	//Assert
 	assert.Error(t, err)
@@ -260,15 +266,15 @@ func TestInMemSoldierStore_DeleteSoldier__success(t *testing.T) {
 		},
 	}
 
-	err = soldierStore.CreateNewSoldier(soldier)
+	err = soldierStore.CreateNewSoldier(context.Background(), soldier)
 	require.NoError(t, err)
 
 	//Act
-	err = soldierStore.DeleteSoldier(soldierID)
+	err = soldierStore.DeleteSoldier(context.Background(), soldierID, 1)
 
 	//Assert
 	assert.NoError(t, err)
-	foundSoldiers, err := soldierStore.FindSoldierByID(soldierID)
+	foundSoldiers, err := soldierStore.FindSoldierByID(context.Background(), soldierID)
 	assert.NoError(t, err)
 	assert.Len(t, foundSoldiers, 0)
 }
@@ -279,7 +285,7 @@ func TestInMemSoldierStore_DeleteSoldier__not_found(t *testing.T) {
 	require.NoError(t, err)
 
 	//Act
-	err = soldierStore.DeleteSoldier("456")
+	err = soldierStore.DeleteSoldier(context.Background(), "456", 1)
 
 	//Assert
 	assert.Error(t, err)
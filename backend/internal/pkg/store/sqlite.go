@@ -0,0 +1,291 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/pkg/errors"
+)
+
+const (
+	InMemBackend  = "memory"
+	SQLiteBackend = "sqlite"
+)
+
+// migrations are applied in order, each one exactly once. Never edit an applied migration - append a new one instead.
+// Entities are kept as JSON documents, keyed by the field the stores look them up by.
+var migrations = []string{
+	`CREATE TABLE soldiers (id TEXT PRIMARY KEY, data TEXT NOT NULL);
+	CREATE TABLE shifts (id TEXT PRIMARY KEY, data TEXT NOT NULL);
+	CREATE TABLE shift_templates (id TEXT PRIMARY KEY, data TEXT NOT NULL);
+	CREATE TABLE day_schedules (date TEXT PRIMARY KEY, data TEXT NOT NULL);
+	CREATE TABLE users (username TEXT PRIMARY KEY, data TEXT NOT NULL);
+	CREATE TABLE leaves (id TEXT PRIMARY KEY, data TEXT NOT NULL);
+	CREATE TABLE rotations (id TEXT PRIMARY KEY, data TEXT NOT NULL);`,
+	// Audit entries are append-only, and are read back in the order they were added
+	`CREATE TABLE audit_entries (seq INTEGER PRIMARY KEY AUTOINCREMENT, entity_type TEXT NOT NULL, entity_id TEXT NOT NULL,
+		data TEXT NOT NULL);
+	CREATE INDEX audit_entries_entity ON audit_entries (entity_type, entity_id);`,
+	`CREATE TABLE revoked_tokens (id TEXT PRIMARY KEY, data TEXT NOT NULL);`,
+}
+
+// OpenSQLiteDB opens the SQLite database file at path, creating it if needed, and migrates it to the latest schema
+func OpenSQLiteDB(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL", path))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open sqlite database")
+	}
+	// SQLite allows a single writer at a time, so sharing one connection avoids "database is locked" errors
+	db.SetMaxOpenConns(1)
+	if err := migrate(db); err != nil {
+		_ = db.Close()
+		return nil, errors.Wrap(err, "could not migrate sqlite database")
+	}
+	return db, nil
+}
+
+func migrate(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, applied_at TEXT NOT NULL)`); err != nil {
+		return errors.Wrap(err, "could not create migrations table")
+	}
+	var currentVersion int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&currentVersion); err != nil {
+		return errors.Wrap(err, "could not query schema version")
+	}
+
+	for i := currentVersion; i < len(migrations); i++ {
+		version := i + 1
+		tx, err := db.Begin()
+		if err != nil {
+			return errors.Wrap(err, "could not begin migration transaction")
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			_ = tx.Rollback()
+			return errors.Wrapf(err, "migration %d failed", version)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+			version, time.Now().UTC().Format(time.RFC3339)); err != nil {
+			_ = tx.Rollback()
+			return errors.Wrapf(err, "could not record migration %d", version)
+		}
+		if err := tx.Commit(); err != nil {
+			return errors.Wrapf(err, "could not commit migration %d", version)
+		}
+	}
+	return nil
+}
+
+// sqlExecutor is implemented by both *sql.DB and *sql.Tx, so stores work the same way within a transaction and outside one
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// versionExpr extracts the version of a stored entity. Rows written before entities were versioned count as version 0.
+const versionExpr = `COALESCE(json_extract(data, '$.version'), 0)`
+
+// deletedAtExpr extracts the time a soft deleted entity was deleted at, or NULL for an entity that is not deleted
+const deletedAtExpr = `json_extract(data, '$.deletedAt')`
+
+// jsonTable stores entities of type T as JSON documents in a table of (key, data) rows
+type jsonTable[T any] struct {
+	db        sqlExecutor
+	name      string
+	keyColumn string
+	// softDelete hides soft deleted entities from finds and versioned writes, as if they were not stored at all
+	softDelete bool
+}
+
+// liveCondition is a WHERE condition that matches the entities that are not soft deleted
+func (t jsonTable[T]) liveCondition() string {
+	if t.softDelete {
+		return deletedAtExpr + " IS NULL"
+	}
+	return "TRUE"
+}
+
+func (t jsonTable[T]) insert(ctx context.Context, key string, entity T) error {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal entity")
+	}
+	_, err = t.db.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (%s, data) VALUES (?, ?)`, t.name, t.keyColumn), key, string(data))
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+		return alreadyExists(t.name, key)
+	}
+	return errors.Wrapf(err, "could not insert into %s", t.name)
+}
+
+func (t jsonTable[T]) find(ctx context.Context, key string) ([]T, error) {
+	rows, err := t.db.QueryContext(ctx, fmt.Sprintf(`SELECT data FROM %s WHERE %s = ? AND %s`, t.name, t.keyColumn, t.liveCondition()), key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not query %s", t.name)
+	}
+	return t.scan(rows)
+}
+
+func (t jsonTable[T]) findAll(ctx context.Context) ([]T, error) {
+	rows, err := t.db.QueryContext(ctx, fmt.Sprintf(`SELECT data FROM %s WHERE %s ORDER BY %s`, t.name, t.liveCondition(), t.keyColumn))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not query %s", t.name)
+	}
+	return t.scan(rows)
+}
+
+// findWhere returns the entities matching all of conditions, which are WHERE conditions on the data column
+func (t jsonTable[T]) findWhere(ctx context.Context, conditions []string, args ...any) ([]T, error) {
+	where := strings.Join(append([]string{t.liveCondition()}, conditions...), " AND ")
+	rows, err := t.db.QueryContext(ctx, fmt.Sprintf(`SELECT data FROM %s WHERE %s ORDER BY %s`, t.name, where, t.keyColumn), args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not query %s", t.name)
+	}
+	return t.scan(rows)
+}
+
+func (t jsonTable[T]) update(ctx context.Context, key string, entity T) error {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal entity")
+	}
+	result, err := t.db.ExecContext(ctx, fmt.Sprintf(`UPDATE %s SET data = ? WHERE %s = ?`, t.name, t.keyColumn), string(data), key)
+	if err != nil {
+		return errors.Wrapf(err, "could not update %s", t.name)
+	}
+	return t.expectAffected(result, key)
+}
+
+func (t jsonTable[T]) delete(ctx context.Context, key string) error {
+	result, err := t.db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE %s = ?`, t.name, t.keyColumn), key)
+	if err != nil {
+		return errors.Wrapf(err, "could not delete from %s", t.name)
+	}
+	return t.expectAffected(result, key)
+}
+
+// updateVersioned replaces the entity stored under key and bumps its version, as long as the stored entity is still at
+// version
+func (t jsonTable[T]) updateVersioned(ctx context.Context, key string, entity T, version int) error {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal entity")
+	}
+	result, err := t.db.ExecContext(ctx, fmt.Sprintf(`UPDATE %s SET data = json_set(?, '$.version', ?) WHERE %s = ? AND %s = ? AND %s`,
+		t.name, t.keyColumn, versionExpr, t.liveCondition()), string(data), version+1, key, version)
+	if err != nil {
+		return errors.Wrapf(err, "could not update %s", t.name)
+	}
+	return t.expectVersionAffected(ctx, result, key)
+}
+
+// deleteVersioned deletes the entity stored under key, as long as it is still at version
+func (t jsonTable[T]) deleteVersioned(ctx context.Context, key string, version int) error {
+	result, err := t.db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE %s = ? AND %s = ?`, t.name, t.keyColumn, versionExpr),
+		key, version)
+	if err != nil {
+		return errors.Wrapf(err, "could not delete from %s", t.name)
+	}
+	return t.expectVersionAffected(ctx, result, key)
+}
+
+// softDeleteVersioned marks the entity stored under key as deleted and bumps its version, as long as it is still at
+// version
+func (t jsonTable[T]) softDeleteVersioned(ctx context.Context, key string, version int) error {
+	result, err := t.db.ExecContext(ctx, fmt.Sprintf(`UPDATE %s SET data = json_set(data, '$.deletedAt', ?, '$.version', ?)
+		WHERE %s = ? AND %s = ? AND %s`, t.name, t.keyColumn, versionExpr, t.liveCondition()),
+		time.Now().UTC().Format(time.RFC3339Nano), version+1, key, version)
+	if err != nil {
+		return errors.Wrapf(err, "could not delete from %s", t.name)
+	}
+	return t.expectVersionAffected(ctx, result, key)
+}
+
+// restore undoes the soft delete of the entity stored under key, and bumps its version
+func (t jsonTable[T]) restore(ctx context.Context, key string) error {
+	result, err := t.db.ExecContext(ctx, fmt.Sprintf(`UPDATE %s SET data = json_set(json_remove(data, '$.deletedAt'), '$.version', %s + 1)
+		WHERE %s = ? AND %s IS NOT NULL`, t.name, versionExpr, t.keyColumn, deletedAtExpr), key)
+	if err != nil {
+		return errors.Wrapf(err, "could not restore %s", t.name)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "could not count affected rows")
+	} else if affected == 0 {
+		return notDeleted(t.name, key)
+	}
+	return nil
+}
+
+// purgeDeleted permanently removes the entities soft deleted before deletedBefore. Deletion times are compared as
+// times rather than as text, since RFC 3339 timestamps with fractional seconds do not sort lexically.
+func (t jsonTable[T]) purgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	result, err := t.db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE julianday(%s) < julianday(?)`, t.name, deletedAtExpr),
+		deletedBefore.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return 0, errors.Wrapf(err, "could not purge %s", t.name)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "could not count affected rows")
+	}
+	return int(purged), nil
+}
+
+// expectVersionAffected tells apart a missing entity from a stale version when a versioned write affected no rows
+func (t jsonTable[T]) expectVersionAffected(ctx context.Context, result sql.Result, key string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "could not count affected rows")
+	} else if affected > 0 {
+		return nil
+	}
+	rows, err := t.db.QueryContext(ctx, fmt.Sprintf(`SELECT %s FROM %s WHERE %s = ? AND %s`, versionExpr, t.name, t.keyColumn,
+		t.liveCondition()), key)
+	if err != nil {
+		return errors.Wrapf(err, "could not query %s version", t.name)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return errors.Wrapf(err, "could not query %s version", t.name)
+		}
+		return errors.Errorf("%s %s not found", t.name, key)
+	}
+	var storedVersion int
+	if err := rows.Scan(&storedVersion); err != nil {
+		return errors.Wrapf(err, "could not scan %s version", t.name)
+	}
+	return versionConflict(t.name, key, storedVersion)
+}
+
+func (t jsonTable[T]) expectAffected(result sql.Result, key string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "could not count affected rows")
+	} else if affected == 0 {
+		return errors.Errorf("%s %s not found", t.name, key)
+	}
+	return nil
+}
+
+func (t jsonTable[T]) scan(rows *sql.Rows) ([]T, error) {
+	defer rows.Close()
+	entities := make([]T, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, errors.Wrapf(err, "could not scan %s row", t.name)
+		}
+		var entity T
+		if err := json.Unmarshal([]byte(data), &entity); err != nil {
+			return nil, errors.Wrapf(err, "could not unmarshal %s row", t.name)
+		}
+		entities = append(entities, entity)
+	}
+	return entities, errors.Wrapf(rows.Err(), "could not iterate %s rows", t.name)
+}
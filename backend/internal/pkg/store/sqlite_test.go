@@ -0,0 +1,308 @@
+package store_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testShiftModel = models.Shift{
+	ID:        "123",
+	Name:      "Test Shift",
+	Type:      models.MotorizedPatrolShiftType,
+	StartTime: time.Date(2025, time.April, 9, 15, 0, 0, 0, time.UTC),
+	EndTime:   time.Date(2025, time.April, 9, 16, 0, 0, 0, time.UTC),
+	Commander: testSoldier,
+	Version:   1,
+}
+
+func openTestSQLiteDB(t *testing.T) (db *sql.DB, path string) {
+	path = filepath.Join(t.TempDir(), "test.db")
+	db, err := store.OpenSQLiteDB(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db, path
+}
+
+func TestOpenSQLiteDB__data_survives_reopening(t *testing.T) {
+	// Arrange
+	db, path := openTestSQLiteDB(t)
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// Act
+	reopenedDB, err := store.OpenSQLiteDB(path)
+	require.NoError(t, err)
+	defer reopenedDB.Close()
+	reopenedShiftStore, err := store.NewSQLiteShiftStore(reopenedDB)
+	require.NoError(t, err)
+	foundShifts, err := reopenedShiftStore.FindShiftByID(context.Background(), testShiftModel.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Shift{testShiftModel.WithSoldierRefs()}, foundShifts)
+}
+
+func TestNewSQLiteShiftStore__nil_db(t *testing.T) {
+	// Act
+	shiftStore, err := store.NewSQLiteShiftStore(nil)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, shiftStore)
+}
+
+func TestSQLiteShiftStore_CreateNewShift__duplicate_id(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+
+	// Act
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestSQLiteShiftStore_UpdateShift__success(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+	updatedShift := testShiftModel
+	updatedShift.Name = "Updated Shift"
+
+	// Act
+	err = shiftStore.UpdateShift(context.Background(), updatedShift)
+
+	// Assert
+	assert.NoError(t, err)
+	foundShifts, err := shiftStore.FindAllShifts(context.Background())
+	assert.NoError(t, err)
+	updatedShift.Version = 2
+	assert.Equal(t, []models.Shift{updatedShift.WithSoldierRefs()}, foundShifts)
+}
+
+func TestSQLiteShiftStore_UpdateShift__not_found(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+
+	// Act
+	err = shiftStore.UpdateShift(context.Background(), testShiftModel)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestSQLiteShiftStore_DeleteShift__success(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+
+	// Act
+	err = shiftStore.DeleteShift(context.Background(), testShiftModel.ID, 1)
+
+	// Assert
+	assert.NoError(t, err)
+	foundShifts, err := shiftStore.FindShiftByID(context.Background(), testShiftModel.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, foundShifts)
+	assert.Error(t, shiftStore.DeleteShift(context.Background(), testShiftModel.ID, 1))
+}
+
+func TestSQLiteShiftStore_UpdateShift__stale_version(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+	err = shiftStore.UpdateShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+	staleShift := testShiftModel
+	staleShift.Name = "Stale Shift"
+
+	// Act
+	err = shiftStore.UpdateShift(context.Background(), staleShift)
+
+	// Assert
+	assert.ErrorIs(t, err, store.ErrVersionConflict)
+	foundShifts, err := shiftStore.FindShiftByID(context.Background(), testShiftModel.ID)
+	assert.NoError(t, err)
+	require.Len(t, foundShifts, 1)
+	assert.Equal(t, testShiftModel.Name, foundShifts[0].Name)
+	assert.Equal(t, 2, foundShifts[0].Version)
+}
+
+func TestSQLiteShiftStore_DeleteShift__stale_version(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+
+	// Act
+	err = shiftStore.DeleteShift(context.Background(), testShiftModel.ID, 2)
+
+	// Assert
+	assert.ErrorIs(t, err, store.ErrVersionConflict)
+	foundShifts, err := shiftStore.FindShiftByID(context.Background(), testShiftModel.ID)
+	assert.NoError(t, err)
+	assert.Len(t, foundShifts, 1)
+}
+
+func TestSQLiteShiftStore_UpdateShift__unversioned_row(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	// Rows written before entities were versioned have no version field
+	_, err = db.Exec(`INSERT INTO shifts (id, data) VALUES (?, ?)`, testShiftModel.ID, `{"id":"123","name":"Test Shift"}`)
+	require.NoError(t, err)
+	unversionedShift := testShiftModel
+	unversionedShift.Version = 0
+
+	// Act
+	err = shiftStore.UpdateShift(context.Background(), unversionedShift)
+
+	// Assert
+	assert.NoError(t, err)
+	foundShifts, err := shiftStore.FindShiftByID(context.Background(), testShiftModel.ID)
+	assert.NoError(t, err)
+	require.Len(t, foundShifts, 1)
+	assert.Equal(t, 1, foundShifts[0].Version)
+}
+
+func TestSQLiteDaySchedStore_CreateNewDaySchedule__existing_date(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	dayStore, err := store.NewSQLiteDaySchedStore(db)
+	require.NoError(t, err)
+	day := models.DaySchedule{Date: time.Date(2025, time.April, 9, 0, 0, 0, 0, time.UTC), Shifts: []models.Shift{testShiftModel}}
+	err = dayStore.CreateNewDaySchedule(context.Background(), day)
+	require.NoError(t, err)
+	otherShift := testShiftModel
+	otherShift.ID = "456"
+	sameDateDay := models.DaySchedule{Date: day.Date.Add(12 * time.Hour), Shifts: []models.Shift{otherShift}}
+
+	// Act
+	err = dayStore.CreateNewDaySchedule(context.Background(), sameDateDay)
+
+	// Assert
+	assert.ErrorIs(t, err, store.ErrAlreadyExists)
+	foundDays, err := dayStore.FindDaySchedule(context.Background(), day.Date)
+	assert.NoError(t, err)
+	day.Version = 1
+	assert.Equal(t, []models.DaySchedule{day.WithSoldierRefs()}, foundDays)
+}
+
+func TestSQLiteUserStore_FindUserByUsername__success(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	userStore, err := store.NewSQLiteUserStore(db)
+	require.NoError(t, err)
+	user := models.User{Username: "gal_tfilin", HashedPassword: []byte("hashed-password"), SoldierID: testSoldier.ID,
+		Role: models.SoldierUserRole}
+	err = userStore.CreateNewUser(context.Background(), user)
+	require.NoError(t, err)
+
+	// Act
+	foundUsers, err := userStore.FindUserByUsername(context.Background(), user.Username)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []models.User{user}, foundUsers)
+	assert.Error(t, userStore.CreateNewUser(context.Background(), user))
+}
+
+func TestSQLiteLeaveStore_FindLeavesBySoldierID__success(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	leaveStore, err := store.NewSQLiteLeaveStore(db)
+	require.NoError(t, err)
+	otherSoldierLeave := testLeave
+	otherSoldierLeave.ID = "other-leave"
+	otherSoldierLeave.SoldierID = "other-soldier"
+	require.NoError(t, leaveStore.CreateNewLeave(context.Background(), testLeave))
+	require.NoError(t, leaveStore.CreateNewLeave(context.Background(), otherSoldierLeave))
+
+	// Act
+	leaves, err := leaveStore.FindLeavesBySoldierID(context.Background(), testSoldier.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Leave{testLeave}, leaves)
+}
+
+func TestSQLiteShiftStore_RestoreShift__success(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+	err = shiftStore.DeleteShift(context.Background(), testShiftModel.ID, 1)
+	require.NoError(t, err)
+
+	// Act
+	err = shiftStore.RestoreShift(context.Background(), testShiftModel.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	foundShifts, err := shiftStore.FindAllShifts(context.Background())
+	assert.NoError(t, err)
+	require.Len(t, foundShifts, 1)
+	assert.Nil(t, foundShifts[0].DeletedAt)
+	assert.Equal(t, 3, foundShifts[0].Version)
+	assert.ErrorIs(t, shiftStore.RestoreShift(context.Background(), testShiftModel.ID), store.ErrNotDeleted)
+}
+
+func TestSQLiteShiftStore_PurgeDeletedShifts__success(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	err = shiftStore.CreateNewShift(context.Background(), testShiftModel)
+	require.NoError(t, err)
+	liveShift := testShiftModel
+	liveShift.ID = "456"
+	err = shiftStore.CreateNewShift(context.Background(), liveShift)
+	require.NoError(t, err)
+	err = shiftStore.DeleteShift(context.Background(), testShiftModel.ID, 1)
+	require.NoError(t, err)
+
+	// Act
+	notYetPurged, notYetErr := shiftStore.PurgeDeletedShifts(context.Background(), time.Now().Add(-time.Hour))
+	purged, err := shiftStore.PurgeDeletedShifts(context.Background(), time.Now().Add(time.Hour))
+
+	// Assert
+	assert.NoError(t, notYetErr)
+	assert.Equal(t, 0, notYetPurged)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.ErrorIs(t, shiftStore.RestoreShift(context.Background(), testShiftModel.ID), store.ErrNotDeleted)
+	foundShifts, err := shiftStore.FindAllShifts(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, foundShifts, 1)
+}
@@ -0,0 +1,375 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"database/sql"
+	"maps"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TxStores are the stores a unit of work reads and writes through. Their writes take effect only once the unit of
+// work succeeds.
+type TxStores struct {
+	Days           IDayStore
+	Shifts         IShiftStore
+	Soldiers       ISoldierStore
+	ShiftTemplates IShiftTemplateStore
+	Users          IUserStore
+	Leaves         ILeaveStore
+	Rotations      IRotationStore
+	Audit          IAuditStore
+}
+
+// ITransactor runs units of work that write several entities, possibly across stores, atomically
+type ITransactor interface {
+	// WithinTx runs work against stores bound to a single transaction. If work returns an error none of its writes
+	// take effect, and the error is returned. work must only use the stores it is given, or it might deadlock.
+	WithinTx(ctx context.Context, work func(stores TxStores) error) error
+}
+
+// InMemTransactor runs units of work against copies of the in-memory stores they write to, and swaps the copies in on
+// success. All the stores stay locked while a unit of work runs, so units of work are fully isolated from other writes.
+type InMemTransactor struct {
+	stores InMemStores
+}
+
+func NewInMemTransactor(stores InMemStores) (*InMemTransactor, error) {
+	if !stores.complete() {
+		return nil, errors.New("all in-memory stores are required")
+	}
+	return &InMemTransactor{stores: stores}, nil
+}
+
+func (t *InMemTransactor) WithinTx(ctx context.Context, work func(stores TxStores) error) error {
+	t.stores.lockAll()
+	defer t.stores.unlockAll()
+
+	days := newTxDaySchedStore(t.stores.DaySchedules)
+	shifts := newTxShiftStore(t.stores.Shifts)
+	soldiers := newTxSoldierStore(t.stores.Soldiers)
+	shiftTemplates := newTxShiftTemplateStore(t.stores.ShiftTemplates)
+	users := newTxUserStore(t.stores.Users)
+	leaves := newTxLeaveStore(t.stores.Leaves)
+	rotations := newTxRotationStore(t.stores.Rotations)
+	// The audit log only grows, so the unit of work appends to it past the committed entries, which stay untouched
+	audit := &InMemAuditStore{entries: t.stores.Audit.entries}
+	if err := work(TxStores{
+		Days:           days,
+		Shifts:         shifts,
+		Soldiers:       soldiers,
+		ShiftTemplates: shiftTemplates,
+		Users:          users,
+		Leaves:         leaves,
+		Rotations:      rotations,
+		Audit:          audit,
+	}); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return errors.Wrap(err, "transaction context is done")
+	}
+
+	t.stores.DaySchedules.days = days.days
+	t.stores.Shifts.shifts = shifts.shifts
+	t.stores.Soldiers.soldiers = soldiers.soldiers
+	t.stores.ShiftTemplates.shiftTemplates = shiftTemplates.shiftTemplates
+	t.stores.Users.users = users.users
+	t.stores.Leaves.leaves = leaves.leaves
+	t.stores.Rotations.rotations = rotations.rotations
+	t.stores.Audit.entries = audit.entries
+	return nil
+}
+
+// The tx stores are the in-memory stores a unit of work runs against. They read the committed data until their first
+// write, which copies it, so a unit of work only copies the stores it writes to. The copies are swapped in on success.
+
+type txDaySchedStore struct {
+	*InMemDaySchedStore
+	copied bool
+}
+
+func newTxDaySchedStore(committed *InMemDaySchedStore) *txDaySchedStore {
+	return &txDaySchedStore{InMemDaySchedStore: &InMemDaySchedStore{days: committed.days}}
+}
+
+func (s *txDaySchedStore) copyOnWrite() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.copied {
+		s.days = maps.Clone(s.days)
+		s.copied = true
+	}
+}
+
+func (s *txDaySchedStore) CreateNewDaySchedule(ctx context.Context, day models.DaySchedule) error {
+	s.copyOnWrite()
+	return s.InMemDaySchedStore.CreateNewDaySchedule(ctx, day)
+}
+
+func (s *txDaySchedStore) UpdateDaySchedule(ctx context.Context, day models.DaySchedule) error {
+	s.copyOnWrite()
+	return s.InMemDaySchedStore.UpdateDaySchedule(ctx, day)
+}
+
+func (s *txDaySchedStore) DeleteDaySchedule(ctx context.Context, date time.Time, version int) error {
+	s.copyOnWrite()
+	return s.InMemDaySchedStore.DeleteDaySchedule(ctx, date, version)
+}
+
+type txShiftStore struct {
+	*InMemShiftStore
+	copied bool
+}
+
+func newTxShiftStore(committed *InMemShiftStore) *txShiftStore {
+	return &txShiftStore{InMemShiftStore: &InMemShiftStore{shifts: committed.shifts}}
+}
+
+func (s *txShiftStore) copyOnWrite() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.copied {
+		s.shifts = maps.Clone(s.shifts)
+		s.copied = true
+	}
+}
+
+func (s *txShiftStore) CreateNewShift(ctx context.Context, shift models.Shift) error {
+	s.copyOnWrite()
+	return s.InMemShiftStore.CreateNewShift(ctx, shift)
+}
+
+func (s *txShiftStore) UpdateShift(ctx context.Context, shift models.Shift) error {
+	s.copyOnWrite()
+	return s.InMemShiftStore.UpdateShift(ctx, shift)
+}
+
+func (s *txShiftStore) DeleteShift(ctx context.Context, id string, version int) error {
+	s.copyOnWrite()
+	return s.InMemShiftStore.DeleteShift(ctx, id, version)
+}
+
+func (s *txShiftStore) RestoreShift(ctx context.Context, id string) error {
+	s.copyOnWrite()
+	return s.InMemShiftStore.RestoreShift(ctx, id)
+}
+
+func (s *txShiftStore) PurgeDeletedShifts(ctx context.Context, deletedBefore time.Time) (int, error) {
+	s.copyOnWrite()
+	return s.InMemShiftStore.PurgeDeletedShifts(ctx, deletedBefore)
+}
+
+type txSoldierStore struct {
+	*InMemSoldierStore
+	copied bool
+}
+
+func newTxSoldierStore(committed *InMemSoldierStore) *txSoldierStore {
+	return &txSoldierStore{InMemSoldierStore: &InMemSoldierStore{soldiers: committed.soldiers}}
+}
+
+func (s *txSoldierStore) copyOnWrite() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.copied {
+		s.soldiers = maps.Clone(s.soldiers)
+		s.copied = true
+	}
+}
+
+func (s *txSoldierStore) CreateNewSoldier(ctx context.Context, soldier models.Soldier) error {
+	s.copyOnWrite()
+	return s.InMemSoldierStore.CreateNewSoldier(ctx, soldier)
+}
+
+func (s *txSoldierStore) UpdateSoldier(ctx context.Context, soldier models.Soldier) error {
+	s.copyOnWrite()
+	return s.InMemSoldierStore.UpdateSoldier(ctx, soldier)
+}
+
+func (s *txSoldierStore) DeleteSoldier(ctx context.Context, id string, version int) error {
+	s.copyOnWrite()
+	return s.InMemSoldierStore.DeleteSoldier(ctx, id, version)
+}
+
+func (s *txSoldierStore) RestoreSoldier(ctx context.Context, id string) error {
+	s.copyOnWrite()
+	return s.InMemSoldierStore.RestoreSoldier(ctx, id)
+}
+
+func (s *txSoldierStore) PurgeDeletedSoldiers(ctx context.Context, deletedBefore time.Time) (int, error) {
+	s.copyOnWrite()
+	return s.InMemSoldierStore.PurgeDeletedSoldiers(ctx, deletedBefore)
+}
+
+type txShiftTemplateStore struct {
+	*InMemShiftTemplateStore
+	copied bool
+}
+
+func newTxShiftTemplateStore(committed *InMemShiftTemplateStore) *txShiftTemplateStore {
+	return &txShiftTemplateStore{InMemShiftTemplateStore: &InMemShiftTemplateStore{shiftTemplates: committed.shiftTemplates}}
+}
+
+func (s *txShiftTemplateStore) copyOnWrite() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.copied {
+		s.shiftTemplates = maps.Clone(s.shiftTemplates)
+		s.copied = true
+	}
+}
+
+func (s *txShiftTemplateStore) CreateNewShiftTemplate(ctx context.Context, template models.ShiftTemplate) error {
+	s.copyOnWrite()
+	return s.InMemShiftTemplateStore.CreateNewShiftTemplate(ctx, template)
+}
+
+func (s *txShiftTemplateStore) UpdateShiftTemplate(ctx context.Context, template models.ShiftTemplate) error {
+	s.copyOnWrite()
+	return s.InMemShiftTemplateStore.UpdateShiftTemplate(ctx, template)
+}
+
+func (s *txShiftTemplateStore) DeleteShiftTemplate(ctx context.Context, id string, version int) error {
+	s.copyOnWrite()
+	return s.InMemShiftTemplateStore.DeleteShiftTemplate(ctx, id, version)
+}
+
+func (s *txShiftTemplateStore) RestoreShiftTemplate(ctx context.Context, id string) error {
+	s.copyOnWrite()
+	return s.InMemShiftTemplateStore.RestoreShiftTemplate(ctx, id)
+}
+
+func (s *txShiftTemplateStore) PurgeDeletedShiftTemplates(ctx context.Context, deletedBefore time.Time) (int, error) {
+	s.copyOnWrite()
+	return s.InMemShiftTemplateStore.PurgeDeletedShiftTemplates(ctx, deletedBefore)
+}
+
+type txUserStore struct {
+	*InMemUserStore
+	copied bool
+}
+
+func newTxUserStore(committed *InMemUserStore) *txUserStore {
+	return &txUserStore{InMemUserStore: &InMemUserStore{users: committed.users}}
+}
+
+func (s *txUserStore) copyOnWrite() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.copied {
+		s.users = maps.Clone(s.users)
+		s.copied = true
+	}
+}
+
+func (s *txUserStore) CreateNewUser(ctx context.Context, user models.User) error {
+	s.copyOnWrite()
+	return s.InMemUserStore.CreateNewUser(ctx, user)
+}
+
+type txLeaveStore struct {
+	*InMemLeaveStore
+	copied bool
+}
+
+func newTxLeaveStore(committed *InMemLeaveStore) *txLeaveStore {
+	return &txLeaveStore{InMemLeaveStore: &InMemLeaveStore{leaves: committed.leaves}}
+}
+
+func (s *txLeaveStore) copyOnWrite() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.copied {
+		s.leaves = maps.Clone(s.leaves)
+		s.copied = true
+	}
+}
+
+func (s *txLeaveStore) CreateNewLeave(ctx context.Context, leave models.Leave) error {
+	s.copyOnWrite()
+	return s.InMemLeaveStore.CreateNewLeave(ctx, leave)
+}
+
+func (s *txLeaveStore) UpdateLeave(ctx context.Context, leave models.Leave) error {
+	s.copyOnWrite()
+	return s.InMemLeaveStore.UpdateLeave(ctx, leave)
+}
+
+func (s *txLeaveStore) DeleteLeave(ctx context.Context, id string) error {
+	s.copyOnWrite()
+	return s.InMemLeaveStore.DeleteLeave(ctx, id)
+}
+
+type txRotationStore struct {
+	*InMemRotationStore
+	copied bool
+}
+
+func newTxRotationStore(committed *InMemRotationStore) *txRotationStore {
+	return &txRotationStore{InMemRotationStore: &InMemRotationStore{rotations: committed.rotations}}
+}
+
+func (s *txRotationStore) copyOnWrite() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.copied {
+		s.rotations = maps.Clone(s.rotations)
+		s.copied = true
+	}
+}
+
+func (s *txRotationStore) CreateNewRotation(ctx context.Context, rotation models.Rotation) error {
+	s.copyOnWrite()
+	return s.InMemRotationStore.CreateNewRotation(ctx, rotation)
+}
+
+func (s *txRotationStore) UpdateRotation(ctx context.Context, rotation models.Rotation) error {
+	s.copyOnWrite()
+	return s.InMemRotationStore.UpdateRotation(ctx, rotation)
+}
+
+func (s *txRotationStore) DeleteRotation(ctx context.Context, id string) error {
+	s.copyOnWrite()
+	return s.InMemRotationStore.DeleteRotation(ctx, id)
+}
+
+// SQLiteTransactor runs units of work within a single SQLite transaction
+type SQLiteTransactor struct {
+	db *sql.DB
+}
+
+func NewSQLiteTransactor(db *sql.DB) (*SQLiteTransactor, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return &SQLiteTransactor{db: db}, nil
+}
+
+func (t *SQLiteTransactor) WithinTx(ctx context.Context, work func(stores TxStores) error) error {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+	if err := work(newSQLiteTxStores(tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return errors.Wrap(tx.Commit(), "could not commit transaction")
+}
+
+func newSQLiteTxStores(tx *sql.Tx) TxStores {
+	return TxStores{
+		Days:           newSQLiteDaySchedStore(tx),
+		Shifts:         newSQLiteShiftStore(tx),
+		Soldiers:       newSQLiteSoldierStore(tx),
+		ShiftTemplates: newSQLiteShiftTemplateStore(tx),
+		Users:          newSQLiteUserStore(tx),
+		Leaves:         newSQLiteLeaveStore(tx),
+		Rotations:      newSQLiteRotationStore(tx),
+		Audit:          newSQLiteAuditStore(tx),
+	}
+}
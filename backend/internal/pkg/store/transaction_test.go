@@ -0,0 +1,155 @@
+package store_test
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"brothers_in_batash/internal/pkg/store"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createShiftAndDaySchedule writes a shift and the day schedule it belongs to, then fails if fail is set
+func createShiftAndDaySchedule(fail bool) func(stores store.TxStores) error {
+	return func(stores store.TxStores) error {
+		if err := stores.Shifts.CreateNewShift(context.Background(), testShiftModel); err != nil {
+			return err
+		}
+		day := models.DaySchedule{Date: time.Date(2025, time.April, 9, 0, 0, 0, 0, time.UTC), Shifts: []models.Shift{testShiftModel}}
+		if err := stores.Days.CreateNewDaySchedule(context.Background(), day); err != nil {
+			return err
+		}
+		if fail {
+			return errors.New("unit of work failed")
+		}
+		return nil
+	}
+}
+
+func TestInMemTransactor_WithinTx__commits_on_success(t *testing.T) {
+	// Arrange
+	stores := newTestInMemStores(t)
+	transactor, err := store.NewInMemTransactor(stores)
+	require.NoError(t, err)
+
+	// Act
+	err = transactor.WithinTx(context.Background(), createShiftAndDaySchedule(false))
+
+	// Assert
+	assert.NoError(t, err)
+	shifts, err := stores.Shifts.FindAllShifts(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, shifts, 1)
+	days, err := stores.DaySchedules.FindAllDaySchedules(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, days, 1)
+}
+
+func TestInMemTransactor_WithinTx__rolls_back_on_error(t *testing.T) {
+	// Arrange
+	stores := newTestInMemStores(t)
+	transactor, err := store.NewInMemTransactor(stores)
+	require.NoError(t, err)
+
+	// Act
+	err = transactor.WithinTx(context.Background(), createShiftAndDaySchedule(true))
+
+	// Assert
+	assert.Error(t, err)
+	shifts, err := stores.Shifts.FindAllShifts(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, shifts)
+	days, err := stores.DaySchedules.FindAllDaySchedules(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, days)
+}
+
+func TestInMemTransactor_WithinTx__rolls_back_writes_to_existing_data(t *testing.T) {
+	// Arrange
+	stores := newTestInMemStores(t)
+	require.NoError(t, stores.Shifts.CreateNewShift(context.Background(), testShiftModel))
+	transactor, err := store.NewInMemTransactor(stores)
+	require.NoError(t, err)
+	renamedShift := testShiftModel
+	renamedShift.Name = "Renamed Shift"
+	entry := models.AuditEntry{EntityType: models.ShiftAuditEntity, EntityID: testShiftModel.ID, Action: models.UpdateAuditAction}
+
+	// Act
+	err = transactor.WithinTx(context.Background(), func(txStores store.TxStores) error {
+		if err := txStores.Shifts.UpdateShift(context.Background(), renamedShift); err != nil {
+			return err
+		}
+		if err := txStores.Audit.AddAuditEntry(context.Background(), entry); err != nil {
+			return err
+		}
+		return errors.New("unit of work failed")
+	})
+
+	// Assert
+	assert.Error(t, err)
+	shifts, err := stores.Shifts.FindShiftByID(context.Background(), testShiftModel.ID)
+	assert.NoError(t, err)
+	require.Len(t, shifts, 1)
+	assert.Equal(t, testShiftModel.Name, shifts[0].Name)
+	entries, err := stores.Audit.FindAuditEntries(context.Background(), models.ShiftAuditEntity, testShiftModel.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestNewInMemTransactor__missing_store(t *testing.T) {
+	// Act
+	transactor, err := store.NewInMemTransactor(store.InMemStores{})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, transactor)
+}
+
+func TestSQLiteTransactor_WithinTx__commits_on_success(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	transactor, err := store.NewSQLiteTransactor(db)
+	require.NoError(t, err)
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	dayStore, err := store.NewSQLiteDaySchedStore(db)
+	require.NoError(t, err)
+
+	// Act
+	err = transactor.WithinTx(context.Background(), createShiftAndDaySchedule(false))
+
+	// Assert
+	assert.NoError(t, err)
+	shifts, err := shiftStore.FindAllShifts(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, shifts, 1)
+	days, err := dayStore.FindAllDaySchedules(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, days, 1)
+}
+
+func TestSQLiteTransactor_WithinTx__rolls_back_on_error(t *testing.T) {
+	// Arrange
+	db, _ := openTestSQLiteDB(t)
+	transactor, err := store.NewSQLiteTransactor(db)
+	require.NoError(t, err)
+	shiftStore, err := store.NewSQLiteShiftStore(db)
+	require.NoError(t, err)
+	dayStore, err := store.NewSQLiteDaySchedStore(db)
+	require.NoError(t, err)
+
+	// Act
+	err = transactor.WithinTx(context.Background(), createShiftAndDaySchedule(true))
+
+	// Assert
+	assert.Error(t, err)
+	shifts, err := shiftStore.FindAllShifts(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, shifts)
+	days, err := dayStore.FindAllDaySchedules(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, days)
+}
@@ -2,19 +2,20 @@ package store
 
 import (
 	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"sync"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/pkg/errors"
 )
 
-//TODO - accept ctx in signatures
-
 type IUserStore interface {
-	CreateNewUser(user models.User) error
-	FindUserByUsername(username string) ([]models.User, error)
+	CreateNewUser(ctx context.Context, user models.User) error
+	FindUserByUsername(ctx context.Context, username string) ([]models.User, error)
 }
 
 type InMemUserStore struct {
+	mu    sync.RWMutex
 	users map[string]models.User
 }
 
@@ -22,7 +23,9 @@ func NewUserStore() (*InMemUserStore, error) {
 	return &InMemUserStore{users: make(map[string]models.User)}, nil
 }
 
-func (us *InMemUserStore) CreateNewUser(user models.User) error {
+func (us *InMemUserStore) CreateNewUser(ctx context.Context, user models.User) error {
+	us.mu.Lock()
+	defer us.mu.Unlock()
 	if err := validator.New().Struct(user); err != nil {
 		return errors.Wrap(err, "user validation failed")
 	}
@@ -33,7 +36,9 @@ func (us *InMemUserStore) CreateNewUser(user models.User) error {
 	return nil
 }
 
-func (us *InMemUserStore) FindUserByUsername(username string) ([]models.User, error) {
+func (us *InMemUserStore) FindUserByUsername(ctx context.Context, username string) ([]models.User, error) {
+	us.mu.RLock()
+	defer us.mu.RUnlock()
 	if res, exists := us.users[username]; !exists {
 		return []models.User{}, nil
 	} else {
@@ -0,0 +1,36 @@
+package store
+
+import (
+	"brothers_in_batash/internal/pkg/models"
+	"context"
+	"database/sql"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+)
+
+type SQLiteUserStore struct {
+	users jsonTable[models.User]
+}
+
+func NewSQLiteUserStore(db *sql.DB) (*SQLiteUserStore, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return newSQLiteUserStore(db), nil
+}
+
+func newSQLiteUserStore(db sqlExecutor) *SQLiteUserStore {
+	return &SQLiteUserStore{users: jsonTable[models.User]{db: db, name: "users", keyColumn: "username"}}
+}
+
+func (us *SQLiteUserStore) CreateNewUser(ctx context.Context, user models.User) error {
+	if err := validator.New().Struct(user); err != nil {
+		return errors.Wrap(err, "user validation failed")
+	}
+	return us.users.insert(ctx, user.Username, user)
+}
+
+func (us *SQLiteUserStore) FindUserByUsername(ctx context.Context, username string) ([]models.User, error) {
+	return us.users.find(ctx, username)
+}
@@ -0,0 +1,12 @@
+package store
+
+import "github.com/pkg/errors"
+
+// ErrVersionConflict is returned when updating or deleting a versioned entity - a Shift, Soldier, ShiftTemplate or
+// DaySchedule - with a version other than the stored one, meaning it was changed since the caller has read it.
+// Creating an entity sets its version to 1, and every successful update bumps it.
+var ErrVersionConflict = errors.New("entity was modified by someone else")
+
+func versionConflict(entity, key string, storedVersion int) error {
+	return errors.Wrapf(ErrVersionConflict, "%s %s is at version %d", entity, key, storedVersion)
+}
@@ -1,7 +1,23 @@
 package test_utils
 
-import "github.com/gofiber/fiber/v2"
+import (
+	jwtmw "brothers_in_batash/internal/pkg/middleware/jwt"
+	"brothers_in_batash/internal/pkg/models"
 
+	"github.com/gofiber/fiber/v2"
+)
+
+// AlwaysAllowedJWTMiddleware authenticates every request as a platoon admin, who is allowed every route
 func AlwaysAllowedJWTMiddleware(ctx *fiber.Ctx) error {
+	ctx.Locals(jwtmw.RoleLocalsKey, models.PlatoonAdminUserRole)
 	return ctx.Next()
 }
+
+// NewRoleJWTMiddleware authenticates every request as a user with role, whose soldier is soldierID
+func NewRoleJWTMiddleware(role models.UserRole, soldierID string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		ctx.Locals(jwtmw.RoleLocalsKey, role)
+		ctx.Locals(jwtmw.SoldierIDLocalsKey, soldierID)
+		return ctx.Next()
+	}
+}
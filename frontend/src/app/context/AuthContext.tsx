@@ -103,6 +103,16 @@ export function AuthProvider({children}: { children: ReactNode }) {
     }
 
     const logout = () => {
+        // Revoke the tokens on the server, so they stop working even if copied off this device
+        if (token) {
+            fetch('/api/auth/logout', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                },
+                body: JSON.stringify({token, refreshToken}),
+            }).catch((err) => logger.error('Logout request failed:', err))
+        }
         setUser(null)
         setToken(null)
         setRefreshToken(null)